@@ -0,0 +1,406 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// The Build* methods compile an operation without signing it. Request signers are only asked for
+// their public key, so signer.WatchOnly can stand in for keys held by a wallet app, a hardware
+// device or another approver. Collect signatures with PartialSign or AddSignature and broadcast
+// with Submit.
+
+// BuildTransferSOL prepares an unsigned SOL transfer
+func (c *Client) BuildTransferSOL(ctx context.Context, req models.TransferSOLRequest) (*models.PreparedTransaction, error) {
+	op, err := c.transferSOL(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildCreateATA prepares an unsigned idempotent ATA creation and returns the ATA address
+func (c *Client) BuildCreateATA(ctx context.Context, req models.CreateATARequest) (*models.PreparedTransaction, string, error) {
+	op, ata, err := c.createATA(ctx, req, true)
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := c.build(ctx, op)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, ata, nil
+}
+
+// BuildTransferTokenChecked prepares an unsigned TransferChecked
+func (c *Client) BuildTransferTokenChecked(ctx context.Context, req models.TransferTokenCheckedRequest) (*models.PreparedTransaction, error) {
+	op, err := c.transferTokenChecked(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildMintTo prepares an unsigned MintTo
+func (c *Client) BuildMintTo(ctx context.Context, req models.MintToRequest) (*models.PreparedTransaction, error) {
+	op, err := c.mintTo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildCreateMint prepares a mint creation and returns the new mint address.
+// The transaction comes signed by the generated mint account and awaits the payer.
+func (c *Client) BuildCreateMint(ctx context.Context, req models.CreateMintRequest) (*models.PreparedTransaction, string, error) {
+	op, mint, err := c.createMint(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := c.build(ctx, op)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, mint, nil
+}
+
+// BuildCreateTokenMetadata prepares an unsigned Metaplex metadata creation and returns the
+// metadata address
+func (c *Client) BuildCreateTokenMetadata(ctx context.Context, req models.CreateTokenMetadataRequest) (*models.PreparedTransaction, string, error) {
+	op, address, err := c.createTokenMetadata(req)
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := c.build(ctx, op)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, address, nil
+}
+
+// BuildSetTokenMetadata prepares an unsigned Metaplex metadata update
+func (c *Client) BuildSetTokenMetadata(ctx context.Context, req models.SetTokenMetadataRequest) (*models.PreparedTransaction, error) {
+	op, err := c.setTokenMetadata(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildMintNFT prepares an NFT mint and returns the mint address. The transaction comes signed
+// by the generated mint account and awaits the payer.
+func (c *Client) BuildMintNFT(ctx context.Context, req models.MintNFTRequest) (*models.PreparedTransaction, string, error) {
+	op, mint, err := c.mintNFT(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := c.build(ctx, op)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, mint, nil
+}
+
+// BuildTransferNFT prepares an unsigned NFT transfer
+func (c *Client) BuildTransferNFT(ctx context.Context, req models.TransferNFTRequest) (*models.PreparedTransaction, error) {
+	op, err := c.transferNFT(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildCreateMultisig prepares a multisig creation and returns the multisig address.
+// The transaction comes signed by the generated multisig account and awaits the payer.
+func (c *Client) BuildCreateMultisig(ctx context.Context, req models.CreateMultisigRequest) (*models.PreparedTransaction, string, error) {
+	op, address, err := c.createMultisig(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := c.build(ctx, op)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, address, nil
+}
+
+// BuildBurn prepares an unsigned Burn, or BurnChecked when the request carries decimals
+func (c *Client) BuildBurn(ctx context.Context, req models.BurnRequest) (*models.PreparedTransaction, error) {
+	op, err := c.burn(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildApproveChecked prepares an unsigned ApproveChecked
+func (c *Client) BuildApproveChecked(ctx context.Context, req models.ApproveRequest) (*models.PreparedTransaction, error) {
+	op, err := c.approveChecked(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildRevoke prepares an unsigned Revoke
+func (c *Client) BuildRevoke(ctx context.Context, req models.RevokeRequest) (*models.PreparedTransaction, error) {
+	op, err := c.revoke(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildFreezeAccount prepares an unsigned FreezeAccount
+func (c *Client) BuildFreezeAccount(ctx context.Context, req models.FreezeAccountRequest) (*models.PreparedTransaction, error) {
+	op, err := c.setFrozen(ctx, req.SendOptions, req.FreezeAuthority, req.Multisig, req.Account, req.Mint, tokenInstructionFreezeAccount)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildThawAccount prepares an unsigned ThawAccount
+func (c *Client) BuildThawAccount(ctx context.Context, req models.ThawAccountRequest) (*models.PreparedTransaction, error) {
+	op, err := c.setFrozen(ctx, req.SendOptions, req.FreezeAuthority, req.Multisig, req.Account, req.Mint, tokenInstructionThawAccount)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildCloseAccount prepares an unsigned CloseAccount
+func (c *Client) BuildCloseAccount(ctx context.Context, req models.CloseAccountRequest) (*models.PreparedTransaction, error) {
+	op, err := c.closeAccount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildSetAuthority prepares an unsigned SetAuthority
+func (c *Client) BuildSetAuthority(ctx context.Context, req models.SetAuthorityRequest) (*models.PreparedTransaction, error) {
+	op, err := c.setAuthority(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildCreateStakeAccount prepares a stake account creation and returns the account address.
+// Without a seed the transaction comes signed by the generated stake account and awaits the payer.
+func (c *Client) BuildCreateStakeAccount(ctx context.Context, req models.CreateStakeAccountRequest) (*models.PreparedTransaction, string, error) {
+	op, address, err := c.createStakeAccount(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := c.build(ctx, op)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, address, nil
+}
+
+// BuildDelegateStake prepares an unsigned DelegateStake
+func (c *Client) BuildDelegateStake(ctx context.Context, req models.DelegateStakeRequest) (*models.PreparedTransaction, error) {
+	op, err := c.delegateStake(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildDeactivateStake prepares an unsigned Deactivate
+func (c *Client) BuildDeactivateStake(ctx context.Context, req models.DeactivateStakeRequest) (*models.PreparedTransaction, error) {
+	op, err := c.deactivateStake(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildWithdrawStake prepares an unsigned stake Withdraw
+func (c *Client) BuildWithdrawStake(ctx context.Context, req models.WithdrawStakeRequest) (*models.PreparedTransaction, error) {
+	op, err := c.withdrawStake(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildSplitStake prepares a stake split and returns the new account address. Without a seed
+// the transaction comes signed by the generated account and awaits the staker.
+func (c *Client) BuildSplitStake(ctx context.Context, req models.SplitStakeRequest) (*models.PreparedTransaction, string, error) {
+	op, address, err := c.splitStake(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := c.build(ctx, op)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, address, nil
+}
+
+// BuildMergeStake prepares an unsigned stake Merge
+func (c *Client) BuildMergeStake(ctx context.Context, req models.MergeStakeRequest) (*models.PreparedTransaction, error) {
+	op, err := c.mergeStake(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// BuildCreateLookupTable prepares an unsigned lookup table creation and returns the table address
+func (c *Client) BuildCreateLookupTable(ctx context.Context, req models.CreateLookupTableRequest) (*models.PreparedTransaction, string, error) {
+	op, table, err := c.createLookupTable(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	tx, err := c.build(ctx, op)
+	if err != nil {
+		return nil, "", err
+	}
+	return tx, table, nil
+}
+
+// BuildExtendLookupTable prepares an unsigned lookup table extension
+func (c *Client) BuildExtendLookupTable(ctx context.Context, req models.ExtendLookupTableRequest) (*models.PreparedTransaction, error) {
+	op, err := c.extendLookupTable(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.build(ctx, op)
+}
+
+// PartialSign adds signatures from the given signers; each must be a required signer of the transaction
+func (c *Client) PartialSign(ctx context.Context, req models.PartialSignRequest) (*models.PreparedTransaction, error) {
+	tx, err := decodePrepared(req.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range req.Signers {
+		if s == nil {
+			return nil, fmt.Errorf("missing signer")
+		}
+		if signerIndex(tx, s.PublicKey()) < 0 {
+			return nil, fmt.Errorf("%s is not a signer of this transaction", s.PublicKey())
+		}
+	}
+	if err := signWith(ctx, &tx, req.Signers...); err != nil {
+		return nil, err
+	}
+	return preparedTransaction(tx, req.Transaction.LastValidBlockHeight)
+}
+
+// AddSignature attaches a signature produced elsewhere after verifying it against the message
+func (c *Client) AddSignature(req models.AddSignatureRequest) (*models.PreparedTransaction, error) {
+	tx, err := decodePrepared(req.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	i := signerIndex(tx, req.PublicKey)
+	if i < 0 {
+		return nil, fmt.Errorf("%s is not a signer of this transaction", req.PublicKey)
+	}
+	sig, err := base58.Decode(req.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	// AddSignature verifies the signature and places it in the signer's slot
+	if err := tx.AddSignature(sig); err != nil {
+		return nil, fmt.Errorf("signature does not belong to %s: %w", req.PublicKey, err)
+	}
+	if !bytes.Equal(tx.Signatures[i], sig) {
+		return nil, fmt.Errorf("signature does not belong to %s", req.PublicKey)
+	}
+	return preparedTransaction(tx, req.Transaction.LastValidBlockHeight)
+}
+
+// Submit broadcasts a fully signed transaction. Commitment is honoured; the compute budget and
+// nonce options only apply when building.
+func (c *Client) Submit(ctx context.Context, req models.SubmitRequest) (string, error) {
+	tx, err := decodePrepared(req.Transaction)
+	if err != nil {
+		return "", err
+	}
+	if missing := missingSigners(tx); len(missing) > 0 {
+		return "", fmt.Errorf("transaction is missing signatures from %v", missing)
+	}
+	return c.send(ctx, tx, req.Transaction.LastValidBlockHeight, req.SendOptions)
+}
+
+func decodePrepared(p models.PreparedTransaction) (types.Transaction, error) {
+	raw, err := base64.StdEncoding.DecodeString(p.Transaction)
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("invalid transaction encoding: %w", err)
+	}
+	tx, err := types.TransactionDeserialize(raw)
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("invalid transaction: %w", err)
+	}
+	// the transaction may come from another party, so its header is checked before it indexes anything
+	header := tx.Message.Header
+	signers := int(header.NumRequireSignatures)
+	switch {
+	case signers == 0 || signers > len(tx.Message.Accounts):
+		return types.Transaction{}, fmt.Errorf("invalid transaction: %d required signatures for %d accounts", signers, len(tx.Message.Accounts))
+	case int(header.NumReadonlySignedAccounts) >= signers || int(header.NumReadonlyUnsignedAccounts) > len(tx.Message.Accounts)-signers:
+		return types.Transaction{}, fmt.Errorf("invalid transaction: readonly account counts do not match the header")
+	case len(tx.Signatures) != signers:
+		return types.Transaction{}, fmt.Errorf("invalid transaction: %d signatures for %d required signers", len(tx.Signatures), signers)
+	}
+	for _, sig := range tx.Signatures {
+		if len(sig) != 64 {
+			return types.Transaction{}, fmt.Errorf("invalid transaction: signature of %d bytes", len(sig))
+		}
+	}
+	return tx, nil
+}
+
+func preparedTransaction(tx types.Transaction, lastValidBlockHeight uint64) (*models.PreparedTransaction, error) {
+	raw, err := tx.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := tx.Message.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	p := &models.PreparedTransaction{
+		Transaction:          base64.StdEncoding.EncodeToString(raw),
+		Message:              base64.StdEncoding.EncodeToString(msg),
+		Missing:              missingSigners(tx),
+		Blockhash:            tx.Message.RecentBlockHash,
+		LastValidBlockHeight: lastValidBlockHeight,
+	}
+	for _, key := range tx.Message.Accounts[:tx.Message.Header.NumRequireSignatures] {
+		p.Signers = append(p.Signers, key.ToBase58())
+	}
+	return p, nil
+}
+
+func signerIndex(tx types.Transaction, publicKey string) int {
+	for i, key := range tx.Message.Accounts[:tx.Message.Header.NumRequireSignatures] {
+		if key.ToBase58() == publicKey {
+			return i
+		}
+	}
+	return -1
+}
+
+func missingSigners(tx types.Transaction) []string {
+	var missing []string
+	for i, sig := range tx.Signatures {
+		if bytes.Equal(sig, make([]byte, 64)) {
+			missing = append(missing, tx.Message.Accounts[i].ToBase58())
+		}
+	}
+	return missing
+}
@@ -0,0 +1,186 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
+)
+
+func TestBuilder_MultiPartyDurableTransfer(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	founder := c.CreateAccount()
+	member := c.CreateAccount()
+	to := c.CreateAccount()
+	for _, acc := range []string{founder.PublicKey, member.PublicKey} {
+		if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: acc, Lamports: 100_000_000}); err != nil {
+			t.Fatalf("airdrop failed: %v", err)
+		}
+	}
+	nonce, _, err := c.CreateNonceAccount(ctx, models.CreateNonceAccountRequest{SendOptions: confirmed, Payer: mustSigner(t, founder)})
+	if err != nil {
+		t.Fatalf("create nonce account failed: %v", err)
+	}
+
+	// the member pays and sends; the founder approves by signing the nonce advance
+	prepared, err := c.BuildTransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: models.SendOptions{Nonce: &models.DurableNonce{Account: nonce, Authority: signer.NewWatchOnly(founder.PublicKey)}},
+		From:        signer.NewWatchOnly(member.PublicKey),
+		ToPublicKey: to.PublicKey,
+		Lamports:    5_000_000,
+	})
+	if err != nil {
+		t.Fatalf("build transfer failed: %v", err)
+	}
+	if len(prepared.Signers) != 2 || prepared.Signers[0] != member.PublicKey || len(prepared.Missing) != 2 {
+		t.Fatalf("unexpected prepared transaction: %+v", prepared)
+	}
+
+	if _, err := c.Submit(ctx, models.SubmitRequest{Transaction: *prepared}); err == nil {
+		t.Fatalf("expected unsigned submit to fail")
+	}
+	if _, err := c.PartialSign(ctx, models.PartialSignRequest{Transaction: *prepared, Signers: []models.Signer{mustSigner(t, to)}}); err == nil {
+		t.Fatalf("expected signing by a non-signer to fail")
+	}
+
+	approved, err := c.PartialSign(ctx, models.PartialSignRequest{Transaction: *prepared, Signers: []models.Signer{mustSigner(t, founder)}})
+	if err != nil {
+		t.Fatalf("partial sign failed: %v", err)
+	}
+	if len(approved.Missing) != 1 || approved.Missing[0] != member.PublicKey {
+		t.Fatalf("unexpected missing signers: %v", approved.Missing)
+	}
+
+	// the member signs much later, in a wallet that only sees the message
+	srv.AdvanceSlots(300)
+	msg, err := base64.StdEncoding.DecodeString(approved.Message)
+	if err != nil {
+		t.Fatalf("decode message failed: %v", err)
+	}
+	sig, err := mustSigner(t, member).Sign(msg)
+	if err != nil {
+		t.Fatalf("wallet sign failed: %v", err)
+	}
+	if _, err := c.AddSignature(models.AddSignatureRequest{Transaction: *approved, PublicKey: founder.PublicKey, Signature: base58.Encode(sig)}); err == nil {
+		t.Fatalf("expected a signature under the wrong key to be rejected")
+	}
+	signed, err := c.AddSignature(models.AddSignatureRequest{Transaction: *approved, PublicKey: member.PublicKey, Signature: base58.Encode(sig)})
+	if err != nil {
+		t.Fatalf("add signature failed: %v", err)
+	}
+	if len(signed.Missing) != 0 {
+		t.Fatalf("unexpected missing signers: %v", signed.Missing)
+	}
+
+	if _, err := c.Submit(ctx, models.SubmitRequest{SendOptions: confirmed, Transaction: *signed}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	assertBalance(t, ctx, c, to.PublicKey, 5_000_000)
+}
+
+func TestBuilder_CreateMint(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	payer := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: payer.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	prepared, mint, err := c.BuildCreateMint(ctx, models.CreateMintRequest{Payer: signer.NewWatchOnly(payer.PublicKey), MintAuthority: payer.PublicKey, Decimals: 2})
+	if err != nil {
+		t.Fatalf("build create mint failed: %v", err)
+	}
+	// the generated mint account has already signed
+	if len(prepared.Signers) != 2 || len(prepared.Missing) != 1 || prepared.Missing[0] != payer.PublicKey {
+		t.Fatalf("unexpected prepared transaction: %+v", prepared)
+	}
+	signed, err := c.PartialSign(ctx, models.PartialSignRequest{Transaction: *prepared, Signers: []models.Signer{mustSigner(t, payer)}})
+	if err != nil {
+		t.Fatalf("partial sign failed: %v", err)
+	}
+	if _, err := c.Submit(ctx, models.SubmitRequest{SendOptions: confirmed, Transaction: *signed}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	info, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: mint})
+	if err != nil {
+		t.Fatalf("get mint info failed: %v", err)
+	}
+	if info.Decimals != 2 || info.MintAuthority != payer.PublicKey {
+		t.Fatalf("unexpected mint: %+v", info)
+	}
+
+	// the idempotent ATA builder can be signed and sent twice
+	owner := c.CreateAccount()
+	for i := 0; i < 2; i++ {
+		prepared, ata, err := c.BuildCreateATA(ctx, models.CreateATARequest{Payer: signer.NewWatchOnly(payer.PublicKey), Owner: owner.PublicKey, Mint: mint})
+		if err != nil {
+			t.Fatalf("build create ata failed: %v", err)
+		}
+		signed, err := c.PartialSign(ctx, models.PartialSignRequest{Transaction: *prepared, Signers: []models.Signer{mustSigner(t, payer)}})
+		if err != nil {
+			t.Fatalf("partial sign failed: %v", err)
+		}
+		if _, err := c.Submit(ctx, models.SubmitRequest{SendOptions: confirmed, Transaction: *signed}); err != nil {
+			t.Fatalf("submit %d failed: %v", i, err)
+		}
+		if ta, err := c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: ata}); err != nil || ta.Owner != owner.PublicKey {
+			t.Fatalf("unexpected ata %s: %+v, %v", ata, ta, err)
+		}
+	}
+}
+
+func TestBuilder_RejectsMalformedHeader(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	from := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	prepared, err := c.BuildTransferSOL(ctx, models.TransferSOLRequest{From: signer.NewWatchOnly(from.PublicKey), ToPublicKey: c.CreateAccount().PublicKey, Lamports: 1})
+	if err != nil {
+		t.Fatalf("build transfer failed: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(prepared.Transaction)
+	if err != nil {
+		t.Fatalf("decode transaction failed: %v", err)
+	}
+
+	// the transfer has one signature and three accounts; claim more signers than accounts, with
+	// as many signature slots so the signature count still matches the header
+	message := raw[1+64:]
+	for name, signers := range map[string]byte{"more signers than accounts": 4, "readonly accounts past the end": 3} {
+		tampered := append([]byte{signers}, make([]byte, 64*int(signers))...)
+		tampered = append(tampered, signers)
+		tampered = append(tampered, message[1:]...)
+		tx := *prepared
+		tx.Transaction = base64.StdEncoding.EncodeToString(tampered)
+
+		if _, err := c.PartialSign(ctx, models.PartialSignRequest{Transaction: tx, Signers: []models.Signer{mustSigner(t, from)}}); err == nil {
+			t.Errorf("%s: expected partial sign to fail", name)
+		}
+		if _, err := c.AddSignature(models.AddSignatureRequest{Transaction: tx, PublicKey: from.PublicKey, Signature: base58.Encode(make([]byte, 64))}); err == nil {
+			t.Errorf("%s: expected add signature to fail", name)
+		}
+		if _, err := c.Submit(ctx, models.SubmitRequest{Transaction: tx}); err == nil {
+			t.Errorf("%s: expected submit to fail", name)
+		}
+	}
+}
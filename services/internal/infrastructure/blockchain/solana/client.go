@@ -1,20 +1,52 @@
 package sdk
 
 import (
-	"bytes"
 	"context"
 	"encoding/binary"
 	"fmt"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/blocto/solana-go-sdk/client"
 	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
 	"github.com/blocto/solana-go-sdk/types"
-	"github.com/mr-tron/base58"
 	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
 )
 
 type Client struct {
-	c *client.Client
+	c    *client.Client
+	pool *rpcPool
+
+	confirmPollInitial time.Duration
+	confirmPollMax     time.Duration
+	computeBudget      models.ComputeBudget
+
+	resendInterval time.Duration
+	maxRebuilds    int
+	submissions    models.SubmissionStore
+	keyLocks       map[string]*keyLock
+	keyLocksMu     sync.Mutex
+	history        models.HistoryStore
+	metadata       *metadataFetcher
+
+	networkEndpoints map[Network][]string
+	healthInterval   time.Duration
+	done             chan struct{}
+	closeOnce        sync.Once
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithConfirmationPolling sets the initial and maximum delay between signature status polls
+func WithConfirmationPolling(initial, max time.Duration) Option {
+	return func(c *Client) {
+		c.confirmPollInitial = initial
+		c.confirmPollMax = max
+	}
 }
 
 // Network defines Solana cluster
@@ -39,208 +71,269 @@ func DefaultRPCURL(network Network) string {
 	}
 }
 
-func NewClient(rpcURL string) *Client {
-	return &Client{c: client.NewClient(rpcURL)}
+// NewClient returns an error when rpcURL is not an http or https URL
+func NewClient(rpcURL string, opts ...Option) (*Client, error) {
+	c := newClient(opts...)
+	if err := c.pool.setEndpoints([]string{rpcURL}); err != nil {
+		return nil, err
+	}
+	c.startHealthChecks()
+	return c, nil
 }
 
-func NewClientForNetwork(network Network) *Client {
-	return NewClient(DefaultRPCURL(network))
+// NewClientForNetwork uses the endpoints configured with WithNetworkEndpoints, or the network's public default
+func NewClientForNetwork(network Network, opts ...Option) (*Client, error) {
+	c := newClient(opts...)
+	if err := c.pool.setEndpoints(c.networkURLs(network)); err != nil {
+		return nil, err
+	}
+	c.startHealthChecks()
+	return c, nil
+}
+
+func newClient(opts ...Option) *Client {
+	c := &Client{
+		pool:             newRPCPool(),
+		networkEndpoints: map[Network][]string{},
+		done:             make(chan struct{}),
+		resendInterval:   defaultResendInterval,
+		maxRebuilds:      defaultMaxRebuilds,
+		submissions:      NewMemorySubmissionStore(),
+		keyLocks:         map[string]*keyLock{},
+		history:          NewMemoryHistoryStore(),
+		metadata:         newMetadataFetcher(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	// every request goes through the pool, so endpoints can change without replacing the rpc client
+	c.c = client.New(rpc.WithEndpoint(poolEndpoint), rpc.WithHTTPClient(&http.Client{Transport: c.pool}))
+	return c
 }
 
-func (c *Client) SwitchNetworkByURL(rpcURL string) {
-	c.c = client.NewClient(rpcURL)
+// SwitchNetworkByURL keeps the current endpoints when rpcURL is invalid
+func (c *Client) SwitchNetworkByURL(rpcURL string) error {
+	return c.pool.setEndpoints([]string{rpcURL})
 }
 
-func (c *Client) SwitchNetwork(network Network) {
-	c.SwitchNetworkByURL(DefaultRPCURL(network))
+func (c *Client) SwitchNetwork(network Network) error {
+	return c.pool.setEndpoints(c.networkURLs(network))
 }
 
 // GetBalance returns balance in lamports for a given public key (base58)
 func (c *Client) GetBalance(ctx context.Context, req models.BalanceRequest) (uint64, error) {
-	pub := common.PublicKeyFromString(req.PublicKey)
-	bal, err := c.c.GetBalance(ctx, pub.ToBase58())
+	pub, err := publicKey(req.PublicKey)
 	if err != nil {
 		return 0, err
 	}
+	bal, err := c.c.GetBalance(ctx, pub.ToBase58())
+	if err != nil {
+		return 0, rpcError(err)
+	}
 	return bal, nil
 }
 
 // RequestAirdrop requests airdrop to the given public key (base58) in lamports
 func (c *Client) RequestAirdrop(ctx context.Context, req models.AirdropRequest) (string, error) {
-	pub := common.PublicKeyFromString(req.PublicKey)
-	sig, err := c.c.RequestAirdrop(ctx, pub.ToBase58(), req.Lamports)
+	pub, err := publicKey(req.PublicKey)
 	if err != nil {
 		return "", err
 	}
+	sig, err := c.c.RequestAirdrop(ctx, pub.ToBase58(), req.Lamports)
+	if err != nil {
+		return "", rpcError(err)
+	}
+	if err := c.confirm(ctx, sig, 0, req.SendOptions); err != nil {
+		return sig, err
+	}
 	return sig, nil
 }
 
-// TransferSOL sends lamports from a private key (base58 64 bytes) to recipient public key (base58)
+// TransferSOL sends lamports from the From signer to recipient public key (base58)
 func (c *Client) TransferSOL(ctx context.Context, req models.TransferSOLRequest) (string, error) {
-	privBytes, err := base58.Decode(req.FromPrivateKey)
+	op, err := c.transferSOL(req)
 	if err != nil {
 		return "", err
 	}
-	if len(privBytes) != 64 {
-		return "", fmt.Errorf("invalid private key length: expected 64 bytes")
-	}
+	return c.run(ctx, op)
+}
 
-	sender, err := types.AccountFromBytes(privBytes)
+func (c *Client) transferSOL(req models.TransferSOLRequest) (operation, error) {
+	sender, err := signerPublicKey(req.From)
 	if err != nil {
-		return "", err
+		return operation{}, err
 	}
 
-	recent, err := c.c.GetLatestBlockhash(ctx)
+	to, err := publicKey(req.ToPublicKey)
 	if err != nil {
-		return "", err
+		return operation{}, err
 	}
 
-	to := common.PublicKeyFromString(req.ToPublicKey)
-
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:        sender.PublicKey,
-			RecentBlockhash: recent.Blockhash,
-			Instructions: []types.Instruction{
-				// SystemProgram Transfer
-				{
-					ProgramID: common.SystemProgramID,
-					Accounts: []types.AccountMeta{
-						{PubKey: sender.PublicKey, IsSigner: true, IsWritable: true},
-						{PubKey: to, IsSigner: false, IsWritable: true},
-					},
-					Data: append([]byte{2}, // Transfer instruction index in System Program
-						uint8(req.Lamports), uint8(req.Lamports>>8), uint8(req.Lamports>>16), uint8(req.Lamports>>24),
-						uint8(req.Lamports>>32), uint8(req.Lamports>>40), uint8(req.Lamports>>48), uint8(req.Lamports>>56),
-					),
-				},
-			},
-		}),
-		Signers: []types.Account{sender},
-	})
-	if err != nil {
-		return "", err
+	inst := types.Instruction{
+		// SystemProgram Transfer
+		ProgramID: common.SystemProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: sender, IsSigner: true, IsWritable: true},
+			{PubKey: to, IsSigner: false, IsWritable: true},
+		},
+		Data: append([]byte{2, 0, 0, 0}, // Transfer instruction index in System Program (u32 LE)
+			uint8(req.Lamports), uint8(req.Lamports>>8), uint8(req.Lamports>>16), uint8(req.Lamports>>24),
+			uint8(req.Lamports>>32), uint8(req.Lamports>>40), uint8(req.Lamports>>48), uint8(req.Lamports>>56),
+		),
 	}
 
-	sig, err := c.c.SendTransaction(ctx, tx)
+	instructions, err := annotateTransfer(inst, req.Memo, req.References)
 	if err != nil {
-		return "", err
+		return operation{}, err
 	}
-	return sig, nil
+	return operation{opts: req.SendOptions, feePayer: req.From, instructions: instructions}, nil
 }
 
 // GetMinimumBalanceForRentExemption returns required lamports for an account of given size
 func (c *Client) GetMinimumBalanceForRentExemption(ctx context.Context, req models.RentRequest) (uint64, error) {
-	return c.c.GetMinimumBalanceForRentExemption(ctx, req.DataLen)
+	rent, err := c.c.GetMinimumBalanceForRentExemption(ctx, req.DataLen)
+	if err != nil {
+		return 0, rpcError(err)
+	}
+	return rent, nil
 }
 
-// DeriveAssociatedTokenAddress derives ATA PDA for owner+mint
+// DeriveAssociatedTokenAddress derives ATA PDA for owner+mint under the requested token program
 func (c *Client) DeriveAssociatedTokenAddress(req models.DeriveATARequest) (string, error) {
-	owner := common.PublicKeyFromString(req.Owner)
-	mint := common.PublicKeyFromString(req.Mint)
-	seeds := [][]byte{
-		owner.Bytes(),
-		common.TokenProgramID.Bytes(),
-		mint.Bytes(),
+	program, err := tokenProgramFromString(req.TokenProgram)
+	if err != nil {
+		return "", err
 	}
-	pda, _, err := common.FindProgramAddress(seeds, common.SPLAssociatedTokenAccountProgramID)
+	owner, err := publicKey(req.Owner)
+	if err != nil {
+		return "", err
+	}
+	mint, err := publicKey(req.Mint)
+	if err != nil {
+		return "", err
+	}
+	pda, err := deriveATA(owner, mint, program)
 	if err != nil {
 		return "", err
 	}
 	return pda.ToBase58(), nil
 }
 
-// CreateAssociatedTokenAccountIfNotExists creates ATA for (owner,mint) if missing; returns ATA and optional signature
+// CreateAssociatedTokenAccountIfNotExists creates ATA for (owner,mint) if missing; returns ATA and optional signature.
+// The token program is taken from the mint's owner.
 func (c *Client) CreateAssociatedTokenAccountIfNotExists(ctx context.Context, req models.CreateATARequest) (string, string, error) {
-	ata, err := c.DeriveAssociatedTokenAddress(models.DeriveATARequest{Owner: req.Owner, Mint: req.Mint})
+	op, ata, err := c.createATA(ctx, req, false)
 	if err != nil {
 		return "", "", err
 	}
-	// if exists, nothing to do
-	if _, err := c.c.GetAccountInfo(ctx, ata); err == nil {
+	// if exists, nothing to do; a missing account comes back empty without an error
+	acc, err := c.c.GetAccountInfo(ctx, ata)
+	if err != nil {
+		return "", "", rpcError(err)
+	}
+	if len(acc.Data) > 0 {
 		return ata, "", nil
 	}
 
-	payerPriv, err := base58.Decode(req.PayerPrivateKey)
+	sig, err := c.run(ctx, op)
 	if err != nil {
 		return "", "", err
 	}
-	payer, err := types.AccountFromBytes(payerPriv)
+	return ata, sig, nil
+}
+
+// createATA builds the associated token account creation; the idempotent variant succeeds when the account exists
+func (c *Client) createATA(ctx context.Context, req models.CreateATARequest, idempotent bool) (operation, string, error) {
+	owner, err := publicKey(req.Owner)
 	if err != nil {
-		return "", "", err
+		return operation{}, "", err
+	}
+	mint, err := publicKey(req.Mint)
+	if err != nil {
+		return operation{}, "", err
+	}
+	program, err := c.tokenProgramOf(ctx, req.Mint)
+	if err != nil {
+		return operation{}, "", err
+	}
+	ata, err := c.DeriveAssociatedTokenAddress(models.DeriveATARequest{Owner: req.Owner, Mint: req.Mint, TokenProgram: program.ToBase58()})
+	if err != nil {
+		return operation{}, "", err
 	}
 
-	owner := common.PublicKeyFromString(req.Owner)
-	mint := common.PublicKeyFromString(req.Mint)
-
-	recent, err := c.c.GetLatestBlockhash(ctx)
+	payer, err := signerPublicKey(req.Payer)
 	if err != nil {
-		return "", "", err
+		return operation{}, "", err
 	}
 
+	data := []byte{} // Create
+	if idempotent {
+		data = []byte{1} // CreateIdempotent
+	}
 	inst := types.Instruction{
 		ProgramID: common.SPLAssociatedTokenAccountProgramID,
 		Accounts: []types.AccountMeta{
-			{PubKey: payer.PublicKey, IsSigner: true, IsWritable: true},
+			{PubKey: payer, IsSigner: true, IsWritable: true},
 			{PubKey: common.PublicKeyFromString(ata), IsSigner: false, IsWritable: true},
 			{PubKey: owner, IsSigner: false, IsWritable: false},
 			{PubKey: mint, IsSigner: false, IsWritable: false},
 			{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false},
-			{PubKey: common.TokenProgramID, IsSigner: false, IsWritable: false},
+			{PubKey: program, IsSigner: false, IsWritable: false},
 			{PubKey: common.SysVarRentPubkey, IsSigner: false, IsWritable: false},
 		},
-		Data: []byte{},
+		Data: data,
 	}
 
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:        payer.PublicKey,
-			RecentBlockhash: recent.Blockhash,
-			Instructions:    []types.Instruction{inst},
-		}),
-		Signers: []types.Account{payer},
-	})
-	if err != nil {
-		return "", "", err
-	}
-	sig, err := c.c.SendTransaction(ctx, tx)
-	if err != nil {
-		return "", "", err
-	}
-	return ata, sig, nil
+	return operation{opts: req.SendOptions, feePayer: req.Payer, instructions: []types.Instruction{inst}}, ata, nil
 }
 
 // GetMintDecimals reads decimals from Mint account data at offset 44
 func (c *Client) GetMintDecimals(ctx context.Context, req models.GetMintDecimalsRequest) (uint8, error) {
+	if _, err := publicKey(req.Mint); err != nil {
+		return 0, err
+	}
 	acc, err := c.c.GetAccountInfo(ctx, req.Mint)
 	if err != nil {
-		return 0, err
+		return 0, rpcError(err)
+	}
+	if len(acc.Data) == 0 {
+		return 0, accountError(req.Mint, ErrAccountNotFound)
 	}
 	if len(acc.Data) < 45 {
-		return 0, fmt.Errorf("invalid mint account data")
+		return 0, accountError(req.Mint, ErrInvalidAccountData)
 	}
 	return acc.Data[44], nil
 }
 
-// TransferTokenChecked performs SPL token transfer with decimals check
+// TransferTokenChecked performs SPL token transfer with decimals check.
+// Mints with a Token-2022 transfer fee use TransferCheckedWithFee, asserting the fee of the current epoch.
 func (c *Client) TransferTokenChecked(ctx context.Context, req models.TransferTokenCheckedRequest) (string, error) {
-	priv, err := base58.Decode(req.AuthorityPrivateKey)
+	op, err := c.transferTokenChecked(ctx, req)
 	if err != nil {
 		return "", err
 	}
-	authority, err := types.AccountFromBytes(priv)
+	return c.run(ctx, op)
+}
+
+func (c *Client) transferTokenChecked(ctx context.Context, req models.TransferTokenCheckedRequest) (operation, error) {
+	authority, signers, err := authorityAccounts(req.Authority, req.Multisig)
 	if err != nil {
-		return "", err
+		return operation{}, err
 	}
-
-	recent, err := c.c.GetLatestBlockhash(ctx)
+	mintInfo, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: req.Mint})
 	if err != nil {
-		return "", err
+		return operation{}, err
 	}
 
-	src := common.PublicKeyFromString(req.SourceATA)
-	dst := common.PublicKeyFromString(req.DestinationATA)
-	mint := common.PublicKeyFromString(req.Mint)
+	src, err := publicKey(req.SourceATA)
+	if err != nil {
+		return operation{}, err
+	}
+	dst, err := publicKey(req.DestinationATA)
+	if err != nil {
+		return operation{}, err
+	}
+	mint := common.PublicKeyFromString(mintInfo.Address)
 
 	// token.TransferChecked instruction layout
 	data := make([]byte, 0, 1+8+1)
@@ -251,134 +344,172 @@ func (c *Client) TransferTokenChecked(ctx context.Context, req models.TransferTo
 		byte(req.Amount>>32), byte(req.Amount>>40), byte(req.Amount>>48), byte(req.Amount>>56),
 	)
 	data = append(data, byte(req.Decimals))
+	if mintInfo.TransferFee != nil {
+		epoch, err := c.c.GetEpochInfo(ctx)
+		if err != nil {
+			return operation{}, rpcError(err)
+		}
+		// TransferFeeExtension(26) TransferCheckedWithFee(1): amount, decimals, fee
+		data = append([]byte{26, 1}, data[1:]...)
+		data = binary.LittleEndian.AppendUint64(data, mintInfo.TransferFee.Fee(epoch.Epoch, req.Amount))
+	}
 
 	inst := types.Instruction{
-		ProgramID: common.TokenProgramID,
+		ProgramID: common.PublicKeyFromString(mintInfo.TokenProgram),
 		Accounts: []types.AccountMeta{
 			{PubKey: src, IsSigner: false, IsWritable: true},
 			{PubKey: mint, IsSigner: false, IsWritable: false},
 			{PubKey: dst, IsSigner: false, IsWritable: true},
-			{PubKey: authority.PublicKey, IsSigner: true, IsWritable: false},
 		},
 		Data: data,
 	}
-
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:        authority.PublicKey,
-			RecentBlockhash: recent.Blockhash,
-			Instructions:    []types.Instruction{inst},
-		}),
-		Signers: []types.Account{authority},
-	})
+	inst.Accounts = append(inst.Accounts, authority...)
+	instructions, err := annotateTransfer(inst, req.Memo, req.References)
 	if err != nil {
-		return "", err
+		return operation{}, err
 	}
-	return c.c.SendTransaction(ctx, tx)
+
+	return operation{opts: req.SendOptions, feePayer: req.Authority, instructions: instructions, signers: signers}, nil
 }
 
 // CreateMint creates a new SPL Mint and initializes it
 func (c *Client) CreateMint(ctx context.Context, req models.CreateMintRequest) (string, string, error) {
-	payerPriv, err := base58.Decode(req.PayerPrivateKey)
+	op, mint, err := c.createMint(ctx, req)
 	if err != nil {
 		return "", "", err
 	}
-	payer, err := types.AccountFromBytes(payerPriv)
+	sig, err := c.run(ctx, op)
 	if err != nil {
 		return "", "", err
 	}
+	return mint, sig, nil
+}
 
-	mintAccount := types.NewAccount()
-	mintPub := mintAccount.PublicKey.ToBase58()
+func (c *Client) createMint(ctx context.Context, req models.CreateMintRequest) (operation, string, error) {
+	payer, err := signerPublicKey(req.Payer)
+	if err != nil {
+		return operation{}, "", err
+	}
 
-	// Rent for Mint account; typical size is 82 bytes
-	const mintAccountSize = 82
-	rent, err := c.GetMinimumBalanceForRentExemption(ctx, models.RentRequest{DataLen: mintAccountSize})
+	program, err := tokenProgramFromString(req.TokenProgram)
 	if err != nil {
-		return "", "", err
+		return operation{}, "", err
+	}
+	if req.TransferFee != nil && program != common.Token2022ProgramID {
+		return operation{}, "", fmt.Errorf("transfer fees require the Token-2022 program")
 	}
 
-	recent, err := c.c.GetLatestBlockhash(ctx)
+	mintAccount := signer.NewKeypair()
+	mintPub := mintAccount.PublicKey()
+	mintKey := common.PublicKeyFromString(mintPub)
+
+	// Rent for Mint account; 82 bytes without extensions
+	mintAccountSize := uint64(mintSize)
+	if req.TransferFee != nil {
+		mintAccountSize = extensionsOffset + tlvHeaderSize + transferFeeConfigSize
+	}
+	rent, err := c.GetMinimumBalanceForRentExemption(ctx, models.RentRequest{DataLen: mintAccountSize})
 	if err != nil {
-		return "", "", err
+		return operation{}, "", err
 	}
 
 	// SystemProgram CreateAccount for Mint
 	createMint := types.Instruction{
 		ProgramID: common.SystemProgramID,
 		Accounts: []types.AccountMeta{
-			{PubKey: payer.PublicKey, IsSigner: true, IsWritable: true},
-			{PubKey: mintAccount.PublicKey, IsSigner: true, IsWritable: true},
+			{PubKey: payer, IsSigner: true, IsWritable: true},
+			{PubKey: mintKey, IsSigner: true, IsWritable: true},
 		},
 		Data: func() []byte {
-			// CreateAccount: 0, lamports u64, space u64, owner pubkey
-			data := []byte{0}
+			// CreateAccount: 0 (u32 LE), lamports u64, space u64, owner pubkey
+			data := []byte{0, 0, 0, 0}
 			lam := rent
-			space := uint64(mintAccountSize)
+			space := mintAccountSize
 			data = append(data,
 				byte(lam), byte(lam>>8), byte(lam>>16), byte(lam>>24), byte(lam>>32), byte(lam>>40), byte(lam>>48), byte(lam>>56),
 			)
 			data = append(data,
 				byte(space), byte(space>>8), byte(space>>16), byte(space>>24), byte(space>>32), byte(space>>40), byte(space>>48), byte(space>>56),
 			)
-			data = append(data, common.TokenProgramID.Bytes()...)
+			data = append(data, program.Bytes()...)
 			return data
 		}(),
 	}
+	instructions := []types.Instruction{createMint}
+
+	// extensions are initialized before the mint itself
+	if fee := req.TransferFee; fee != nil {
+		// TransferFeeExtension(26) InitializeTransferFeeConfig(0)
+		data := []byte{26, 0}
+		if data, err = appendPubkeyOption(data, fee.ConfigAuthority); err != nil {
+			return operation{}, "", err
+		}
+		if data, err = appendPubkeyOption(data, fee.WithdrawWithheldAuthority); err != nil {
+			return operation{}, "", err
+		}
+		data = binary.LittleEndian.AppendUint16(data, fee.BasisPoints)
+		data = binary.LittleEndian.AppendUint64(data, fee.MaximumFee)
+		instructions = append(instructions, types.Instruction{
+			ProgramID: program,
+			Accounts: []types.AccountMeta{
+				{PubKey: mintKey, IsSigner: false, IsWritable: true},
+			},
+			Data: data,
+		})
+	}
 
 	// token.InitializeMint2
-	mintAuthority := common.PublicKeyFromString(req.MintAuthority)
+	mintAuthority, err := publicKey(req.MintAuthority)
+	if err != nil {
+		return operation{}, "", err
+	}
+	// InitializeMint2: 20, decimals u8, mintAuthority pubkey, freezeAuthority option u8 (0 = none) + pubkey
+	initData := []byte{20, req.Decimals}
+	initData = append(initData, mintAuthority.Bytes()...)
+	if initData, err = appendPubkeyOption(initData, req.FreezeAuthority); err != nil {
+		return operation{}, "", err
+	}
 	initMint := types.Instruction{
-		ProgramID: common.TokenProgramID,
+		ProgramID: program,
 		Accounts: []types.AccountMeta{
-			{PubKey: mintAccount.PublicKey, IsSigner: false, IsWritable: true},
+			{PubKey: mintKey, IsSigner: false, IsWritable: true},
 		},
-		Data: func() []byte {
-			// InitializeMint2: 20, decimals u8, mintAuthority pubkey, freezeAuthorityOption u8(0 = none)
-			data := []byte{20}
-			data = append(data, byte(req.Decimals))
-			data = append(data, mintAuthority.Bytes()...)
-			data = append(data, 0) // no freeze authority
-			return data
-		}(),
+		Data: initData,
 	}
 
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:        payer.PublicKey,
-			RecentBlockhash: recent.Blockhash,
-			Instructions:    []types.Instruction{createMint, initMint},
-		}),
-		Signers: []types.Account{payer, mintAccount},
-	})
-	if err != nil {
-		return "", "", err
-	}
-	sig, err := c.c.SendTransaction(ctx, tx)
-	if err != nil {
-		return "", "", err
-	}
-	return mintPub, sig, nil
+	return operation{
+		opts:         req.SendOptions,
+		feePayer:     req.Payer,
+		instructions: append(instructions, initMint),
+		generated:    []models.Signer{mintAccount},
+	}, mintPub, nil
 }
 
 // MintTo mints tokens to a destination ATA
 func (c *Client) MintTo(ctx context.Context, req models.MintToRequest) (string, error) {
-	priv, err := base58.Decode(req.MintAuthorityPrivateKey)
+	op, err := c.mintTo(ctx, req)
 	if err != nil {
 		return "", err
 	}
-	authority, err := types.AccountFromBytes(priv)
+	return c.run(ctx, op)
+}
+
+func (c *Client) mintTo(ctx context.Context, req models.MintToRequest) (operation, error) {
+	authority, signers, err := authorityAccounts(req.MintAuthority, req.Multisig)
 	if err != nil {
-		return "", err
+		return operation{}, err
 	}
 
-	recent, err := c.c.GetLatestBlockhash(ctx)
+	program, err := c.tokenProgramOf(ctx, req.Mint)
 	if err != nil {
-		return "", err
+		return operation{}, err
 	}
 
 	mint := common.PublicKeyFromString(req.Mint)
-	dest := common.PublicKeyFromString(req.DestinationATA)
+	dest, err := publicKey(req.DestinationATA)
+	if err != nil {
+		return operation{}, err
+	}
 
 	data := make([]byte, 0, 1+8)
 	data = append(data, byte(7)) // MintTo
@@ -387,276 +518,40 @@ func (c *Client) MintTo(ctx context.Context, req models.MintToRequest) (string,
 		byte(req.Amount>>32), byte(req.Amount>>40), byte(req.Amount>>48), byte(req.Amount>>56),
 	)
 	inst := types.Instruction{
-		ProgramID: common.TokenProgramID,
+		ProgramID: program,
 		Accounts: []types.AccountMeta{
 			{PubKey: mint, IsSigner: false, IsWritable: true},
 			{PubKey: dest, IsSigner: false, IsWritable: true},
-			{PubKey: authority.PublicKey, IsSigner: true, IsWritable: false},
 		},
 		Data: data,
 	}
+	inst.Accounts = append(inst.Accounts, authority...)
 
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:        authority.PublicKey,
-			RecentBlockhash: recent.Blockhash,
-			Instructions:    []types.Instruction{inst},
-		}),
-		Signers: []types.Account{authority},
-	})
-	if err != nil {
-		return "", err
-	}
-	return c.c.SendTransaction(ctx, tx)
-}
-
-var tokenMetadataProgramID = common.PublicKeyFromString("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
-
-// GetTokenMetadata fetches Metaplex metadata (name, symbol, uri) and mint decimals
-func (c *Client) GetTokenMetadata(ctx context.Context, req models.GetTokenMetadataRequest) (*models.TokenMetadata, error) {
-	mint := common.PublicKeyFromString(req.Mint)
-	metaPDA, err := deriveMetadataPDA(mint)
-	if err != nil {
-		return nil, err
-	}
-	acc, err := c.c.GetAccountInfo(ctx, metaPDA.ToBase58())
-	if err != nil {
-		return nil, err
-	}
-	if len(acc.Data) < 1+32+32+4 {
-		return nil, fmt.Errorf("invalid metadata account data")
-	}
-	// Skip: key(1) + updateAuthority(32) + mint(32)
-	offset := 1 + 32 + 32
-	name, off, err := parseBorshString(acc.Data, offset)
-	if err != nil {
-		return nil, err
-	}
-	symbol, off2, err := parseBorshString(acc.Data, off)
-	if err != nil {
-		return nil, err
-	}
-	uri, _, err := parseBorshString(acc.Data, off2)
-	if err != nil {
-		return nil, err
-	}
-	dec, err := c.GetMintDecimals(ctx, models.GetMintDecimalsRequest{Mint: req.Mint})
-	if err != nil {
-		return nil, err
-	}
-	return &models.TokenMetadata{
-		Name:     name,
-		Symbol:   symbol,
-		URI:      uri,
-		Decimals: dec,
-	}, nil
-}
-
-// SetTokenMetadata updates name/symbol/uri via Metaplex UpdateMetadataAccountV2
-func (c *Client) SetTokenMetadata(ctx context.Context, req models.SetTokenMetadataRequest) (string, error) {
-	priv, err := base58.Decode(req.UpdateAuthorityPrivateKey)
-	if err != nil {
-		return "", err
-	}
-	updateAuth, err := types.AccountFromBytes(priv)
-	if err != nil {
-		return "", err
-	}
-
-	mint := common.PublicKeyFromString(req.Mint)
-	metadataPDA, err := deriveMetadataPDA(mint)
-	if err != nil {
-		return "", err
-	}
-
-	// Build instruction data for UpdateMetadataAccountV2
-	var b bytes.Buffer
-	b.WriteByte(15) // UpdateMetadataAccountV2 discriminator
-	b.WriteByte(1)  // Some(DataV2)
-	writeBorshString(&b, req.Name)
-	writeBorshString(&b, req.Symbol)
-	writeBorshString(&b, req.URI)
-	_ = binary.Write(&b, binary.LittleEndian, uint16(0)) // seller_fee_basis_points
-	b.WriteByte(0)                                       // creators None
-	b.WriteByte(0)                                       // collection None
-	b.WriteByte(0)                                       // uses None
-	b.WriteByte(0)                                       // new_update_authority None
-	b.WriteByte(0)                                       // primary_sale_happened None
-	b.WriteByte(0)                                       // is_mutable None
-
-	inst := types.Instruction{
-		ProgramID: tokenMetadataProgramID,
-		Accounts: []types.AccountMeta{
-			{PubKey: metadataPDA, IsSigner: false, IsWritable: true},
-			{PubKey: updateAuth.PublicKey, IsSigner: true, IsWritable: false},
-		},
-		Data: b.Bytes(),
-	}
-
-	recent, err := c.c.GetLatestBlockhash(ctx)
-	if err != nil {
-		return "", err
-	}
-
-	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:        updateAuth.PublicKey,
-			RecentBlockhash: recent.Blockhash,
-			Instructions:    []types.Instruction{inst},
-		}),
-		Signers: []types.Account{updateAuth},
-	})
-	if err != nil {
-		return "", err
-	}
-
-	return c.c.SendTransaction(ctx, tx)
-}
-
-func deriveMetadataPDA(mint common.PublicKey) (common.PublicKey, error) {
-	seeds := [][]byte{
-		[]byte("metadata"),
-		tokenMetadataProgramID.Bytes(),
-		mint.Bytes(),
-	}
-	pda, _, err := common.FindProgramAddress(seeds, tokenMetadataProgramID)
-	if err != nil {
-		return common.PublicKey{}, err
-	}
-	return pda, nil
-}
-
-func parseBorshString(data []byte, offset int) (string, int, error) {
-	if len(data) < offset+4 {
-		return "", 0, fmt.Errorf("unexpected end of data while reading string length")
-	}
-	ln := binary.LittleEndian.Uint32(data[offset : offset+4])
-	offset += 4
-	end := offset + int(ln)
-	if end > len(data) {
-		return "", 0, fmt.Errorf("unexpected end of data while reading string bytes")
-	}
-	return string(data[offset:end]), end, nil
+	return operation{opts: req.SendOptions, feePayer: req.MintAuthority, instructions: []types.Instruction{inst}, signers: signers}, nil
 }
 
-func writeBorshString(b *bytes.Buffer, s string) {
-	length := uint32(len(s))
-	_ = binary.Write(b, binary.LittleEndian, length)
-	b.WriteString(s)
-}
-
-// GetTransactionTransfersSPL parses SPL token transfers from a confirmed transaction
-func (c *Client) GetTransactionTransfersSPL(ctx context.Context, req models.GetTransactionTransfersRequest) ([]*models.Transfer, error) {
-	tx, err := c.c.GetTransaction(ctx, req.Signature)
+// GetTransactionTransfersSPL returns the token transfers of both token programs in a confirmed transaction
+func (c *Client) GetTransactionTransfersSPL(ctx context.Context, req models.GetTransactionTransfersRequest) ([]*models.TokenTransferEvent, error) {
+	events, err := c.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: req.Signature})
 	if err != nil {
 		return nil, err
 	}
-	if tx == nil {
-		return nil, fmt.Errorf("transaction not found")
-	}
-
-	indexAccountMap := map[int]string{}
-	for i, acc := range tx.Transaction.Message.Accounts {
-		indexAccountMap[i] = acc.String()
-	}
-
-	var transfers []*models.Transfer
-
-	// outer instructions
-	for i, inst := range tx.Transaction.Message.Instructions {
-		programID := indexAccountMap[int(inst.ProgramIDIndex)]
-		if programID == common.TokenProgramID.String() {
-			if t := tryDecodeTransfer(inst, indexAccountMap); t != nil {
-				transfers = append(transfers, t)
-				transfers[len(transfers)-1].IsInner = false
-			}
-		}
-		// inner for this outer index
-		if tx.Meta != nil {
-			for _, inner := range tx.Meta.InnerInstructions {
-				if int(inner.Index) != i {
-					continue
-				}
-				for _, inInst := range inner.Instructions {
-					p := indexAccountMap[inInst.ProgramIDIndex]
-					if p == common.TokenProgramID.String() {
-						if t := tryDecodeTransfer(inInst, indexAccountMap); t != nil {
-							t.IsInner = true
-							transfers = append(transfers, t)
-						}
-					}
-				}
-			}
+	var transfers []*models.TokenTransferEvent
+	for _, ev := range events {
+		if t, ok := ev.(*models.TokenTransferEvent); ok {
+			transfers = append(transfers, t)
 		}
 	}
-
 	return transfers, nil
 }
 
-func tryDecodeTransfer(inst types.CompiledInstruction, indexAccountMap map[int]string) *models.Transfer {
-	if len(inst.Data) == 0 {
-		return nil
-	}
-	instrType := inst.Data[0]
-	switch instrType {
-	case 3: // Transfer
-		if len(inst.Data) < 9 || len(inst.Accounts) < 3 {
-			return nil
-		}
-		amount := uint64(inst.Data[1]) |
-			uint64(inst.Data[2])<<8 |
-			uint64(inst.Data[3])<<16 |
-			uint64(inst.Data[4])<<24 |
-			uint64(inst.Data[5])<<32 |
-			uint64(inst.Data[6])<<40 |
-			uint64(inst.Data[7])<<48 |
-			uint64(inst.Data[8])<<56
-		return &models.Transfer{
-			Type:        "transfer",
-			Source:      indexAccountMap[inst.Accounts[0]],
-			Destination: indexAccountMap[inst.Accounts[1]],
-			Authority:   indexAccountMap[inst.Accounts[2]],
-			TokenMint:   "",
-			Amount:      fmt.Sprintf("%d", amount),
-		}
-	case 12: // TransferChecked
-		if len(inst.Data) < 10 || len(inst.Accounts) < 4 {
-			return nil
-		}
-		amount := uint64(inst.Data[1]) |
-			uint64(inst.Data[2])<<8 |
-			uint64(inst.Data[3])<<16 |
-			uint64(inst.Data[4])<<24 |
-			uint64(inst.Data[5])<<32 |
-			uint64(inst.Data[6])<<40 |
-			uint64(inst.Data[7])<<48 |
-			uint64(inst.Data[8])<<56
-		return &models.Transfer{
-			Type:        "transferChecked",
-			Source:      indexAccountMap[inst.Accounts[0]],
-			Destination: indexAccountMap[inst.Accounts[2]],
-			Authority:   indexAccountMap[inst.Accounts[3]],
-			TokenMint:   indexAccountMap[inst.Accounts[1]],
-			Amount:      fmt.Sprintf("%d", amount),
-		}
-	default:
-		return nil
-	}
-}
-
-// GetTokenAccount returns minimal parsed info of a token account (ATA)
+// GetTokenAccount returns the parsed token account (ATA), including its delegate and state
 func (c *Client) GetTokenAccount(ctx context.Context, req models.GetTokenAccountRequest) (*models.TokenAccount, error) {
-	acc, err := c.c.GetAccountInfo(ctx, req.ATA)
+	acc, err := c.tokenAccountInfo(ctx, req.ATA)
 	if err != nil {
 		return nil, err
 	}
-	if len(acc.Data) < 72 {
-		return nil, fmt.Errorf("invalid token account data")
-	}
-	mint := base58.Encode(acc.Data[0:32])
-	owner := base58.Encode(acc.Data[32:64])
-	amount := binary.LittleEndian.Uint64(acc.Data[64:72])
-	return &models.TokenAccount{Mint: mint, Owner: owner, Amount: amount}, nil
+	return parseTokenAccount(req.ATA, acc.Owner, acc.Data)
 }
 
 // GetTokenMintFromATA returns mint address for a given token account (ATA)
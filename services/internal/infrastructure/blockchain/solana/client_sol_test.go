@@ -0,0 +1,123 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	entities "github.com/whiteelite/superapp/internal/domain/entities/solana"
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+var confirmed = models.SendOptions{Commitment: models.CommitmentConfirmed}
+
+func TestSOL_AirdropAndTransfer(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	// Create two accounts
+	acc1 := c.CreateAccount()
+	acc2 := c.CreateAccount()
+
+	// Airdrop to acc1
+	_, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: acc1.PublicKey, Lamports: 100_000_000}) // 0.1 SOL
+	if err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	// Transfer 0.02 SOL to acc2
+	_, err = c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: confirmed,
+		From:        mustSigner(t, acc1),
+		ToPublicKey: acc2.PublicKey,
+		Lamports:    20_000_000,
+	})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+
+	// Verify both balances, the sender also paid one signature fee
+	assertBalance(t, ctx, c, acc2.PublicKey, 20_000_000)
+	assertBalance(t, ctx, c, acc1.PublicKey, 100_000_000-20_000_000-solanatest.LamportsPerSignature)
+}
+
+func TestSOL_RemoteSignerFollowsContext(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	from := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 10_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	// the signer never answers; only the caller's deadline can stop it
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer hung.Close()
+	defer close(release)
+	remote, err := signer.NewRemote(hung.URL, from.PublicKey, nil)
+	if err != nil {
+		t.Fatalf("new remote failed: %v", err)
+	}
+
+	short, stop := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer stop()
+	start := time.Now()
+	_, err = c.TransferSOL(short, models.TransferSOLRequest{From: remote, ToPublicKey: c.CreateAccount().PublicKey, Lamports: 1_000})
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 5*time.Second {
+		t.Fatalf("expected the transfer to stop at the deadline, got %v after %s", err, time.Since(start))
+	}
+}
+
+// newTestClient returns a client bound to an in-process fake cluster.
+func newTestClient(t *testing.T) *sdk.Client {
+	t.Helper()
+	c, _ := newTestCluster(t)
+	return c
+}
+
+// newTestCluster returns a fake cluster and a client polling it without real-time delays.
+func newTestCluster(t *testing.T) (*sdk.Client, *solanatest.Server) {
+	t.Helper()
+	srv := solanatest.NewServer()
+	t.Cleanup(srv.Close)
+	c, err := sdk.NewClient(srv.URL, sdk.WithConfirmationPolling(time.Millisecond, 5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	return c, srv
+}
+
+// mustSigner wraps a generated account's secret in an in-memory signer.
+func mustSigner(t *testing.T, acc entities.Account) models.Signer {
+	t.Helper()
+	kp, err := signer.KeypairFromBase58(acc.PrivateKey)
+	if err != nil {
+		t.Fatalf("load keypair failed: %v", err)
+	}
+	return kp
+}
+
+func assertBalance(t *testing.T, ctx context.Context, c *sdk.Client, pub string, want uint64) {
+	t.Helper()
+	bal, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: pub})
+	if err != nil {
+		t.Fatalf("get balance failed: %v", err)
+	}
+	if bal != want {
+		t.Fatalf("unexpected balance of %s: got %d, want %d", pub, bal, want)
+	}
+}
@@ -2,31 +2,30 @@ package sdk_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
-	"github.com/mr-tron/base58"
 	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
 	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
 )
 
-func TestDevnet_SPL_MintAndTransfer(t *testing.T) {
+func TestSPL_MintAndTransfer(t *testing.T) {
 	t.Parallel()
 
-	c := sdk.NewClientForNetwork(sdk.NetworkDevnet)
+	c := newTestClient(t)
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
 	defer cancel()
 
 	// Payer / mint authority
 	payer := c.CreateAccount()
-	_, err := c.RequestAirdrop(ctx, models.AirdropRequest{PublicKey: payer.PublicKey, Lamports: 200_000_000})
+	_, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: payer.PublicKey, Lamports: 200_000_000})
 	if err != nil {
 		t.Fatalf("airdrop failed: %v", err)
 	}
-	waitForBalanceGTE(t, ctx, c, payer.PublicKey, 100_000_000)
 
 	// Create Mint
-	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{PayerPrivateKey: payer.PrivateKey, MintAuthority: payer.PublicKey, Decimals: 6})
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, payer), MintAuthority: payer.PublicKey, Decimals: 6})
 	if err != nil {
 		t.Fatalf("create mint failed: %v", err)
 	}
@@ -36,17 +35,17 @@ func TestDevnet_SPL_MintAndTransfer(t *testing.T) {
 	owner2 := c.CreateAccount()
 
 	// Create ATAs
-	ata1, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{PayerPrivateKey: payer.PrivateKey, Owner: owner1.PublicKey, Mint: mint})
+	ata1, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: owner1.PublicKey, Mint: mint})
 	if err != nil {
 		t.Fatalf("create ata1 failed: %v", err)
 	}
-	ata2, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{PayerPrivateKey: payer.PrivateKey, Owner: owner2.PublicKey, Mint: mint})
+	ata2, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: owner2.PublicKey, Mint: mint})
 	if err != nil {
 		t.Fatalf("create ata2 failed: %v", err)
 	}
 
 	// MintTo owner1
-	_, err = c.MintTo(ctx, models.MintToRequest{MintAuthorityPrivateKey: payer.PrivateKey, Mint: mint, DestinationATA: ata1, Amount: 1_000_000})
+	_, err = c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, payer), Mint: mint, DestinationATA: ata1, Amount: 1_000_000})
 	if err != nil {
 		t.Fatalf("mint to failed: %v", err)
 	}
@@ -60,20 +59,27 @@ func TestDevnet_SPL_MintAndTransfer(t *testing.T) {
 		t.Fatalf("unexpected mint: %s != %s", ta1.Mint, mint)
 	}
 
+	// owner1 signs the transfer and pays its fee
+	_, err = c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: owner1.PublicKey, Lamports: 10_000_000})
+	if err != nil {
+		t.Fatalf("airdrop to owner1 failed: %v", err)
+	}
+
 	// Transfer tokens owner1 -> owner2
 	_, err = c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
-		AuthorityPrivateKey: payer.PrivateKey,
-		SourceATA:           ata1,
-		DestinationATA:      ata2,
-		Mint:                mint,
-		Amount:              100_000,
-		Decimals:            6,
+		SendOptions:    confirmed,
+		Authority:      mustSigner(t, owner1),
+		SourceATA:      ata1,
+		DestinationATA: ata2,
+		Mint:           mint,
+		Amount:         100_000,
+		Decimals:       6,
 	})
 	if err != nil {
 		t.Fatalf("token transfer failed: %v", err)
 	}
 
-	// Verify dst ATA exists and has same mint
+	// Verify dst ATA exists, has same mint and received the tokens
 	ta2info, err := c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: ata2})
 	if err != nil {
 		t.Fatalf("get token account 2 failed: %v", err)
@@ -81,15 +87,21 @@ func TestDevnet_SPL_MintAndTransfer(t *testing.T) {
 	if ta2info.Mint != mint {
 		t.Fatalf("unexpected dst mint: %s != %s", ta2info.Mint, mint)
 	}
+	if ta2info.Amount != 100_000 {
+		t.Fatalf("unexpected dst amount: %d != %d", ta2info.Amount, 100_000)
+	}
 
 	// Smoke: check ATA derivation
 	ataDerived, err := c.DeriveAssociatedTokenAddress(models.DeriveATARequest{Owner: owner1.PublicKey, Mint: mint})
 	if err != nil {
 		t.Fatalf("derive ata failed: %v", err)
 	}
-	if base58.Encode(commonFromString(ataDerived)) == "" { // simple usage to avoid unused imports; commonFromString below
-		t.Fatalf("derived ata invalid")
+	if ataDerived != ata1 {
+		t.Fatalf("derived ata mismatch: %s != %s", ataDerived, ata1)
 	}
-}
 
-func commonFromString(s string) []byte { return []byte(s) }
+	// a token account is long enough to pass for a mint but must not decode as one
+	if _, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: ata1}); !errors.Is(err, sdk.ErrInvalidAccountData) {
+		t.Fatalf("expected a token account to be refused as a mint, got %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+const (
+	lamportsPerSignature       = 5000
+	defaultUnitsPerInstruction = 200_000
+	maxTransactionUnits        = 1_400_000
+	microLamportsPerLamport    = 1_000_000
+
+	// autoPricePercentile is the percentile of recent prioritization fees AutoPrice pays
+	autoPricePercentile = 75
+)
+
+// WithComputeBudget sets the compute budget applied to every transaction unless a request overrides it
+func WithComputeBudget(budget models.ComputeBudget) Option {
+	return func(c *Client) {
+		c.computeBudget = budget
+	}
+}
+
+// applyComputeBudget prepends the ComputeBudget instructions requested by opts or the client defaults.
+// In AutoPrice mode UnitPrice acts as a floor for the estimated price.
+func (c *Client) applyComputeBudget(ctx context.Context, opts models.SendOptions, feePayer common.PublicKey, instructions []types.Instruction) ([]types.Instruction, error) {
+	budget := c.computeBudget
+	if opts.ComputeBudget != nil {
+		budget = *opts.ComputeBudget
+	}
+
+	price := budget.UnitPrice
+	if budget.AutoPrice {
+		estimated, err := c.estimateUnitPrice(ctx, writableAccounts(feePayer, instructions))
+		if err != nil {
+			return nil, err
+		}
+		if budget.MaxUnitPrice > 0 && estimated > budget.MaxUnitPrice {
+			estimated = budget.MaxUnitPrice
+		}
+		price = max(price, estimated)
+	}
+
+	var prefix []types.Instruction
+	if budget.UnitLimit > 0 {
+		data := make([]byte, 5)
+		data[0] = 2 // SetComputeUnitLimit
+		binary.LittleEndian.PutUint32(data[1:], budget.UnitLimit)
+		prefix = append(prefix, types.Instruction{ProgramID: common.ComputeBudgetProgramID, Data: data})
+	}
+	if price > 0 {
+		data := make([]byte, 9)
+		data[0] = 3 // SetComputeUnitPrice
+		binary.LittleEndian.PutUint64(data[1:], price)
+		prefix = append(prefix, types.Instruction{ProgramID: common.ComputeBudgetProgramID, Data: data})
+	}
+	if len(prefix) == 0 {
+		return instructions, nil
+	}
+	return append(prefix, instructions...), nil
+}
+
+// estimateUnitPrice returns the autoPricePercentile of recent prioritization fees paid to lock accounts
+func (c *Client) estimateUnitPrice(ctx context.Context, accounts []common.PublicKey) (uint64, error) {
+	fees, err := c.c.GetRecentPrioritizationFees(ctx, accounts)
+	if err != nil {
+		return 0, rpcError(err)
+	}
+	if len(fees) == 0 {
+		return 0, nil
+	}
+	prices := make([]uint64, 0, len(fees))
+	for _, f := range fees {
+		prices = append(prices, f.PrioritizationFee)
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
+	// nearest-rank percentile
+	rank := (len(prices)*autoPricePercentile + 99) / 100
+	return prices[rank-1], nil
+}
+
+func writableAccounts(feePayer common.PublicKey, instructions []types.Instruction) []common.PublicKey {
+	seen := map[common.PublicKey]bool{feePayer: true}
+	accounts := []common.PublicKey{feePayer}
+	for _, inst := range instructions {
+		for _, meta := range inst.Accounts {
+			if meta.IsWritable && !seen[meta.PubKey] {
+				seen[meta.PubKey] = true
+				accounts = append(accounts, meta.PubKey)
+			}
+		}
+	}
+	return accounts
+}
+
+// GetTransactionFee reports the fee a landed transaction paid, split into signature and priority parts
+func (c *Client) GetTransactionFee(ctx context.Context, req models.GetTransactionFeeRequest) (*models.TransactionFee, error) {
+	tx, err := c.c.GetTransaction(ctx, req.Signature)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	if tx == nil || tx.Meta == nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+
+	msg := tx.Transaction.Message
+	fee := &models.TransactionFee{
+		Signature: req.Signature,
+		Total:     tx.Meta.Fee,
+		Base:      uint64(msg.Header.NumRequireSignatures) * lamportsPerSignature,
+	}
+	if fee.Total > fee.Base {
+		fee.Priority = fee.Total - fee.Base
+	}
+	if tx.Meta.ComputeUnitsConsumed != nil {
+		fee.ComputeUnitsConsumed = *tx.Meta.ComputeUnitsConsumed
+	}
+
+	limitSet := false
+	var others uint32
+	for _, inst := range msg.Instructions {
+		if msg.Accounts[inst.ProgramIDIndex] != common.ComputeBudgetProgramID {
+			others++
+			continue
+		}
+		switch {
+		case len(inst.Data) >= 5 && inst.Data[0] == 2:
+			fee.ComputeUnitLimit = binary.LittleEndian.Uint32(inst.Data[1:5])
+			limitSet = true
+		case len(inst.Data) >= 9 && inst.Data[0] == 3:
+			fee.ComputeUnitPrice = binary.LittleEndian.Uint64(inst.Data[1:9])
+		}
+	}
+	if !limitSet {
+		fee.ComputeUnitLimit = min(others*defaultUnitsPerInstruction, maxTransactionUnits)
+	}
+	return fee, nil
+}
@@ -0,0 +1,96 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func TestComputeBudget_ExplicitPriorityFee(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	from := c.CreateAccount()
+	to := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 10_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	opts := confirmed
+	opts.ComputeBudget = &models.ComputeBudget{UnitLimit: 10_000, UnitPrice: 250_000}
+	sig, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: opts, From: mustSigner(t, from), ToPublicKey: to.PublicKey, Lamports: 1_000_000})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+
+	// 250_000 micro-lamports per unit over 10_000 units is 2_500 lamports
+	const priority = 2_500
+	assertBalance(t, ctx, c, from.PublicKey, 10_000_000-1_000_000-solanatest.LamportsPerSignature-priority)
+
+	fee, err := c.GetTransactionFee(ctx, models.GetTransactionFeeRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get transaction fee failed: %v", err)
+	}
+	if fee.Total != solanatest.LamportsPerSignature+priority || fee.Base != solanatest.LamportsPerSignature || fee.Priority != priority {
+		t.Fatalf("unexpected fee breakdown: %+v", fee)
+	}
+	if fee.ComputeUnitLimit != 10_000 || fee.ComputeUnitPrice != 250_000 {
+		t.Fatalf("unexpected compute budget: %+v", fee)
+	}
+	if fee.ComputeUnitsConsumed == 0 || fee.ComputeUnitsConsumed > 10_000 {
+		t.Fatalf("unexpected units consumed: %d", fee.ComputeUnitsConsumed)
+	}
+}
+
+func TestComputeBudget_AutoPrice(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	from := c.CreateAccount()
+	to := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 10_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	// a prior transaction writing the same accounts sets the recent price
+	opts := confirmed
+	opts.ComputeBudget = &models.ComputeBudget{UnitPrice: 7_000}
+	if _, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: opts, From: mustSigner(t, from), ToPublicKey: to.PublicKey, Lamports: 1_000}); err != nil {
+		t.Fatalf("priced transfer failed: %v", err)
+	}
+
+	opts.ComputeBudget = &models.ComputeBudget{AutoPrice: true}
+	sig, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: opts, From: mustSigner(t, from), ToPublicKey: to.PublicKey, Lamports: 1_000})
+	if err != nil {
+		t.Fatalf("auto priced transfer failed: %v", err)
+	}
+	fee, err := c.GetTransactionFee(ctx, models.GetTransactionFeeRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get transaction fee failed: %v", err)
+	}
+	if fee.ComputeUnitPrice != 7_000 {
+		t.Fatalf("unexpected auto price: got %d, want 7000", fee.ComputeUnitPrice)
+	}
+
+	opts.ComputeBudget = &models.ComputeBudget{AutoPrice: true, MaxUnitPrice: 3_000}
+	sig, err = c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: opts, From: mustSigner(t, from), ToPublicKey: to.PublicKey, Lamports: 1_000})
+	if err != nil {
+		t.Fatalf("capped transfer failed: %v", err)
+	}
+	fee, err = c.GetTransactionFee(ctx, models.GetTransactionFeeRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get transaction fee failed: %v", err)
+	}
+	if fee.ComputeUnitPrice != 3_000 {
+		t.Fatalf("unexpected capped price: got %d, want 3000", fee.ComputeUnitPrice)
+	}
+}
@@ -0,0 +1,160 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/rpc"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+const (
+	defaultConfirmPollInitial = 500 * time.Millisecond
+	defaultConfirmPollMax     = 4 * time.Second
+)
+
+var commitmentRank = map[models.Commitment]int{
+	models.CommitmentProcessed: 1,
+	models.CommitmentConfirmed: 2,
+	models.CommitmentFinalized: 3,
+}
+
+// WaitForConfirmation polls the signature status with backoff until the transaction reaches
+// the requested commitment (confirmed by default). When LastValidBlockHeight is set it returns
+// ErrBlockhashExpired once the chain is past it and the transaction is still unknown.
+func (c *Client) WaitForConfirmation(ctx context.Context, req models.WaitForConfirmationRequest) (*models.ConfirmationResult, error) {
+	want := req.Commitment
+	if want == "" {
+		want = models.CommitmentConfirmed
+	}
+	rank, ok := commitmentRank[want]
+	if !ok {
+		return nil, fmt.Errorf("unknown commitment %q", want)
+	}
+
+	delay, maxDelay := c.confirmPollInitial, c.confirmPollMax
+	if delay <= 0 {
+		delay = defaultConfirmPollInitial
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultConfirmPollMax
+	}
+
+	for {
+		// height is read before the status so a missing status past expiry is final
+		var at chainHeight
+		if req.LastValidBlockHeight > 0 {
+			h, err := c.chainHeight(ctx)
+			if err != nil {
+				return nil, err
+			}
+			at = h
+		}
+
+		status, statusSlot, err := c.signatureStatus(ctx, req.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil && status.ConfirmationStatus != nil && commitmentRank[models.Commitment(*status.ConfirmationStatus)] >= rank {
+			return c.confirmationResult(ctx, req.Signature, status)
+		}
+		if c.unseenPast(ctx, req.Signature, req.LastValidBlockHeight, at, status, statusSlot) {
+			return nil, fmt.Errorf("%w: %s", ErrBlockhashExpired, req.Signature)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+func (c *Client) confirmationResult(ctx context.Context, sig string, status *rpc.SignatureStatus) (*models.ConfirmationResult, error) {
+	res := &models.ConfirmationResult{
+		Signature:  sig,
+		Slot:       status.Slot,
+		Commitment: models.Commitment(*status.ConfirmationStatus),
+		Err:        status.Err,
+	}
+	// getTransaction does not serve processed transactions
+	if res.Commitment == models.CommitmentProcessed {
+		return res, nil
+	}
+	tx, err := c.c.GetTransactionWithConfig(ctx, sig, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	if tx != nil && tx.Meta != nil {
+		res.Fee = tx.Meta.Fee
+	}
+	return res, nil
+}
+
+// chainHeight is the confirmed block height with the slot it was read at
+type chainHeight struct {
+	height uint64
+	slot   uint64
+}
+
+func (c *Client) chainHeight(ctx context.Context) (chainHeight, error) {
+	res, err := c.c.RpcClient.GetEpochInfoWithConfig(ctx, rpc.GetEpochInfoConfig{Commitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return chainHeight{}, rpcError(err)
+	}
+	if res.Error != nil {
+		return chainHeight{}, rpcError(res.Error)
+	}
+	return chainHeight{height: res.Result.BlockHeight, slot: res.Result.AbsoluteSlot}, nil
+}
+
+// signatureStatus looks a signature up and returns the slot of the node that answered
+func (c *Client) signatureStatus(ctx context.Context, sig string) (*rpc.SignatureStatus, uint64, error) {
+	res, err := c.c.RpcClient.GetSignatureStatusesWithConfig(ctx, []string{sig}, rpc.GetSignatureStatusesConfig{SearchTransactionHistory: true})
+	if err != nil {
+		return nil, 0, rpcError(err)
+	}
+	if res.Error != nil {
+		return nil, 0, rpcError(res.Error)
+	}
+	if len(res.Result.Value) != 1 {
+		return nil, 0, fmt.Errorf("expected 1 signature status, got %d", len(res.Result.Value))
+	}
+	return res.Result.Value[0], res.Result.Context.Slot, nil
+}
+
+// unseenPast reports whether a signature provably can no longer land. The height and the status
+// may come from different endpoints of the pool, so a missing status only counts when the node
+// that answered had reached the slot the height was read at, and a final lookup confirms it.
+func (c *Client) unseenPast(ctx context.Context, sig string, lastValidBlockHeight uint64, at chainHeight, status *rpc.SignatureStatus, statusSlot uint64) bool {
+	if status != nil || lastValidBlockHeight == 0 || at.height <= lastValidBlockHeight || statusSlot < at.slot {
+		return false
+	}
+	final, finalSlot, err := c.signatureStatus(ctx, sig)
+	return err == nil && final == nil && finalSlot >= at.slot
+}
+
+func (c *Client) confirm(ctx context.Context, sig string, lastValidBlockHeight uint64, opts models.SendOptions) error {
+	if opts.Commitment == "" {
+		return nil
+	}
+	res, err := c.WaitForConfirmation(ctx, models.WaitForConfirmationRequest{
+		Signature:            sig,
+		Commitment:           opts.Commitment,
+		LastValidBlockHeight: lastValidBlockHeight,
+	})
+	if err != nil {
+		return err
+	}
+	if res.Err != nil {
+		decoded := decodeTransactionError(res.Err, nil, nil, nil)
+		return &TransactionError{Signature: sig, TransactionError: *decoded}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package sdk_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func TestWaitForConfirmation_Finalized(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	from := c.CreateAccount()
+	to := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 10_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	sig, err := c.TransferSOL(ctx, models.TransferSOLRequest{From: mustSigner(t, from), ToPublicKey: to.PublicKey, Lamports: 1_000_000})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+
+	res, err := c.WaitForConfirmation(ctx, models.WaitForConfirmationRequest{Signature: sig, Commitment: models.CommitmentFinalized})
+	if err != nil {
+		t.Fatalf("wait for confirmation failed: %v", err)
+	}
+	if res.Commitment != models.CommitmentFinalized {
+		t.Fatalf("unexpected commitment: %s", res.Commitment)
+	}
+	if res.Err != nil {
+		t.Fatalf("unexpected transaction error: %v", res.Err)
+	}
+	if res.Fee != solanatest.LamportsPerSignature {
+		t.Fatalf("unexpected fee: got %d, want %d", res.Fee, solanatest.LamportsPerSignature)
+	}
+	if res.Slot == 0 || res.Signature != sig {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestWaitForConfirmation_BlockhashExpired(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// a signature the cluster has never seen, signed with a blockhash valid until now
+	unknown := base58.Encode(make([]byte, 64))
+	_, err := c.WaitForConfirmation(ctx, models.WaitForConfirmationRequest{
+		Signature:            unknown,
+		LastValidBlockHeight: srv.Slot(),
+	})
+	if !errors.Is(err, sdk.ErrBlockhashExpired) {
+		t.Fatalf("expected ErrBlockhashExpired, got %v", err)
+	}
+}
+
+func TestWaitForConfirmation_LaggingStatusIsNotExpiry(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	from := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 10_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	sig, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, from), ToPublicKey: c.CreateAccount().PublicKey, Lamports: 1_000_000})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+
+	// the first status lookups reach a node far behind the one that reports the height
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatalf("parse url failed: %v", err)
+	}
+	forward := httputil.NewSingleHostReverseProxy(target)
+	var stale atomic.Int32
+	stale.Store(3)
+	lagging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"getSignatureStatuses"`) && stale.Add(-1) >= 0 {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"context":{"slot":1},"value":[null]}}`)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		forward.ServeHTTP(w, r)
+	}))
+	defer lagging.Close()
+
+	lc, err := sdk.NewClient(lagging.URL, sdk.WithConfirmationPolling(time.Millisecond, 5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer lc.Close()
+	res, err := lc.WaitForConfirmation(ctx, models.WaitForConfirmationRequest{Signature: sig, LastValidBlockHeight: 1})
+	if err != nil {
+		t.Fatalf("expected the landed transaction to be found, got %v", err)
+	}
+	if res.Signature != sig || stale.Load() >= 0 {
+		t.Fatalf("unexpected result %+v after %d stale lookups left", res, stale.Load())
+	}
+}
@@ -1,13 +1,20 @@
 package sdk
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/blocto/solana-go-sdk/types"
 	"github.com/mr-tron/base58"
 	entities "github.com/whiteelite/superapp/internal/domain/entities/solana"
 	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/mappers"
 	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
 )
 
+// defaultGapLimit is how many unused accounts in a row end a restore scan
+const defaultGapLimit = 5
+
 func (c *Client) CreateAccount() entities.Account {
 	account := types.NewAccount()
 
@@ -16,3 +23,100 @@ func (c *Client) CreateAccount() entities.Account {
 		PublicKey:  base58.Encode(account.PrivateKey[32:]),
 	})
 }
+
+// CreateAccountFromMnemonic derives account n of a BIP39 mnemonic along m/44'/501'/n'/0'
+func (c *Client) CreateAccountFromMnemonic(req models.DeriveAccountRequest) (entities.Account, error) {
+	seed, err := signer.MnemonicToSeed(req.Mnemonic, req.Passphrase)
+	if err != nil {
+		return entities.Account{}, err
+	}
+	return deriveAccount(seed, req.Index)
+}
+
+// RestoreAccounts recreates the accounts of a mnemonic. Without a Count it derives accounts
+// in order and keeps those before the first GapLimit (5 by default) unused ones in a row;
+// an account is used when it exists on chain.
+func (c *Client) RestoreAccounts(ctx context.Context, req models.RestoreAccountsRequest) ([]entities.Account, error) {
+	seed, err := signer.MnemonicToSeed(req.Mnemonic, req.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if req.Count > 0 {
+		accounts := make([]entities.Account, 0, req.Count)
+		for i := uint32(0); i < req.Count; i++ {
+			acc, err := deriveAccount(seed, i)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, acc)
+		}
+		return accounts, nil
+	}
+
+	gapLimit := req.GapLimit
+	if gapLimit == 0 {
+		gapLimit = defaultGapLimit
+	}
+	var accounts []entities.Account
+	for i, gap := uint32(0), uint32(0); gap < gapLimit; i++ {
+		acc, err := deriveAccount(seed, i)
+		if err != nil {
+			return nil, err
+		}
+		info, err := c.c.GetAccountInfo(ctx, acc.PublicKey)
+		if err != nil {
+			return nil, rpcError(err)
+		}
+		// a missing account comes back empty rather than as an error
+		if info.Lamports == 0 && len(info.Data) == 0 {
+			gap++
+			continue
+		}
+		// accounts between two used ones are kept so indexes stay contiguous
+		for ; gap > 0; gap-- {
+			skipped, err := deriveAccount(seed, i-gap)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, skipped)
+		}
+		accounts = append(accounts, acc)
+	}
+	return accounts, nil
+}
+
+// ImportKeypairFile loads an account from a Solana CLI keypair file
+func (c *Client) ImportKeypairFile(req models.ImportKeypairFileRequest) (entities.Account, error) {
+	kp, err := signer.ReadKeypairFile(req.Path)
+	if err != nil {
+		return entities.Account{}, err
+	}
+	return accountFromKeypair(kp), nil
+}
+
+// ExportKeypairFile writes an account as a Solana CLI keypair file readable only by its owner
+func (c *Client) ExportKeypairFile(req models.ExportKeypairFileRequest) error {
+	if req.Keypair == nil {
+		return fmt.Errorf("missing keypair")
+	}
+	kp, err := signer.KeypairFromBytes(req.Keypair.SecretKey())
+	if err != nil {
+		return err
+	}
+	return signer.WriteKeypairFile(req.Path, kp)
+}
+
+func deriveAccount(seed []byte, index uint32) (entities.Account, error) {
+	kp, err := signer.DeriveKeypair(seed, signer.SolanaPath(index))
+	if err != nil {
+		return entities.Account{}, err
+	}
+	return accountFromKeypair(kp), nil
+}
+
+func accountFromKeypair(kp *signer.Keypair) entities.Account {
+	return mappers.FromAccount(models.Account{
+		PrivateKey: base58.Encode(kp.SecretKey()),
+		PublicKey:  kp.PublicKey(),
+	})
+}
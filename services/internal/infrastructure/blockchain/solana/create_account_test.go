@@ -1,11 +1,16 @@
 package sdk_test
 
 import (
+	"context"
 	"crypto/ed25519"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/mr-tron/base58"
 	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
 )
 
 func TestCreateAccount_ReturnsValidBase58Keys(t *testing.T) {
@@ -64,3 +69,61 @@ func TestCreateAccount_KeysAreUsableForSignature(t *testing.T) {
 	}
 }
 
+
+func TestRestoreAccounts_ScansUsedAccounts(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	mnemonic, err := signer.NewMnemonic(12)
+	if err != nil {
+		t.Fatalf("new mnemonic failed: %v", err)
+	}
+	var derived []string
+	for i := uint32(0); i < 4; i++ {
+		acc, err := c.CreateAccountFromMnemonic(models.DeriveAccountRequest{Mnemonic: mnemonic, Passphrase: "pass", Index: i})
+		if err != nil {
+			t.Fatalf("derive account %d failed: %v", i, err)
+		}
+		derived = append(derived, acc.PublicKey)
+	}
+
+	// accounts 0 and 2 are funded, 1 is a gap between used accounts
+	for _, i := range []int{0, 2} {
+		if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: derived[i], Lamports: 1_000_000}); err != nil {
+			t.Fatalf("airdrop failed: %v", err)
+		}
+	}
+
+	restored, err := c.RestoreAccounts(ctx, models.RestoreAccountsRequest{Mnemonic: mnemonic, Passphrase: "pass", GapLimit: 2})
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if len(restored) != 3 {
+		t.Fatalf("expected 3 restored accounts, got %d", len(restored))
+	}
+	for i, acc := range restored {
+		if acc.PublicKey != derived[i] {
+			t.Fatalf("restored account %d mismatch: %s != %s", i, acc.PublicKey, derived[i])
+		}
+	}
+
+	// the restored secret round-trips through a CLI keypair file
+	path := filepath.Join(t.TempDir(), "id.json")
+	kp, err := signer.KeypairFromBase58(restored[2].PrivateKey)
+	if err != nil {
+		t.Fatalf("load keypair failed: %v", err)
+	}
+	if err := c.ExportKeypairFile(models.ExportKeypairFileRequest{Keypair: kp, Path: path}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	imported, err := c.ImportKeypairFile(models.ImportKeypairFileRequest{Path: path})
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if imported != restored[2] {
+		t.Fatalf("imported account mismatch: %s != %s", imported.PublicKey, restored[2].PublicKey)
+	}
+}
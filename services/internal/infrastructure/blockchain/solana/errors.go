@@ -0,0 +1,182 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// Errors returned by the client match these with errors.Is. errors.As with *AccountError or
+// *TransactionError gives the account, or the failing instruction, it is about.
+var (
+	ErrAccountNotFound    = errors.New("account not found")
+	ErrInvalidAccountData = errors.New("invalid account data")
+	ErrInvalidKey         = errors.New("invalid public key")
+	// ErrInsufficientLamports covers fees, rent and transfers the paying account cannot afford
+	ErrInsufficientLamports     = errors.New("insufficient lamports")
+	ErrInsufficientTokenBalance = errors.New("insufficient token balance")
+	// ErrBlockhashExpired is returned when a transaction did not land before its blockhash expired
+	ErrBlockhashExpired = errors.New("blockhash expired before the transaction was confirmed")
+	// ErrTransactionTooLarge is a transaction whose instructions, memo included, do not fit in the
+	// size a node accepts
+	ErrTransactionTooLarge = errors.New("transaction too large")
+	// ErrTransactionFailed matches every *TransactionError
+	ErrTransactionFailed = errors.New("transaction failed")
+	// ErrRateLimited is returned once every endpoint refused a request for its rate
+	ErrRateLimited = errors.New("rate limited by the RPC node")
+	// ErrNodeUnhealthy is returned when the node is behind or no endpoint could serve a request
+	ErrNodeUnhealthy = errors.New("RPC node is unhealthy")
+	// ErrInvalidMetadataDocument is an off-chain metadata document that is too large or not valid
+	// Metaplex JSON
+	ErrInvalidMetadataDocument = errors.New("invalid off-chain metadata document")
+	// ErrMetadataHostNotPublic is a metadata URI that leads to a loopback, private or link-local address
+	ErrMetadataHostNotPublic = errors.New("metadata host is not a public address")
+	// ErrInvalidSolanaPayURL is a solana: URL that breaks the Solana Pay transfer request format
+	ErrInvalidSolanaPayURL = errors.New("invalid Solana Pay URL")
+	// ErrPaymentNotFound means no confirmed transaction carries the reference yet; poll again later
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrPaymentMismatch is a payment whose recipient, mint, amount or memo differ from the request
+	ErrPaymentMismatch = errors.New("payment does not match the request")
+)
+
+// JSON-RPC error codes of Solana nodes, and the one RPC providers answer rate limited requests with
+const (
+	rpcCodeSimulationFailed = -32002
+	rpcCodeNodeUnhealthy    = -32005
+	rpcCodeRateLimited      = 429
+)
+
+// AccountError is a failure about one account
+type AccountError struct {
+	Account string
+	Err     error
+}
+
+func (e *AccountError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.Account)
+}
+
+func (e *AccountError) Unwrap() error {
+	return e.Err
+}
+
+func accountError(account string, err error) error {
+	return &AccountError{Account: account, Err: err}
+}
+
+// TransactionError is a transaction that failed on chain or was rejected by preflight
+type TransactionError struct {
+	// Signature of the failed transaction; empty when preflight rejected it, as it never landed
+	Signature string
+	models.TransactionError
+	// Logs of the failed execution when the node returned them
+	Logs []string
+}
+
+func (e *TransactionError) Error() string {
+	if e.Signature == "" {
+		return "transaction rejected: " + e.TransactionError.Error()
+	}
+	return fmt.Sprintf("transaction %s failed: %s", e.Signature, e.TransactionError.Error())
+}
+
+func (e *TransactionError) Unwrap() []error {
+	errs := []error{ErrTransactionFailed}
+	if cause := e.cause(); cause != nil {
+		errs = append(errs, cause)
+	}
+	return errs
+}
+
+// cause maps the decoded error to a sentinel; builtin and program errors share some names, so
+// program errors are told apart by their code and program
+func (e *TransactionError) cause() error {
+	switch e.Name {
+	case "AccountNotFound", "ProgramAccountNotFound":
+		return ErrAccountNotFound
+	case "InsufficientFundsForFee", "InsufficientFundsForRent", "ResultWithNegativeLamports":
+		return ErrInsufficientLamports
+	case "InsufficientFunds":
+		if e.Code != nil && (e.Program == common.TokenProgramID.ToBase58() || e.Program == common.Token2022ProgramID.ToBase58()) {
+			return ErrInsufficientTokenBalance
+		}
+	case "BlockhashNotFound":
+		return ErrBlockhashExpired
+	case "InvalidAccountData", "UninitializedAccount", "UninitializedState":
+		return ErrInvalidAccountData
+	}
+	return nil
+}
+
+// publicKey parses a base58 public key, which blocto's PublicKeyFromString silently mangles
+func publicKey(s string) (common.PublicKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil || len(b) != common.PublicKeyLength {
+		return common.PublicKey{}, accountError(s, ErrInvalidKey)
+	}
+	return common.PublicKeyFromBytes(b), nil
+}
+
+func publicKeys(addresses []string) ([]common.PublicKey, error) {
+	keys := make([]common.PublicKey, 0, len(addresses))
+	for _, addr := range addresses {
+		key, err := publicKey(addr)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// rpcError gives node errors that callers act on a sentinel, keeping the original in the chain
+func rpcError(err error) error {
+	var rpcErr *rpc.JsonRpcError
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+	switch rpcErr.Code {
+	case rpcCodeRateLimited:
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	case rpcCodeNodeUnhealthy:
+		return fmt.Errorf("%w: %w", ErrNodeUnhealthy, err)
+	}
+	return err
+}
+
+// preflightFailure returns the transaction error a node attaches to a failed preflight simulation
+func preflightFailure(err error) (txErr any, logs []string, ok bool) {
+	var rpcErr *rpc.JsonRpcError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != rpcCodeSimulationFailed {
+		return nil, nil, false
+	}
+	data, _ := rpcErr.Data.(map[string]any)
+	if data["err"] == nil {
+		return nil, nil, false
+	}
+	if raw, ok := data["logs"].([]any); ok {
+		for _, l := range raw {
+			if s, ok := l.(string); ok {
+				logs = append(logs, s)
+			}
+		}
+	}
+	return data["err"], logs, true
+}
+
+// transactionError decodes the error of tx, resolving the accounts it loads from lookup tables
+// when they are still readable
+func (c *Client) transactionError(ctx context.Context, signature string, tx types.Transaction, txErr any, logs []string) *TransactionError {
+	keys, err := c.messageAccountKeys(ctx, tx.Message)
+	if err != nil {
+		keys = tx.Message.Accounts
+	}
+	decoded := decodeTransactionError(txErr, tx.Message.Instructions, keys, logs)
+	return &TransactionError{Signature: signature, TransactionError: *decoded, Logs: logs}
+}
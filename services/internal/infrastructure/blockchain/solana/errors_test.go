@@ -0,0 +1,138 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+func TestErrors_AccountErrors(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	_, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: "not-a-key"})
+	var accErr *sdk.AccountError
+	if !errors.Is(err, sdk.ErrInvalidKey) || !errors.As(err, &accErr) || accErr.Account != "not-a-key" {
+		t.Fatalf("expected an invalid key error, got %v", err)
+	}
+	if _, err := c.GetSignaturesForAddress(ctx, models.GetSignaturesForAddressRequest{Address: "not-a-key"}); !errors.Is(err, sdk.ErrInvalidKey) {
+		t.Fatalf("expected an invalid address, got %v", err)
+	}
+
+	missing := c.CreateAccount()
+	_, err = c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: missing.PublicKey})
+	if !errors.Is(err, sdk.ErrAccountNotFound) || !errors.As(err, &accErr) || accErr.Account != missing.PublicKey {
+		t.Fatalf("expected an account not found error, got %v", err)
+	}
+
+	payer := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: payer.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	_, err = c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, payer), ToPublicKey: "0OIl", Lamports: 1})
+	if !errors.Is(err, sdk.ErrInvalidKey) {
+		t.Fatalf("expected an invalid recipient, got %v", err)
+	}
+}
+
+func TestErrors_ProgramErrorsCarryInstructionContext(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	bob := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 2})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	aliceATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: alice.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	bobATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: bob.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+
+	_, err = c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: confirmed, Authority: mustSigner(t, alice), SourceATA: aliceATA, DestinationATA: bobATA, Mint: mint, Amount: 100, Decimals: 2,
+	})
+	var txErr *sdk.TransactionError
+	if !errors.Is(err, sdk.ErrInsufficientTokenBalance) || !errors.Is(err, sdk.ErrTransactionFailed) || !errors.As(err, &txErr) {
+		t.Fatalf("expected insufficient token balance, got %v", err)
+	}
+	if txErr.Instruction < 0 || txErr.Account != aliceATA || txErr.Name != "InsufficientFunds" || txErr.Code == nil || *txErr.Code != 1 ||
+		txErr.Signature != "" || len(txErr.Logs) == 0 {
+		t.Fatalf("unexpected transaction error: %+v", txErr)
+	}
+
+	_, err = c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 1_000_000_000})
+	if !errors.Is(err, sdk.ErrInsufficientLamports) || !errors.As(err, &txErr) || txErr.Account != alice.PublicKey ||
+		txErr.Name != "ResultWithNegativeLamports" {
+		t.Fatalf("expected insufficient lamports, got %v", err)
+	}
+
+	// an unfunded fee payer fails before any instruction runs
+	_, err = c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, bob), ToPublicKey: alice.PublicKey, Lamports: 1})
+	if !errors.Is(err, sdk.ErrAccountNotFound) || !errors.As(err, &txErr) || txErr.Instruction != -1 || txErr.Account != bob.PublicKey {
+		t.Fatalf("expected the fee payer to be missing, got %v", err)
+	}
+}
+
+func TestErrors_NodeErrors(t *testing.T) {
+	t.Parallel()
+
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	behind := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"Node is behind by 120 slots"}}`))
+	}))
+	defer behind.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, tc := range []struct {
+		url  string
+		want error
+	}{
+		{limited.URL, sdk.ErrRateLimited},
+		{behind.URL, sdk.ErrNodeUnhealthy},
+		{down.URL, sdk.ErrNodeUnhealthy},
+	} {
+		c, err := sdk.NewClient(tc.url)
+		if err != nil {
+			t.Fatalf("new client failed: %v", err)
+		}
+		_, err = c.GetBalance(ctx, models.BalanceRequest{PublicKey: c.CreateAccount().PublicKey})
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.url, tc.want, err)
+		}
+		_, err = c.GetSignaturesForAddress(ctx, models.GetSignaturesForAddressRequest{Address: c.CreateAccount().PublicKey})
+		c.Close()
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected signatures to fail with %v, got %v", tc.url, tc.want, err)
+		}
+	}
+}
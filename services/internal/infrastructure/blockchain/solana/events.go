@@ -0,0 +1,516 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// solDecimals normalizes lamports to SOL
+const solDecimals = 9
+
+// GetTransactionEvents decodes the System, token and memo instructions of a confirmed transaction,
+// outer and inner, in execution order. Amounts are normalized by the mint's decimals, taken from
+// checked instructions, the transaction's token balances or, failing those, the mint account.
+// Token-2022 transfer fees are taken from TransferCheckedWithFee or from the mint's fee
+// configuration for the epoch of the transaction. A mint that can no longer be read, e.g. one
+// closed since, leaves the amount unscaled or the fee unresolved rather than failing the call.
+// A failed transaction moved nothing but its fee and has no events.
+func (c *Client) GetTransactionEvents(ctx context.Context, req models.GetTransactionEventsRequest) ([]models.Event, error) {
+	tx, err := c.c.GetTransaction(ctx, req.Signature)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction not found")
+	}
+	return c.transactionEvents(ctx, tx)
+}
+
+// transactionEvents decodes the events of a fetched transaction; a failed one has none
+func (c *Client) transactionEvents(ctx context.Context, tx *client.Transaction) ([]models.Event, error) {
+	if tx.Meta != nil && tx.Meta.Err != nil {
+		return nil, nil
+	}
+	d := newEventDecoder(c, tx)
+	var events []models.Event
+	for i, inst := range tx.Transaction.Message.Instructions {
+		ev, err := d.decode(ctx, inst, models.InstructionRef{Index: i})
+		if err != nil {
+			return nil, err
+		}
+		if ev != nil {
+			events = append(events, ev)
+		}
+		if tx.Meta == nil {
+			continue
+		}
+		for _, inner := range tx.Meta.InnerInstructions {
+			if int(inner.Index) != i {
+				continue
+			}
+			for j, inInst := range inner.Instructions {
+				ev, err := d.decode(ctx, inInst, models.InstructionRef{Index: i, InnerIndex: j, IsInner: true})
+				if err != nil {
+					return nil, err
+				}
+				if ev != nil {
+					events = append(events, ev)
+				}
+			}
+		}
+	}
+
+	if err := d.resolveTransferFees(ctx, tx.Slot, events); err != nil {
+		return nil, err
+	}
+	attachMemos(events)
+	return events, nil
+}
+
+// attachMemos gives each transfer the closest memo before it that no earlier transfer took
+func attachMemos(events []models.Event) {
+	pending := ""
+	for _, ev := range events {
+		switch ev := ev.(type) {
+		case *models.MemoEvent:
+			pending = ev.Memo
+		case *models.SOLTransferEvent:
+			ev.Memo, pending = pending, ""
+		case *models.TokenTransferEvent:
+			ev.Memo, pending = pending, ""
+		}
+	}
+}
+
+// tokenBalance is the mint and decimals of a token account listed in a transaction's token balances
+type tokenBalance struct {
+	mint     string
+	decimals uint8
+}
+
+type eventDecoder struct {
+	c    *Client
+	keys []common.PublicKey
+	// signers is the number of leading keys that signed the transaction
+	signers int
+	// balances is keyed by account index, decimals by mint
+	balances map[int]tokenBalance
+	decimals map[string]uint8
+	mints    map[string]*models.MintInfo
+}
+
+func newEventDecoder(c *Client, tx *client.Transaction) *eventDecoder {
+	d := &eventDecoder{
+		c:        c,
+		keys:     transactionAccountKeys(tx),
+		signers:  int(tx.Transaction.Message.Header.NumRequireSignatures),
+		balances: map[int]tokenBalance{},
+		decimals: map[string]uint8{},
+		mints:    map[string]*models.MintInfo{},
+	}
+	if tx.Meta != nil {
+		// post balances cover accounts created by the transaction, pre balances closed ones
+		for _, list := range [][]rpc.TransactionMetaTokenBalance{tx.Meta.PreTokenBalances, tx.Meta.PostTokenBalances} {
+			for _, b := range list {
+				d.balances[int(b.AccountIndex)] = tokenBalance{mint: b.Mint, decimals: b.UITokenAmount.Decimals}
+				d.decimals[b.Mint] = b.UITokenAmount.Decimals
+			}
+		}
+	}
+	return d
+}
+
+// transactionAccountKeys lists the accounts instruction indexes refer to: the static keys
+// followed, for v0 transactions, by the addresses loaded from lookup tables (writable first)
+func transactionAccountKeys(tx *client.Transaction) []common.PublicKey {
+	keys := append([]common.PublicKey(nil), tx.Transaction.Message.Accounts...)
+	if tx.Meta == nil {
+		return keys
+	}
+	for _, list := range [][]string{tx.Meta.LoadedAddresses.Writable, tx.Meta.LoadedAddresses.Readonly} {
+		for _, addr := range list {
+			keys = append(keys, common.PublicKeyFromString(addr))
+		}
+	}
+	return keys
+}
+
+// account returns the address of the instruction's i-th account, empty when it is missing
+func (d *eventDecoder) account(inst types.CompiledInstruction, i int) string {
+	if i >= len(inst.Accounts) || inst.Accounts[i] >= len(d.keys) {
+		return ""
+	}
+	return d.keys[inst.Accounts[i]].ToBase58()
+}
+
+// references returns the accounts of the instruction from the i-th on that did not sign the
+// transaction; signers there are multisig members approving a transfer
+func (d *eventDecoder) references(inst types.CompiledInstruction, i int) []string {
+	var refs []string
+	for ; i < len(inst.Accounts); i++ {
+		if inst.Accounts[i] < d.signers || inst.Accounts[i] >= len(d.keys) {
+			continue
+		}
+		refs = append(refs, d.keys[inst.Accounts[i]].ToBase58())
+	}
+	return refs
+}
+
+// tokenAccount returns the mint and decimals of the instruction's i-th account from the token balances
+func (d *eventDecoder) tokenAccount(inst types.CompiledInstruction, i int) (tokenBalance, bool) {
+	if i >= len(inst.Accounts) {
+		return tokenBalance{}, false
+	}
+	b, ok := d.balances[inst.Accounts[i]]
+	return b, ok
+}
+
+// mintAmount scales raw by the decimals of mint, looked up in the token balances before the
+// mint account. A mint that cannot be read leaves the amount unscaled.
+func (d *eventDecoder) mintAmount(ctx context.Context, mint string, raw uint64) (models.Amount, error) {
+	if dec, ok := d.decimals[mint]; ok {
+		return models.NewAmount(raw, dec), nil
+	}
+	info, err := d.mintInfo(ctx, mint)
+	if unreadableMint(err) {
+		return models.UnscaledAmount(raw), nil
+	}
+	if err != nil {
+		return models.Amount{}, err
+	}
+	d.decimals[mint] = info.Decimals
+	return models.NewAmount(raw, info.Decimals), nil
+}
+
+// unreadableMint tells a mint that is gone or no longer decodes from a failed lookup
+func unreadableMint(err error) bool {
+	return errors.Is(err, ErrAccountNotFound) || errors.Is(err, ErrInvalidAccountData) || errors.Is(err, ErrInvalidKey)
+}
+
+func (d *eventDecoder) mintInfo(ctx context.Context, mint string) (*models.MintInfo, error) {
+	if info, ok := d.mints[mint]; ok {
+		return info, nil
+	}
+	info, err := d.c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: mint})
+	if err != nil {
+		return nil, err
+	}
+	d.mints[mint] = info
+	return info, nil
+}
+
+func (d *eventDecoder) decode(ctx context.Context, inst types.CompiledInstruction, loc models.InstructionRef) (models.Event, error) {
+	if inst.ProgramIDIndex >= len(d.keys) {
+		return nil, nil
+	}
+	program := d.keys[inst.ProgramIDIndex]
+	loc.Program = program.ToBase58()
+	switch {
+	case program == common.SystemProgramID:
+		return d.decodeSystem(inst, loc), nil
+	case isTokenProgram(program):
+		return d.decodeToken(ctx, inst, loc)
+	case isMemoProgram(program):
+		loc.Type = models.EventMemo
+		ev := &models.MemoEvent{InstructionRef: loc, Memo: string(inst.Data)}
+		for i := range inst.Accounts {
+			ev.Signers = append(ev.Signers, d.account(inst, i))
+		}
+		return ev, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (d *eventDecoder) decodeSystem(inst types.CompiledInstruction, loc models.InstructionRef) models.Event {
+	data := inst.Data
+	if len(data) < 4 {
+		return nil
+	}
+	switch binary.LittleEndian.Uint32(data) {
+	case 0: // CreateAccount: lamports u64, space u64, owner
+		if len(data) < 4+8+8+32 {
+			return nil
+		}
+		loc.Type = models.EventCreateAccount
+		return &models.CreateAccountEvent{
+			InstructionRef: loc,
+			Funder:         d.account(inst, 0),
+			Account:        d.account(inst, 1),
+			Owner:          base58.Encode(data[20:52]),
+			Amount:         models.NewAmount(binary.LittleEndian.Uint64(data[4:12]), solDecimals),
+			Space:          binary.LittleEndian.Uint64(data[12:20]),
+		}
+	case 2: // Transfer: lamports u64
+		if len(data) < 4+8 {
+			return nil
+		}
+		loc.Type = models.EventSOLTransfer
+		return &models.SOLTransferEvent{
+			InstructionRef: loc,
+			From:           d.account(inst, 0),
+			To:             d.account(inst, 1),
+			Amount:         models.NewAmount(binary.LittleEndian.Uint64(data[4:12]), solDecimals),
+			References:     d.references(inst, 2),
+		}
+	default:
+		return nil
+	}
+}
+
+func (d *eventDecoder) decodeToken(ctx context.Context, inst types.CompiledInstruction, loc models.InstructionRef) (models.Event, error) {
+	data := inst.Data
+	if len(data) == 0 {
+		return nil, nil
+	}
+	// amount u64 follows the tag; checked variants add decimals u8
+	var (
+		amount   uint64
+		decimals uint8
+	)
+	if len(data) >= 9 {
+		amount = binary.LittleEndian.Uint64(data[1:9])
+	}
+	if len(data) >= 10 {
+		decimals = data[9]
+	}
+
+	switch data[0] {
+	case 1, 16, 18: // InitializeAccount, InitializeAccount2, InitializeAccount3
+		ev := &models.InitializeAccountEvent{Account: d.account(inst, 0), Mint: d.account(inst, 1)}
+		switch data[0] {
+		case 1:
+			loc.Type = models.EventInitializeAccount
+			ev.Owner = d.account(inst, 2)
+		case 16, 18:
+			if len(data) < 33 {
+				return nil, nil
+			}
+			loc.Type = models.EventInitializeAccount2
+			if data[0] == 18 {
+				loc.Type = models.EventInitializeAccount3
+			}
+			ev.Owner = base58.Encode(data[1:33])
+		}
+		ev.InstructionRef = loc
+		return ev, nil
+
+	case 3: // Transfer: source, destination, authority
+		if len(data) < 9 || len(inst.Accounts) < 3 {
+			return nil, nil
+		}
+		b, ok := d.tokenAccount(inst, 0)
+		if !ok {
+			b, _ = d.tokenAccount(inst, 1)
+		}
+		loc.Type = models.EventTransfer
+		return &models.TokenTransferEvent{
+			InstructionRef: loc,
+			Source:         d.account(inst, 0),
+			Destination:    d.account(inst, 1),
+			Authority:      d.account(inst, 2),
+			Mint:           b.mint,
+			Amount:         models.NewAmount(amount, b.decimals),
+			References:     d.references(inst, 3),
+		}, nil
+
+	case 12: // TransferChecked: source, mint, destination, authority
+		if len(data) < 10 || len(inst.Accounts) < 4 {
+			return nil, nil
+		}
+		loc.Type = models.EventTransferChecked
+		return &models.TokenTransferEvent{
+			InstructionRef: loc,
+			Source:         d.account(inst, 0),
+			Mint:           d.account(inst, 1),
+			Destination:    d.account(inst, 2),
+			Authority:      d.account(inst, 3),
+			Amount:         models.NewAmount(amount, decimals),
+			References:     d.references(inst, 4),
+		}, nil
+
+	case 26: // TransferFeeExtension
+		// TransferCheckedWithFee: 1, amount u64, decimals u8, fee u64
+		if len(data) < 2+8+1+8 || data[1] != 1 || len(inst.Accounts) < 4 {
+			return nil, nil
+		}
+		amount, decimals = binary.LittleEndian.Uint64(data[2:10]), data[10]
+		fee := binary.LittleEndian.Uint64(data[11:19])
+		// the program refuses a fee above the amount, so only a malformed instruction states one
+		if fee > amount {
+			return nil, nil
+		}
+		loc.Type = models.EventTransferCheckedWithFee
+		return &models.TokenTransferEvent{
+			InstructionRef: loc,
+			Source:         d.account(inst, 0),
+			Mint:           d.account(inst, 1),
+			Destination:    d.account(inst, 2),
+			Authority:      d.account(inst, 3),
+			Amount:         models.NewAmount(amount, decimals),
+			Fee:            models.NewAmount(fee, decimals),
+			NetAmount:      models.NewAmount(amount-fee, decimals),
+			References:     d.references(inst, 4),
+		}, nil
+
+	case 7, 14: // MintTo, MintToChecked: mint, destination, authority
+		if len(data) < 9 || len(inst.Accounts) < 3 {
+			return nil, nil
+		}
+		mint := d.account(inst, 0)
+		loc.Type = models.EventMintToChecked
+		scaled := models.NewAmount(amount, decimals)
+		if data[0] == 7 {
+			loc.Type = models.EventMintTo
+			var err error
+			if scaled, err = d.mintAmount(ctx, mint, amount); err != nil {
+				return nil, err
+			}
+		}
+		return &models.MintToEvent{
+			InstructionRef: loc,
+			Mint:           mint,
+			Destination:    d.account(inst, 1),
+			Authority:      d.account(inst, 2),
+			Amount:         scaled,
+		}, nil
+
+	case 8, 15: // Burn, BurnChecked: account, mint, authority
+		if len(data) < 9 || len(inst.Accounts) < 3 {
+			return nil, nil
+		}
+		mint := d.account(inst, 1)
+		loc.Type = models.EventBurnChecked
+		scaled := models.NewAmount(amount, decimals)
+		if data[0] == 8 {
+			loc.Type = models.EventBurn
+			var err error
+			if scaled, err = d.mintAmount(ctx, mint, amount); err != nil {
+				return nil, err
+			}
+		}
+		return &models.BurnEvent{
+			InstructionRef: loc,
+			Account:        d.account(inst, 0),
+			Mint:           mint,
+			Authority:      d.account(inst, 2),
+			Amount:         scaled,
+		}, nil
+
+	case 4: // Approve: source, delegate, owner
+		if len(data) < 9 || len(inst.Accounts) < 3 {
+			return nil, nil
+		}
+		b, _ := d.tokenAccount(inst, 0)
+		loc.Type = models.EventApprove
+		return &models.ApproveEvent{
+			InstructionRef: loc,
+			Source:         d.account(inst, 0),
+			Mint:           b.mint,
+			Delegate:       d.account(inst, 1),
+			Owner:          d.account(inst, 2),
+			Amount:         models.NewAmount(amount, b.decimals),
+		}, nil
+
+	case 13: // ApproveChecked: source, mint, delegate, owner
+		if len(data) < 10 || len(inst.Accounts) < 4 {
+			return nil, nil
+		}
+		loc.Type = models.EventApproveChecked
+		return &models.ApproveEvent{
+			InstructionRef: loc,
+			Source:         d.account(inst, 0),
+			Mint:           d.account(inst, 1),
+			Delegate:       d.account(inst, 2),
+			Owner:          d.account(inst, 3),
+			Amount:         models.NewAmount(amount, decimals),
+		}, nil
+
+	case 5: // Revoke: source, owner
+		if len(inst.Accounts) < 2 {
+			return nil, nil
+		}
+		loc.Type = models.EventRevoke
+		return &models.RevokeEvent{InstructionRef: loc, Source: d.account(inst, 0), Owner: d.account(inst, 1)}, nil
+
+	case 6: // SetAuthority: account, current authority; type u8, option u8, new authority
+		if len(data) < 3 || len(inst.Accounts) < 2 {
+			return nil, nil
+		}
+		loc.Type = models.EventSetAuthority
+		ev := &models.SetAuthorityEvent{
+			InstructionRef:   loc,
+			Account:          d.account(inst, 0),
+			CurrentAuthority: d.account(inst, 1),
+			AuthorityType:    models.AuthorityType(data[1]),
+		}
+		if data[2] == 1 && len(data) >= 3+32 {
+			ev.NewAuthority = base58.Encode(data[3:35])
+		}
+		return ev, nil
+
+	case 9: // CloseAccount: account, destination, owner
+		if len(inst.Accounts) < 3 {
+			return nil, nil
+		}
+		b, _ := d.tokenAccount(inst, 0)
+		loc.Type = models.EventCloseAccount
+		return &models.CloseAccountEvent{
+			InstructionRef: loc,
+			Account:        d.account(inst, 0),
+			Mint:           b.mint,
+			Destination:    d.account(inst, 1),
+			Owner:          d.account(inst, 2),
+		}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// resolveTransferFees fills the fee of transfers that do not state it. Token transfers have no
+// fee; Token-2022 ones are charged by the mint's schedule for the transaction's epoch, and are
+// marked FeeUnresolved when the mint cannot be read.
+func (d *eventDecoder) resolveTransferFees(ctx context.Context, slot uint64, events []models.Event) error {
+	var epoch *uint64
+	for _, ev := range events {
+		t, ok := ev.(*models.TokenTransferEvent)
+		if !ok || t.Type == models.EventTransferCheckedWithFee {
+			continue
+		}
+		var fee uint64
+		if t.Program == common.Token2022ProgramID.ToBase58() && t.Mint != "" {
+			mint, err := d.mintInfo(ctx, t.Mint)
+			if unreadableMint(err) {
+				t.FeeUnresolved = true
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if mint.TransferFee != nil {
+				if epoch == nil {
+					info, err := d.c.c.GetEpochInfo(ctx)
+					if err != nil {
+						return rpcError(err)
+					}
+					e := epochAt(info, slot)
+					epoch = &e
+				}
+				fee = mint.TransferFee.Fee(*epoch, t.Amount.Raw)
+			}
+		}
+		t.Fee = models.NewAmount(fee, t.Amount.Decimals)
+		t.NetAmount = models.NewAmount(t.Amount.Raw-fee, t.Amount.Decimals)
+	}
+	return nil
+}
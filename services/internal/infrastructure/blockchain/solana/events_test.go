@@ -0,0 +1,340 @@
+package sdk_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	entities "github.com/whiteelite/superapp/internal/domain/entities/solana"
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func TestEvents_DecodesSystemAndTokenInstructions(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	payer := c.CreateAccount()
+	owner1 := c.CreateAccount()
+	owner2 := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: payer.PublicKey, Lamports: 200_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, payer), MintAuthority: payer.PublicKey, Decimals: 6})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	ata1, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: owner1.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	mintSig, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, payer), Mint: mint, DestinationATA: ata1, Amount: 1_000_000})
+	if err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	events, err := c.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: mintSig})
+	if err != nil {
+		t.Fatalf("get events failed: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events))
+	}
+	if ev, ok := events[0].(*models.MintToEvent); !ok || ev.Type != models.EventMintTo || ev.Mint != mint || ev.Amount.Value.String() != "1" {
+		t.Fatalf("unexpected mint event: %+v", events[0])
+	}
+
+	// a hand-built transaction with instructions the client does not emit itself
+	account := types.NewAccount()
+	tx := handBuiltTransaction(t, srv, payer, []types.Account{mustAccount(t, owner1), account},
+		types.Instruction{
+			ProgramID: common.SystemProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: common.PublicKeyFromString(payer.PublicKey), IsSigner: true, IsWritable: true},
+				{PubKey: account.PublicKey, IsSigner: true, IsWritable: true},
+			},
+			Data: systemCreateAccountData(solanatest.RentExemptMinimum(165), 165, common.TokenProgramID),
+		},
+		types.Instruction{
+			ProgramID: common.TokenProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: account.PublicKey, IsWritable: true},
+				{PubKey: common.PublicKeyFromString(mint)},
+			},
+			Data: append([]byte{18}, common.PublicKeyFromString(owner2.PublicKey).Bytes()...),
+		},
+		types.Instruction{
+			ProgramID: common.TokenProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: common.PublicKeyFromString(ata1), IsWritable: true},
+				{PubKey: account.PublicKey, IsWritable: true},
+				{PubKey: common.PublicKeyFromString(owner1.PublicKey), IsSigner: true},
+			},
+			Data: binary.LittleEndian.AppendUint64([]byte{3}, 250_000),
+		},
+		types.Instruction{
+			ProgramID: common.TokenProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: common.PublicKeyFromString(mint), IsWritable: true},
+				{PubKey: account.PublicKey, IsWritable: true},
+				{PubKey: common.PublicKeyFromString(payer.PublicKey), IsSigner: true},
+			},
+			Data: append(binary.LittleEndian.AppendUint64([]byte{14}, 5), 6),
+		},
+		types.Instruction{
+			ProgramID: common.SystemProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: common.PublicKeyFromString(payer.PublicKey), IsSigner: true, IsWritable: true},
+				{PubKey: common.PublicKeyFromString(owner2.PublicKey), IsWritable: true},
+			},
+			Data: binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint32(nil, 2), 15_000_000),
+		},
+	)
+	sig, err := c.Submit(ctx, models.SubmitRequest{SendOptions: confirmed, Transaction: tx})
+	if err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+
+	events, err = c.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get events failed: %v", err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+	created, ok := events[0].(*models.CreateAccountEvent)
+	if !ok || created.Account != account.PublicKey.ToBase58() || created.Owner != common.TokenProgramID.ToBase58() || created.Space != 165 {
+		t.Fatalf("unexpected create account event: %+v", events[0])
+	}
+	initialized, ok := events[1].(*models.InitializeAccountEvent)
+	if !ok || initialized.Type != models.EventInitializeAccount3 || initialized.Mint != mint || initialized.Owner != owner2.PublicKey {
+		t.Fatalf("unexpected initialize account event: %+v", events[1])
+	}
+	// the unchecked transfer takes its mint and decimals from the token balances
+	transfer, ok := events[2].(*models.TokenTransferEvent)
+	if !ok || transfer.Type != models.EventTransfer || transfer.Mint != mint || transfer.Amount.Value.String() != "0.25" || transfer.NetAmount.Raw != 250_000 {
+		t.Fatalf("unexpected transfer event: %+v", events[2])
+	}
+	minted, ok := events[3].(*models.MintToEvent)
+	if !ok || minted.Type != models.EventMintToChecked || minted.Amount.Value.String() != "0.000005" {
+		t.Fatalf("unexpected mint event: %+v", events[3])
+	}
+	sent, ok := events[4].(*models.SOLTransferEvent)
+	if !ok || sent.To != owner2.PublicKey || sent.Amount.Value.String() != "0.015" || sent.Ref().Index != 4 {
+		t.Fatalf("unexpected SOL transfer event: %+v", events[4])
+	}
+
+	transfers, err := c.GetTransactionTransfersSPL(ctx, models.GetTransactionTransfersRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get transfers failed: %v", err)
+	}
+	if len(transfers) != 1 || transfers[0].Source != ata1 || transfers[0].Destination != account.PublicKey.ToBase58() {
+		t.Fatalf("unexpected transfers: %+v", transfers)
+	}
+}
+
+func TestEvents_FailedTransactionsAndMalformedFees(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	payer := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: payer.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, payer), MintAuthority: payer.PublicKey, Decimals: 6, TokenProgram: common.Token2022ProgramID.ToBase58(),
+	})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	source, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: payer.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+
+	// a SOL transfer followed by a TransferCheckedWithFee of 10 base units stating a fee of 50,
+	// which fails the transaction on chain; it still lands as preflight is skipped
+	data := binary.LittleEndian.AppendUint64([]byte{26, 1}, 10)
+	data = binary.LittleEndian.AppendUint64(append(data, 6), 50)
+	prepared := handBuiltTransaction(t, srv, payer, nil,
+		types.Instruction{
+			ProgramID: common.SystemProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: common.PublicKeyFromString(payer.PublicKey), IsSigner: true, IsWritable: true},
+				{PubKey: common.PublicKeyFromString(c.CreateAccount().PublicKey), IsWritable: true},
+			},
+			Data: binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint32(nil, 2), 1_000_000),
+		},
+		types.Instruction{
+			ProgramID: common.Token2022ProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: common.PublicKeyFromString(source), IsWritable: true},
+				{PubKey: common.PublicKeyFromString(mint)},
+				{PubKey: common.PublicKeyFromString(source), IsWritable: true},
+				{PubKey: common.PublicKeyFromString(payer.PublicKey), IsSigner: true},
+			},
+			Data: data,
+		},
+	)
+	raw, _ := base64.StdEncoding.DecodeString(prepared.Transaction)
+	tx, err := types.TransactionDeserialize(raw)
+	if err != nil {
+		t.Fatalf("deserialize transaction failed: %v", err)
+	}
+	sig, err := client.NewClient(srv.URL).SendTransactionWithConfig(ctx, tx, client.SendTransactionConfig{SkipPreflight: true})
+	if err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	// the reverted transaction moved nothing
+	events, err := c.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: sig})
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected no events for a failed transaction, got %+v: %v", events, err)
+	}
+	if transfers, err := c.GetTransactionTransfersSPL(ctx, models.GetTransactionTransfersRequest{Signature: sig}); err != nil || len(transfers) != 0 {
+		t.Fatalf("expected no transfers for a failed transaction, got %+v: %v", transfers, err)
+	}
+
+	// a node claiming it succeeded still gets the malformed transfer skipped
+	succeeded := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		res, err := http.Post(srv.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer res.Body.Close()
+		var envelope map[string]any
+		dec := json.NewDecoder(res.Body)
+		dec.UseNumber()
+		_ = dec.Decode(&envelope)
+		if result, ok := envelope["result"].(map[string]any); ok && strings.Contains(string(body), `"getTransaction"`) {
+			if meta, ok := result["meta"].(map[string]any); ok {
+				meta["err"] = nil
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(envelope)
+	}))
+	defer succeeded.Close()
+	sc, err := sdk.NewClient(succeeded.URL)
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer sc.Close()
+	events, err = sc.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get events failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Ref().Type != models.EventSOLTransfer {
+		t.Fatalf("expected only the SOL transfer, got %+v", events)
+	}
+}
+
+func TestEvents_UnreadableMintKeepsEvents(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	payer := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: payer.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, payer), MintAuthority: payer.PublicKey, Decimals: 6, TokenProgram: common.Token2022ProgramID.ToBase58(),
+	})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	source, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: payer.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	destination, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: c.CreateAccount().PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, payer), Mint: mint, DestinationATA: source, Amount: 1_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+	sig, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: confirmed, Authority: mustSigner(t, payer), SourceATA: source, DestinationATA: destination, Mint: mint, Amount: 250, Decimals: 6, Memo: "order:7",
+	})
+	if err != nil {
+		t.Fatalf("token transfer failed: %v", err)
+	}
+
+	// the mint no longer decodes by the time the transaction is read back
+	srv.SetAccount(mint, solanatest.Account{Lamports: solanatest.RentExemptMinimum(3), Owner: common.Token2022ProgramID, Data: []byte{1, 2, 3}})
+	events, err := c.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get events failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected a memo and a transfer, got %+v", events)
+	}
+	if ev, ok := events[0].(*models.MemoEvent); !ok || ev.Memo != "order:7" {
+		t.Fatalf("unexpected memo event: %+v", events[0])
+	}
+	transfer, ok := events[1].(*models.TokenTransferEvent)
+	if !ok || !transfer.FeeUnresolved || transfer.Amount.Value.String() != "0.00025" || transfer.Memo != "order:7" {
+		t.Fatalf("unexpected transfer event: %+v", events[1])
+	}
+}
+
+// handBuiltTransaction signs instructions paid by payer against the cluster's latest blockhash
+func handBuiltTransaction(t *testing.T, srv *solanatest.Server, payer entities.Account, signers []types.Account, instructions ...types.Instruction) models.PreparedTransaction {
+	t.Helper()
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        common.PublicKeyFromString(payer.PublicKey),
+			RecentBlockhash: srv.LatestBlockhash(),
+			Instructions:    instructions,
+		}),
+		Signers: append([]types.Account{mustAccount(t, payer)}, signers...),
+	})
+	if err != nil {
+		t.Fatalf("build transaction failed: %v", err)
+	}
+	raw, err := tx.Serialize()
+	if err != nil {
+		t.Fatalf("serialize transaction failed: %v", err)
+	}
+	return models.PreparedTransaction{Transaction: base64.StdEncoding.EncodeToString(raw)}
+}
+
+func mustAccount(t *testing.T, acc entities.Account) types.Account {
+	t.Helper()
+	a, err := types.AccountFromBase58(acc.PrivateKey)
+	if err != nil {
+		t.Fatalf("load account failed: %v", err)
+	}
+	return a
+}
+
+func systemCreateAccountData(lamports, space uint64, owner common.PublicKey) []byte {
+	data := binary.LittleEndian.AppendUint32(nil, 0)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	data = binary.LittleEndian.AppendUint64(data, space)
+	return append(data, owner.Bytes()...)
+}
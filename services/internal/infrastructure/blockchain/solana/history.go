@@ -0,0 +1,320 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// maxSignaturesPage is the most signatures getSignaturesForAddress returns per call
+const maxSignaturesPage = 1000
+
+// WithHistoryStore persists synced history in store instead of process memory
+func WithHistoryStore(store models.HistoryStore) Option {
+	return func(c *Client) {
+		c.history = store
+	}
+}
+
+// MemoryHistoryStore keeps history in process memory
+type MemoryHistoryStore struct {
+	mu      sync.Mutex
+	entries map[string][]models.HistoryEntry
+	cursors map[string]map[string]string
+}
+
+func NewMemoryHistoryStore() *MemoryHistoryStore {
+	return &MemoryHistoryStore{entries: map[string][]models.HistoryEntry{}, cursors: map[string]map[string]string{}}
+}
+
+func (s *MemoryHistoryStore) LoadHistoryCursors(_ context.Context, address string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cursors := map[string]string{}
+	for account, sig := range s.cursors[address] {
+		cursors[account] = sig
+	}
+	return cursors, nil
+}
+
+func (s *MemoryHistoryStore) SaveHistory(_ context.Context, address string, entries []models.HistoryEntry, cursors map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := map[string]bool{}
+	for _, e := range s.entries[address] {
+		stored[e.Signature] = true
+	}
+	merged := append([]models.HistoryEntry(nil), s.entries[address]...)
+	for _, e := range entries {
+		if !stored[e.Signature] {
+			merged = append(merged, e)
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Slot > merged[j].Slot })
+	s.entries[address] = merged
+	s.cursors[address] = map[string]string{}
+	for account, sig := range cursors {
+		s.cursors[address][account] = sig
+	}
+	return nil
+}
+
+func (s *MemoryHistoryStore) ListHistory(_ context.Context, address, before string, limit int) ([]models.HistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.entries[address]
+	if before != "" {
+		// skip past the last entry of before, since one transaction can yield several entries
+		start := -1
+		for i, e := range entries {
+			if e.Signature == before {
+				start = i + 1
+			}
+		}
+		if start < 0 {
+			return nil, fmt.Errorf("signature %s is not in the history of %s", before, address)
+		}
+		entries = entries[start:]
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return append([]models.HistoryEntry(nil), entries...), nil
+}
+
+// GetSignaturesForAddress lists confirmed transactions that reference an address, newest first
+func (c *Client) GetSignaturesForAddress(ctx context.Context, req models.GetSignaturesForAddressRequest) ([]models.SignatureInfo, error) {
+	address, err := publicKey(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.c.GetSignaturesForAddressWithConfig(ctx, address.ToBase58(), client.GetSignaturesForAddressConfig{
+		Limit:      req.Limit,
+		Before:     req.Before,
+		Until:      req.Until,
+		Commitment: rpc.CommitmentConfirmed,
+	})
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	infos := make([]models.SignatureInfo, 0, len(res))
+	for _, r := range res {
+		info := models.SignatureInfo{Signature: r.Signature, Slot: r.Slot, Err: r.Err}
+		if r.BlockTime != nil {
+			info.BlockTime = time.Unix(*r.BlockTime, 0)
+		}
+		if r.Memo != nil {
+			info.Memo = *r.Memo
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// SyncHistory pages backwards through the transactions of an address that are newer than the
+// last sync, decodes them into history entries and stores them. A wallet's history includes
+// its token accounts, whose transfers do not reference the wallet itself. Returns the new
+// entries, newest first.
+func (c *Client) SyncHistory(ctx context.Context, req models.SyncHistoryRequest) ([]models.HistoryEntry, error) {
+	if _, err := publicKey(req.Address); err != nil {
+		return nil, err
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 || pageSize > maxSignaturesPage {
+		pageSize = maxSignaturesPage
+	}
+	unlock := c.lockKey("history:" + req.Address)
+	defer unlock()
+
+	cursors, err := c.history.LoadHistoryCursors(ctx, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	tokenAccounts, err := c.tokenAccountsOf(ctx, req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var sigs []models.SignatureInfo
+	seen := map[string]bool{}
+	for _, account := range append([]string{req.Address}, tokenAccounts...) {
+		for before := ""; ; {
+			page, err := c.GetSignaturesForAddress(ctx, models.GetSignaturesForAddressRequest{
+				Address: account,
+				Before:  before,
+				Until:   cursors[account],
+				Limit:   pageSize,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(page) == 0 {
+				break
+			}
+			if before == "" {
+				cursors[account] = page[0].Signature
+			}
+			for _, info := range page {
+				if !seen[info.Signature] {
+					seen[info.Signature] = true
+					sigs = append(sigs, info)
+				}
+			}
+			if len(page) < pageSize {
+				break
+			}
+			before = page[len(page)-1].Signature
+		}
+	}
+	sort.SliceStable(sigs, func(i, j int) bool { return sigs[i].Slot > sigs[j].Slot })
+
+	var entries []models.HistoryEntry
+	for _, info := range sigs {
+		tx, err := c.c.GetTransactionWithConfig(ctx, info.Signature, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+		if err != nil {
+			return nil, rpcError(err)
+		}
+		if tx == nil {
+			return nil, fmt.Errorf("transaction %s not found", info.Signature)
+		}
+		var events []models.Event
+		if tx.Meta != nil && tx.Meta.Err == nil {
+			if events, err = c.transactionEvents(ctx, tx); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, historyEntries(req.Address, tx, events)...)
+	}
+
+	// the cursors only move once every entry they cover is stored
+	if err := c.history.SaveHistory(ctx, req.Address, entries, cursors); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetHistory returns synced history entries of an address, newest first
+func (c *Client) GetHistory(ctx context.Context, req models.GetHistoryRequest) ([]models.HistoryEntry, error) {
+	return c.history.ListHistory(ctx, req.Address, req.Before, req.Limit)
+}
+
+// tokenAccountsOf lists the token accounts owned by address under both token programs
+func (c *Client) tokenAccountsOf(ctx context.Context, address string) ([]string, error) {
+	var accounts []string
+	for _, program := range []common.PublicKey{common.TokenProgramID, common.Token2022ProgramID} {
+		res, err := c.c.RpcClient.GetTokenAccountsByOwnerWithConfig(ctx, address,
+			rpc.GetTokenAccountsByOwnerConfigFilter{ProgramId: program.ToBase58()},
+			rpc.GetTokenAccountsByOwnerConfig{Encoding: rpc.AccountEncodingBase64},
+		)
+		if err != nil {
+			return nil, rpcError(err)
+		}
+		if res.Error != nil {
+			return nil, rpcError(res.Error)
+		}
+		for _, acc := range res.Result.Value {
+			accounts = append(accounts, acc.Pubkey)
+		}
+	}
+	return accounts, nil
+}
+
+// historyEntries turns the transfers, mints and burns of a transaction that move funds in or
+// out of address into history entries. Token accounts count as the address when it owns them.
+func historyEntries(address string, tx *client.Transaction, events []models.Event) []models.HistoryEntry {
+	base := models.HistoryEntry{Address: address, Slot: tx.Slot}
+	if len(tx.Transaction.Signatures) > 0 {
+		base.Signature = base58.Encode(tx.Transaction.Signatures[0])
+	}
+	if tx.BlockTime != nil {
+		base.BlockTime = time.Unix(*tx.BlockTime, 0)
+	}
+	if tx.Meta != nil && tx.Meta.Err != nil {
+		base.Err = fmt.Sprint(tx.Meta.Err)
+	}
+
+	owners := tokenAccountOwners(tx)
+	belongs := func(account string) bool { return account == address || owners[account] == address }
+	party := func(account string) string {
+		if owner, ok := owners[account]; ok {
+			return owner
+		}
+		return account
+	}
+
+	var entries []models.HistoryEntry
+	for _, ev := range events {
+		var from, to, mint string
+		var sent, received models.Amount
+		switch e := ev.(type) {
+		case *models.SOLTransferEvent:
+			from, to, sent, received = e.From, e.To, e.Amount, e.Amount
+		case *models.TokenTransferEvent:
+			// the receiver gets the amount net of any Token-2022 transfer fee, or at most the
+			// whole amount when the fee is unknown
+			from, to, mint, sent, received = e.Source, e.Destination, e.Mint, e.Amount, e.NetAmount
+			if e.FeeUnresolved {
+				received = e.Amount
+			}
+		// mints and burns have the mint as counterparty
+		case *models.MintToEvent:
+			from, to, mint, sent, received = e.Mint, e.Destination, e.Mint, e.Amount, e.Amount
+		case *models.BurnEvent:
+			from, to, mint, sent, received = e.Account, e.Mint, e.Mint, e.Amount, e.Amount
+		default:
+			continue
+		}
+		entry := base
+		entry.Mint = mint
+		entry.Instruction = ev.Ref()
+		switch out, in := belongs(from), belongs(to); {
+		case out && in:
+			entry.Direction, entry.Counterparty, entry.Amount = models.HistorySelf, address, sent
+		case out:
+			entry.Direction, entry.Counterparty, entry.Amount = models.HistoryOut, party(to), sent
+		case in:
+			entry.Direction, entry.Counterparty, entry.Amount = models.HistoryIn, party(from), received
+		default:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	keys := transactionAccountKeys(tx)
+	if tx.Meta == nil || len(keys) == 0 || keys[0].ToBase58() != address {
+		return entries
+	}
+	if len(entries) == 0 {
+		fee := base
+		fee.Direction = models.HistoryFee
+		fee.Amount = models.NewAmount(0, solDecimals)
+		entries = append(entries, fee)
+	}
+	entries[0].Fee = tx.Meta.Fee
+	return entries
+}
+
+// tokenAccountOwners maps the token accounts in a transaction's token balances to their owners
+func tokenAccountOwners(tx *client.Transaction) map[string]string {
+	owners := map[string]string{}
+	if tx.Meta == nil {
+		return owners
+	}
+	keys := transactionAccountKeys(tx)
+	for _, list := range [][]rpc.TransactionMetaTokenBalance{tx.Meta.PreTokenBalances, tx.Meta.PostTokenBalances} {
+		for _, b := range list {
+			if int(b.AccountIndex) < len(keys) && b.Owner != "" {
+				owners[keys[b.AccountIndex].ToBase58()] = b.Owner
+			}
+		}
+	}
+	return owners
+}
@@ -0,0 +1,115 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func TestHistory_SyncsWalletIncrementally(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	bob := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 200_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 6})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	aliceATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: alice.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	bobATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: bob.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, alice), Mint: mint, DestinationATA: aliceATA, Amount: 10_000_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+	solSig, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 20_000_000})
+	if err != nil {
+		t.Fatalf("transfer sol failed: %v", err)
+	}
+	tokenSig, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: confirmed, Authority: mustSigner(t, alice), SourceATA: aliceATA, DestinationATA: bobATA, Mint: mint, Amount: 2_500_000, Decimals: 6,
+	})
+	if err != nil {
+		t.Fatalf("transfer token failed: %v", err)
+	}
+
+	// bob's token transfer only references his token account, not his wallet
+	entries, err := c.SyncHistory(ctx, models.SyncHistoryRequest{Address: bob.PublicKey, PageSize: 2})
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	tokenIn, solIn := entries[0], entries[1]
+	if tokenIn.Signature != tokenSig || tokenIn.Direction != models.HistoryIn || tokenIn.Counterparty != alice.PublicKey ||
+		tokenIn.Mint != mint || tokenIn.Amount.Value.String() != "2.5" || tokenIn.Fee != 0 || tokenIn.BlockTime.IsZero() {
+		t.Fatalf("unexpected token entry: %+v", tokenIn)
+	}
+	if solIn.Signature != solSig || solIn.Direction != models.HistoryIn || solIn.Counterparty != alice.PublicKey ||
+		solIn.Mint != "" || solIn.Amount.Value.String() != "0.02" {
+		t.Fatalf("unexpected SOL entry: %+v", solIn)
+	}
+
+	backSig, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, bob), ToPublicKey: alice.PublicKey, Lamports: 1_000_000})
+	if err != nil {
+		t.Fatalf("transfer back failed: %v", err)
+	}
+	entries, err = c.SyncHistory(ctx, models.SyncHistoryRequest{Address: bob.PublicKey, PageSize: 2})
+	if err != nil {
+		t.Fatalf("incremental sync failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Signature != backSig || entries[0].Direction != models.HistoryOut ||
+		entries[0].Counterparty != alice.PublicKey || entries[0].Fee != solanatest.LamportsPerSignature {
+		t.Fatalf("unexpected incremental entries: %+v", entries)
+	}
+
+	page, err := c.GetHistory(ctx, models.GetHistoryRequest{Address: bob.PublicKey, Limit: 2})
+	if err != nil {
+		t.Fatalf("get history failed: %v", err)
+	}
+	if len(page) != 2 || page[0].Signature != backSig || page[1].Signature != tokenSig {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+	page, err = c.GetHistory(ctx, models.GetHistoryRequest{Address: bob.PublicKey, Before: page[1].Signature, Limit: 2})
+	if err != nil {
+		t.Fatalf("get history failed: %v", err)
+	}
+	if len(page) != 1 || page[0].Signature != solSig {
+		t.Fatalf("unexpected second page: %+v", page)
+	}
+
+	// alice paid for every transaction; transactions without a transfer of hers only cost a fee
+	entries, err = c.SyncHistory(ctx, models.SyncHistoryRequest{Address: alice.PublicKey})
+	if err != nil {
+		t.Fatalf("sync alice failed: %v", err)
+	}
+	var fees uint64
+	directions := map[models.HistoryDirection]int{}
+	for _, e := range entries {
+		fees += e.Fee
+		directions[e.Direction]++
+	}
+	// creating the mint and both ATAs only cost fees; the airdrop is paid by the faucet
+	if directions[models.HistoryOut] != 2 || directions[models.HistoryIn] != 3 || directions[models.HistoryFee] != 3 {
+		t.Fatalf("unexpected directions: %v", directions)
+	}
+	// six transactions paid by alice; creating the mint is also signed by the mint account
+	if fees != 7*solanatest.LamportsPerSignature {
+		t.Fatalf("expected seven signature fees, got %d lamports", fees)
+	}
+}
@@ -0,0 +1,215 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// lookupTableMetaSize is the size of the lookup table header that precedes the addresses
+const lookupTableMetaSize = 56
+
+// maxExtendAddresses is how many addresses one transaction adds to a table, leaving room for
+// the create instruction, a separate authority and the compute budget instructions
+const maxExtendAddresses = 20
+
+// CreateLookupTable creates an address lookup table, filled with req.Addresses in the same
+// transaction; returns its address and the signature. Addresses become usable one slot later.
+func (c *Client) CreateLookupTable(ctx context.Context, req models.CreateLookupTableRequest) (string, string, error) {
+	op, table, err := c.createLookupTable(ctx, req)
+	if err != nil {
+		return "", "", err
+	}
+	sig, err := c.run(ctx, op)
+	if err != nil {
+		return "", "", err
+	}
+	return table, sig, nil
+}
+
+func (c *Client) createLookupTable(ctx context.Context, req models.CreateLookupTableRequest) (operation, string, error) {
+	payer, err := signerPublicKey(req.Payer)
+	if err != nil {
+		return operation{}, "", err
+	}
+	authoritySigner := req.Authority
+	if authoritySigner == nil {
+		authoritySigner = req.Payer
+	}
+	authority, err := signerPublicKey(authoritySigner)
+	if err != nil {
+		return operation{}, "", err
+	}
+
+	// the table address commits to a recent slot, so the same authority can create many tables.
+	// The program only accepts a slot in the SlotHashes of the bank that runs it, which a
+	// processed slot on another fork is not.
+	slot, err := c.c.GetSlotWithConfig(ctx, client.GetSlotConfig{Commitment: rpc.CommitmentFinalized})
+	if err != nil {
+		return operation{}, "", rpcError(err)
+	}
+	table, bump, err := common.FindProgramAddress([][]byte{authority.Bytes(), binary.LittleEndian.AppendUint64(nil, slot)}, common.AddressLookupTableProgramID)
+	if err != nil {
+		return operation{}, "", err
+	}
+
+	// CreateLookupTable: 0 (u32 LE), recent slot u64, bump u8
+	data := binary.LittleEndian.AppendUint64([]byte{0, 0, 0, 0}, slot)
+	data = append(data, bump)
+	instructions := []types.Instruction{{
+		ProgramID: common.AddressLookupTableProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: table, IsSigner: false, IsWritable: true},
+			{PubKey: authority, IsSigner: false, IsWritable: false},
+			{PubKey: payer, IsSigner: true, IsWritable: true},
+			{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false},
+		},
+		Data: data,
+	}}
+	op := operation{opts: req.SendOptions, feePayer: req.Payer}
+	if len(req.Addresses) > 0 {
+		if err := checkExtendSize(len(req.Addresses)); err != nil {
+			return operation{}, "", err
+		}
+		addresses, err := publicKeys(req.Addresses)
+		if err != nil {
+			return operation{}, "", err
+		}
+		instructions = append(instructions, extendLookupTableInstruction(table, authority, payer, addresses))
+		op.signers = []models.Signer{authoritySigner}
+	}
+	op.instructions = instructions
+	return op, table.ToBase58(), nil
+}
+
+// ExtendLookupTable appends addresses to a lookup table
+func (c *Client) ExtendLookupTable(ctx context.Context, req models.ExtendLookupTableRequest) (string, error) {
+	op, err := c.extendLookupTable(req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) extendLookupTable(req models.ExtendLookupTableRequest) (operation, error) {
+	payer, err := signerPublicKey(req.Payer)
+	if err != nil {
+		return operation{}, err
+	}
+	authority, err := signerPublicKey(req.Authority)
+	if err != nil {
+		return operation{}, err
+	}
+	if len(req.Addresses) == 0 {
+		return operation{}, fmt.Errorf("no addresses to add to lookup table %s", req.Table)
+	}
+	if err := checkExtendSize(len(req.Addresses)); err != nil {
+		return operation{}, err
+	}
+	table, err := publicKey(req.Table)
+	if err != nil {
+		return operation{}, err
+	}
+	addresses, err := publicKeys(req.Addresses)
+	if err != nil {
+		return operation{}, err
+	}
+	inst := extendLookupTableInstruction(table, authority, payer, addresses)
+	return operation{
+		opts:         req.SendOptions,
+		feePayer:     req.Payer,
+		instructions: []types.Instruction{inst},
+		signers:      []models.Signer{req.Authority},
+	}, nil
+}
+
+// checkExtendSize refuses more addresses than one transaction carries
+func checkExtendSize(n int) error {
+	if n > maxExtendAddresses {
+		return fmt.Errorf("%d addresses do not fit in one transaction; add at most %d at a time", n, maxExtendAddresses)
+	}
+	return nil
+}
+
+func extendLookupTableInstruction(table, authority, payer common.PublicKey, addresses []common.PublicKey) types.Instruction {
+	// ExtendLookupTable: 2 (u32 LE), address count u64, addresses
+	data := binary.LittleEndian.AppendUint64([]byte{2, 0, 0, 0}, uint64(len(addresses)))
+	for _, addr := range addresses {
+		data = append(data, addr.Bytes()...)
+	}
+	return types.Instruction{
+		ProgramID: common.AddressLookupTableProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: table, IsSigner: false, IsWritable: true},
+			{PubKey: authority, IsSigner: true, IsWritable: false},
+			{PubKey: payer, IsSigner: true, IsWritable: true},
+			{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false},
+		},
+		Data: data,
+	}
+}
+
+// GetLookupTable fetches and parses an address lookup table
+func (c *Client) GetLookupTable(ctx context.Context, req models.GetLookupTableRequest) (*models.LookupTable, error) {
+	if _, err := publicKey(req.Address); err != nil {
+		return nil, err
+	}
+	acc, err := c.c.GetAccountInfo(ctx, req.Address)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	if len(acc.Data) == 0 {
+		return nil, accountError(req.Address, ErrAccountNotFound)
+	}
+	if acc.Owner != common.AddressLookupTableProgramID || len(acc.Data) < lookupTableMetaSize ||
+		(len(acc.Data)-lookupTableMetaSize)%32 != 0 {
+		return nil, accountError(req.Address, fmt.Errorf("%w: not a lookup table", ErrInvalidAccountData))
+	}
+	// state u32, deactivation slot u64, last extended slot u64, start index u8,
+	// authority option, padding u16, then the addresses
+	if binary.LittleEndian.Uint32(acc.Data[0:4]) != 1 {
+		return nil, accountError(req.Address, fmt.Errorf("%w: lookup table is not initialized", ErrInvalidAccountData))
+	}
+	table := &models.LookupTable{
+		Address:          req.Address,
+		LastExtendedSlot: binary.LittleEndian.Uint64(acc.Data[12:20]),
+	}
+	if slot := binary.LittleEndian.Uint64(acc.Data[4:12]); slot != math.MaxUint64 {
+		table.DeactivationSlot = slot
+	}
+	if acc.Data[21] == 1 {
+		table.Authority = base58.Encode(acc.Data[22:54])
+	}
+	for off := lookupTableMetaSize; off < len(acc.Data); off += 32 {
+		table.Addresses = append(table.Addresses, base58.Encode(acc.Data[off:off+32]))
+	}
+	return table, nil
+}
+
+// lookupTableAccounts loads the tables a v0 message resolves accounts from
+func (c *Client) lookupTableAccounts(ctx context.Context, addresses []string) ([]types.AddressLookupTableAccount, error) {
+	tables := make([]types.AddressLookupTableAccount, 0, len(addresses))
+	for _, addr := range addresses {
+		table, err := c.GetLookupTable(ctx, models.GetLookupTableRequest{Address: addr})
+		if err != nil {
+			return nil, err
+		}
+		if table.DeactivationSlot != 0 {
+			return nil, fmt.Errorf("lookup table %s is deactivated", addr)
+		}
+		account := types.AddressLookupTableAccount{Key: common.PublicKeyFromString(addr)}
+		for _, a := range table.Addresses {
+			account.Addresses = append(account.Addresses, common.PublicKeyFromString(a))
+		}
+		tables = append(tables, account)
+	}
+	return tables, nil
+}
@@ -0,0 +1,104 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+func TestLookupTable_V0TransferDecodesLoadedAccounts(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	payer := c.CreateAccount()
+	recipient := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: payer.PublicKey, Lamports: 200_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, payer), MintAuthority: payer.PublicKey, Decimals: 6})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	source, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: payer.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create source ata failed: %v", err)
+	}
+	destination, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: recipient.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create destination ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, payer), Mint: mint, DestinationATA: source, Amount: 5_000_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	table, _, err := c.CreateLookupTable(ctx, models.CreateLookupTableRequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Addresses: []string{mint, source}})
+	if err != nil {
+		t.Fatalf("create lookup table failed: %v", err)
+	}
+	if _, err := c.ExtendLookupTable(ctx, models.ExtendLookupTableRequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Authority: mustSigner(t, payer), Table: table, Addresses: []string{destination}}); err != nil {
+		t.Fatalf("extend lookup table failed: %v", err)
+	}
+	lt, err := c.GetLookupTable(ctx, models.GetLookupTableRequest{Address: table})
+	if err != nil {
+		t.Fatalf("get lookup table failed: %v", err)
+	}
+	if lt.Authority != payer.PublicKey || len(lt.Addresses) != 3 || lt.Addresses[2] != destination || lt.DeactivationSlot != 0 {
+		t.Fatalf("unexpected lookup table: %+v", lt)
+	}
+
+	// one transaction carries at most 20 new addresses
+	var more []string
+	for range 21 {
+		more = append(more, c.CreateAccount().PublicKey)
+	}
+	if _, err := c.ExtendLookupTable(ctx, models.ExtendLookupTableRequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Authority: mustSigner(t, payer), Table: table, Addresses: more}); err == nil {
+		t.Fatal("expected 21 addresses to be refused")
+	}
+	if _, err := c.ExtendLookupTable(ctx, models.ExtendLookupTableRequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Authority: mustSigner(t, payer), Table: table, Addresses: more[:20]}); err != nil {
+		t.Fatalf("extend with 20 addresses failed: %v", err)
+	}
+	if _, _, err := c.CreateLookupTable(ctx, models.CreateLookupTableRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, payer), Authority: mustSigner(t, recipient), Addresses: more[1:],
+	}); err != nil {
+		t.Fatalf("create with 20 addresses and a separate authority failed: %v", err)
+	}
+
+	opts := confirmed
+	opts.LookupTables = []string{table}
+	sig, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: opts, Authority: mustSigner(t, payer), SourceATA: source, DestinationATA: destination, Mint: mint, Amount: 1_250_000, Decimals: 6,
+	})
+	if err != nil {
+		t.Fatalf("v0 transfer failed: %v", err)
+	}
+
+	raw, err := client.NewClient(srv.URL).GetTransaction(ctx, sig)
+	if err != nil {
+		t.Fatalf("get transaction failed: %v", err)
+	}
+	if raw.Version() != types.MessageVersionV0 || len(raw.Meta.LoadedAddresses.Writable)+len(raw.Meta.LoadedAddresses.Readonly) != 3 {
+		t.Fatalf("expected a v0 transaction loading 3 addresses, got %v with %+v", raw.Version(), raw.Meta.LoadedAddresses)
+	}
+
+	transfers, err := c.GetTransactionTransfersSPL(ctx, models.GetTransactionTransfersRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get transfers failed: %v", err)
+	}
+	if len(transfers) != 1 {
+		t.Fatalf("expected one transfer, got %d", len(transfers))
+	}
+	tr := transfers[0]
+	if tr.Source != source || tr.Destination != destination || tr.Mint != mint || tr.Authority != payer.PublicKey || tr.Amount.Value.String() != "1.25" {
+		t.Fatalf("unexpected transfer: %+v", tr)
+	}
+	if ta, err := c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: destination}); err != nil || ta.Amount != 1_250_000 {
+		t.Fatalf("unexpected destination account: %+v, %v", ta, err)
+	}
+}
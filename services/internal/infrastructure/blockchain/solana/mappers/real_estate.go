@@ -0,0 +1,67 @@
+package mappers
+
+import (
+	"fmt"
+	"mime"
+	"path"
+	"sort"
+
+	domain "github.com/whiteelite/superapp/internal/domain/entities"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// ToDeedMetadata renders the off-chain metadata document of a real-estate deed NFT. Only public
+// images are published; KYC and private images never leave the platform. The owner is left out,
+// as the deed token itself records it.
+func ToDeedMetadata(name, symbol string, estate domain.RealEstate) models.OffChainMetadata {
+	doc := models.OffChainMetadata{
+		Name:   name,
+		Symbol: symbol,
+		Description: fmt.Sprintf("Deed of a %s owned property at %s, %s",
+			estate.OwnerType, estate.GeoPoint.Latitude, estate.GeoPoint.Longitude),
+		Attributes: []models.MetadataAttribute{
+			{TraitType: "latitude", Value: estate.GeoPoint.Latitude.String()},
+			{TraitType: "longitude", Value: estate.GeoPoint.Longitude.String()},
+			{TraitType: "owner_type", Value: string(estate.OwnerType)},
+			{TraitType: "rent", Value: fmt.Sprint(estate.Rent)},
+		},
+	}
+	if estate.Rooms != nil {
+		doc.Attributes = append(doc.Attributes, models.MetadataAttribute{TraitType: "rooms", Value: estate.Rooms.Count})
+	}
+
+	// map order is random; keep the document stable so it hashes the same every time
+	types := make([]string, 0, len(estate.Info))
+	for t := range estate.Info {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		info := estate.Info[domain.RealEstateType(t)]
+		if info == nil {
+			continue
+		}
+		doc.Attributes = append(doc.Attributes, models.MetadataAttribute{TraitType: t, Value: string(info.Content)})
+		if info.RealEstateSubType != nil {
+			doc.Attributes = append(doc.Attributes, models.MetadataAttribute{TraitType: t + "_subtype", Value: string(*info.RealEstateSubType)})
+		}
+	}
+
+	images := estate.Images
+	if estate.Rooms != nil {
+		images = append(append([]domain.Image(nil), images...), estate.Rooms.Images...)
+	}
+	var files []models.MetadataFile
+	for _, img := range images {
+		if img.IsKYC || img.PublicImage == "" {
+			continue
+		}
+		uri := string(img.PublicImage)
+		files = append(files, models.MetadataFile{URI: uri, Type: mime.TypeByExtension(path.Ext(uri))})
+	}
+	if len(files) > 0 {
+		doc.Image = files[0].URI
+		doc.Properties = &models.MetadataProperties{Files: files, Category: "image"}
+	}
+	return doc
+}
@@ -0,0 +1,53 @@
+package sdk
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// memoV1ProgramID is the original SPL Memo program; older transactions still carry it
+var memoV1ProgramID = common.PublicKeyFromString("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
+// maxMemoLength is the longest memo, in bytes, that fits in a transaction holding nothing but the
+// memo and one signer, as the SPL Memo program documents. With a transfer and references less
+// fits; prepare checks the finished transaction against maxTransactionSize.
+const maxMemoLength = 566
+
+func isMemoProgram(program common.PublicKey) bool {
+	return program == common.MemoProgramID || program == memoV1ProgramID
+}
+
+// annotateTransfer puts an SPL Memo v2 instruction in front of a transfer and appends the
+// references to it as read-only accounts, so the transfer can be found by them
+func annotateTransfer(transfer types.Instruction, memo string, references []string) ([]types.Instruction, error) {
+	seen := make(map[common.PublicKey]bool, len(transfer.Accounts)+len(references))
+	for _, meta := range transfer.Accounts {
+		seen[meta.PubKey] = true
+	}
+	for _, r := range references {
+		key, err := publicKey(r)
+		if err != nil {
+			return nil, err
+		}
+		// a key the transfer already uses would not stay read-only
+		if seen[key] {
+			return nil, fmt.Errorf("reference %s is already an account of the transfer", r)
+		}
+		seen[key] = true
+		transfer.Accounts = append(transfer.Accounts, types.AccountMeta{PubKey: key, IsSigner: false, IsWritable: false})
+	}
+	if memo == "" {
+		return []types.Instruction{transfer}, nil
+	}
+	if !utf8.ValidString(memo) {
+		return nil, fmt.Errorf("memo is not valid UTF-8")
+	}
+	if len(memo) > maxMemoLength {
+		return nil, fmt.Errorf("%w: the memo is %d bytes, at most %d fit", ErrTransactionTooLarge, len(memo), maxMemoLength)
+	}
+	// the memo takes no accounts, so it needs no signature besides the transaction's
+	return []types.Instruction{{ProgramID: common.MemoProgramID, Data: []byte(memo)}, transfer}, nil
+}
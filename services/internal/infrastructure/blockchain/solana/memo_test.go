@@ -0,0 +1,130 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+func TestMemo_TransfersCarryMemoAndReferences(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	bob := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	// the reference is a fresh key standing for the transfer entity
+	ref := c.CreateAccount().PublicKey
+	const memo = "wallet-transfer:6b1f0c2e-8f7a-4c55-9d0e-2a7d3c1b9e44"
+	sig, err := c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: confirmed, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 1_000_000, Memo: memo, References: []string{ref},
+	})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	found, err := c.GetSignaturesForAddress(ctx, models.GetSignaturesForAddressRequest{Address: ref})
+	if err != nil {
+		t.Fatalf("get signatures failed: %v", err)
+	}
+	if len(found) != 1 || found[0].Signature != sig || found[0].Memo != fmt.Sprintf("[%d] %s", len(memo), memo) {
+		t.Fatalf("expected the transfer under its reference, got %+v", found)
+	}
+	events, err := c.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get events failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected a memo and a transfer, got %+v", events)
+	}
+	if ev, ok := events[0].(*models.MemoEvent); !ok || ev.Type != models.EventMemo || ev.Memo != memo || len(ev.Signers) != 0 {
+		t.Fatalf("unexpected memo event: %+v", events[0])
+	}
+	if ev, ok := events[1].(*models.SOLTransferEvent); !ok || ev.Memo != memo || len(ev.References) != 1 || ev.References[0] != ref {
+		t.Fatalf("unexpected transfer event: %+v", events[1])
+	}
+
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 2})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	aliceATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: alice.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	bobATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: bob.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, alice), Mint: mint, DestinationATA: aliceATA, Amount: 1_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	if _, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: confirmed, Authority: mustSigner(t, alice), SourceATA: aliceATA, DestinationATA: bobATA, Mint: mint, Amount: 1, Decimals: 2,
+		References: []string{bobATA},
+	}); err == nil {
+		t.Fatal("expected a reference that is already a transfer account to be refused")
+	}
+	refs := []string{c.CreateAccount().PublicKey, c.CreateAccount().PublicKey}
+	sig, err = c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: confirmed, Authority: mustSigner(t, alice), SourceATA: aliceATA, DestinationATA: bobATA, Mint: mint, Amount: 250, Decimals: 2,
+		Memo: "crypto-transfer:42", References: refs,
+	})
+	if err != nil {
+		t.Fatalf("token transfer failed: %v", err)
+	}
+	transfers, err := c.GetTransactionTransfersSPL(ctx, models.GetTransactionTransfersRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get transfers failed: %v", err)
+	}
+	if len(transfers) != 1 || transfers[0].Memo != "crypto-transfer:42" || len(transfers[0].References) != 2 ||
+		transfers[0].References[0] != refs[0] || transfers[0].References[1] != refs[1] || transfers[0].Amount.Raw != 250 {
+		t.Fatalf("unexpected transfers: %+v", transfers)
+	}
+
+	// the memo has to fit in the transaction next to the transfer
+	if _, err := c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: confirmed, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 1_000, Memo: strings.Repeat("m", 566), References: []string{ref},
+	}); err != nil {
+		t.Fatalf("expected the longest memo to fit, got %v", err)
+	}
+	if _, err := c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: confirmed, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 1_000, Memo: strings.Repeat("m", 567),
+	}); !errors.Is(err, sdk.ErrTransactionTooLarge) || !strings.Contains(err.Error(), "567 bytes") {
+		t.Fatalf("expected a memo too long to be refused, got %v", err)
+	}
+	// a memo that fits alone may not with many references
+	var many []string
+	for range 16 {
+		many = append(many, c.CreateAccount().PublicKey)
+	}
+	if _, err := c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: confirmed, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 1_000, Memo: strings.Repeat("m", 500), References: many,
+	}); !errors.Is(err, sdk.ErrTransactionTooLarge) {
+		t.Fatalf("expected the transaction to be too large, got %v", err)
+	}
+
+	// a plain transfer carries neither
+	sig, err = c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 1_000})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	if events, err = c.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: sig}); err != nil || len(events) != 1 {
+		t.Fatalf("unexpected events %+v: %v", events, err)
+	}
+	if ev := events[0].(*models.SOLTransferEvent); ev.Memo != "" || len(ev.References) != 0 {
+		t.Fatalf("unexpected plain transfer: %+v", ev)
+	}
+}
@@ -0,0 +1,542 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+var tokenMetadataProgramID = common.PublicKeyFromString("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
+
+// Limits of the Metaplex Token Metadata program
+const (
+	metadataKeyV1           = 4
+	metadataMaxNameLength   = 32
+	metadataMaxSymbolLength = 10
+	metadataMaxURILength    = 200
+	metadataMaxCreators     = 5
+	maxBasisPoints          = 10_000
+)
+
+// Token Metadata instruction discriminators
+const (
+	metadataInstructionUpdateV2 = 15
+	metadataInstructionCreateV3 = 33
+)
+
+// metadataData is the DataV2 of Token Metadata instructions
+type metadataData struct {
+	Name                 string
+	Symbol               string
+	URI                  string
+	SellerFeeBasisPoints uint16
+	Creators             []models.Creator
+	Collection           *models.Collection
+	Uses                 *models.Uses
+}
+
+// GetTokenMetadata fetches and decodes the Metaplex metadata of a mint, with the mint decimals
+func (c *Client) GetTokenMetadata(ctx context.Context, req models.GetTokenMetadataRequest) (*models.TokenMetadata, error) {
+	mint, err := publicKey(req.Mint)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := c.metadataAccount(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+	dec, err := c.GetMintDecimals(ctx, models.GetMintDecimalsRequest{Mint: req.Mint})
+	if err != nil {
+		return nil, err
+	}
+	meta.Decimals = dec
+	return meta, nil
+}
+
+func (c *Client) metadataAccount(ctx context.Context, mint common.PublicKey) (*models.TokenMetadata, error) {
+	metaPDA, err := deriveMetadataPDA(mint)
+	if err != nil {
+		return nil, err
+	}
+	address := metaPDA.ToBase58()
+	acc, err := c.c.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return decodeMetadataAccount(address, acc)
+}
+
+func decodeMetadataAccount(address string, acc client.AccountInfo) (*models.TokenMetadata, error) {
+	if len(acc.Data) == 0 {
+		return nil, accountError(address, ErrAccountNotFound)
+	}
+	if acc.Owner != tokenMetadataProgramID {
+		return nil, accountError(address, fmt.Errorf("%w: not owned by the Token Metadata program", ErrInvalidAccountData))
+	}
+	meta, err := parseMetadata(acc.Data)
+	if err != nil {
+		return nil, accountError(address, fmt.Errorf("%w: %v", ErrInvalidAccountData, err))
+	}
+	meta.Address = address
+	return meta, nil
+}
+
+// parseMetadata decodes a Metadata account. Metaplex pads the strings with zero bytes to their
+// maximum length, and accounts created by older versions end before the newer optional fields.
+func parseMetadata(data []byte) (*models.TokenMetadata, error) {
+	r := &borshReader{data: data}
+	if key := r.u8(); r.err == nil && key != metadataKeyV1 {
+		return nil, fmt.Errorf("not a metadata account (key %d)", key)
+	}
+	meta := &models.TokenMetadata{
+		UpdateAuthority: r.key(),
+		Mint:            r.key(),
+		Name:            trimPadding(r.str()),
+		Symbol:          trimPadding(r.str()),
+		URI:             trimPadding(r.str()),
+	}
+	meta.SellerFeeBasisPoints = r.u16()
+	if r.option() {
+		n := r.u32()
+		for i := uint32(0); i < n && r.err == nil; i++ {
+			meta.Creators = append(meta.Creators, models.Creator{Address: r.key(), Verified: r.flag(), Share: r.u8()})
+		}
+	}
+	meta.PrimarySaleHappened = r.flag()
+	meta.IsMutable = r.flag()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if r.more() && r.option() {
+		nonce := r.u8()
+		meta.EditionNonce = &nonce
+	}
+	if r.more() && r.option() {
+		standard := models.TokenStandard(r.u8())
+		meta.TokenStandard = &standard
+	}
+	if r.more() && r.option() {
+		meta.Collection = &models.Collection{Verified: r.flag(), Key: r.key()}
+	}
+	if r.more() && r.option() {
+		meta.Uses = &models.Uses{UseMethod: models.UseMethod(r.u8()), Remaining: r.u64(), Total: r.u64()}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return meta, nil
+}
+
+func trimPadding(s string) string {
+	return strings.TrimRight(s, "\x00")
+}
+
+// CreateTokenMetadata creates the Metaplex metadata of a mint with CreateMetadataAccountV3;
+// returns the metadata address and the signature
+func (c *Client) CreateTokenMetadata(ctx context.Context, req models.CreateTokenMetadataRequest) (string, string, error) {
+	op, address, err := c.createTokenMetadata(req)
+	if err != nil {
+		return "", "", err
+	}
+	sig, err := c.run(ctx, op)
+	if err != nil {
+		return "", "", err
+	}
+	return address, sig, nil
+}
+
+func (c *Client) createTokenMetadata(req models.CreateTokenMetadataRequest) (operation, string, error) {
+	payer, err := signerPublicKey(req.Payer)
+	if err != nil {
+		return operation{}, "", err
+	}
+	mintAuthoritySigner := req.MintAuthority
+	if mintAuthoritySigner == nil {
+		mintAuthoritySigner = req.Payer
+	}
+	mintAuthority, err := signerPublicKey(mintAuthoritySigner)
+	if err != nil {
+		return operation{}, "", err
+	}
+	mint, err := publicKey(req.Mint)
+	if err != nil {
+		return operation{}, "", err
+	}
+	updateAuthority := mintAuthority
+	if req.UpdateAuthority != "" {
+		if updateAuthority, err = publicKey(req.UpdateAuthority); err != nil {
+			return operation{}, "", err
+		}
+	}
+	// the update authority signs when the request holds its key
+	updateAuthoritySigns := updateAuthority == mintAuthority || updateAuthority == payer
+
+	d := metadataData{
+		Name:                 req.Name,
+		Symbol:               req.Symbol,
+		URI:                  req.URI,
+		SellerFeeBasisPoints: req.SellerFeeBasisPoints,
+		Creators:             req.Creators,
+		Collection:           req.Collection,
+		Uses:                 req.Uses,
+	}
+	if err := d.validate(); err != nil {
+		return operation{}, "", err
+	}
+	for _, cr := range d.Creators {
+		if cr.Verified && (cr.Address != updateAuthority.ToBase58() || !updateAuthoritySigns) {
+			return operation{}, "", fmt.Errorf("creator %s can only be verified by signing itself", cr.Address)
+		}
+	}
+	if d.Collection != nil && d.Collection.Verified {
+		return operation{}, "", fmt.Errorf("a collection is verified by its update authority once the metadata exists")
+	}
+
+	metadataPDA, err := deriveMetadataPDA(mint)
+	if err != nil {
+		return operation{}, "", err
+	}
+	// CreateMetadataAccountV3: 33, DataV2, is_mutable bool, collection_details None
+	data, err := d.appendTo([]byte{metadataInstructionCreateV3})
+	if err != nil {
+		return operation{}, "", err
+	}
+	data = appendBool(data, !req.Immutable)
+	data = append(data, 0)
+
+	inst := types.Instruction{
+		ProgramID: tokenMetadataProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: metadataPDA, IsSigner: false, IsWritable: true},
+			{PubKey: mint, IsSigner: false, IsWritable: false},
+			{PubKey: mintAuthority, IsSigner: true, IsWritable: false},
+			{PubKey: payer, IsSigner: true, IsWritable: true},
+			{PubKey: updateAuthority, IsSigner: updateAuthoritySigns, IsWritable: false},
+			{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false},
+		},
+		Data: data,
+	}
+	return operation{
+		opts:         req.SendOptions,
+		feePayer:     req.Payer,
+		instructions: []types.Instruction{inst},
+		signers:      []models.Signer{mintAuthoritySigner},
+	}, metadataPDA.ToBase58(), nil
+}
+
+// SetTokenMetadata updates Metaplex metadata with UpdateMetadataAccountV2. The instruction
+// replaces the whole data, so the current metadata is read first and the fields the request
+// leaves nil are sent unchanged.
+func (c *Client) SetTokenMetadata(ctx context.Context, req models.SetTokenMetadataRequest) (string, error) {
+	op, err := c.setTokenMetadata(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) setTokenMetadata(ctx context.Context, req models.SetTokenMetadataRequest) (operation, error) {
+	updateAuth, err := signerPublicKey(req.UpdateAuthority)
+	if err != nil {
+		return operation{}, err
+	}
+	mint, err := publicKey(req.Mint)
+	if err != nil {
+		return operation{}, err
+	}
+	var newAuthority common.PublicKey
+	if req.NewUpdateAuthority != "" {
+		if newAuthority, err = publicKey(req.NewUpdateAuthority); err != nil {
+			return operation{}, err
+		}
+	}
+	current, err := c.metadataAccount(ctx, mint)
+	if err != nil {
+		return operation{}, err
+	}
+
+	changesData := req.Name != nil || req.Symbol != nil || req.URI != nil || req.SellerFeeBasisPoints != nil ||
+		req.Creators != nil || req.Collection != nil || req.Uses != nil
+	if !changesData && req.NewUpdateAuthority == "" && !req.PrimarySaleHappened && !req.Immutable {
+		return operation{}, fmt.Errorf("metadata update changes nothing")
+	}
+
+	data := []byte{metadataInstructionUpdateV2}
+	if changesData {
+		d, err := mergeMetadata(current, req, updateAuth.ToBase58())
+		if err != nil {
+			return operation{}, err
+		}
+		data = append(data, 1) // Some(DataV2)
+		if data, err = d.appendTo(data); err != nil {
+			return operation{}, err
+		}
+	} else {
+		data = append(data, 0)
+	}
+	if req.NewUpdateAuthority != "" {
+		data = append(append(data, 1), newAuthority.Bytes()...)
+	} else {
+		data = append(data, 0)
+	}
+	if req.PrimarySaleHappened {
+		data = append(data, 1, 1)
+	} else {
+		data = append(data, 0)
+	}
+	if req.Immutable {
+		data = append(data, 1, 0)
+	} else {
+		data = append(data, 0)
+	}
+
+	metadataPDA, err := deriveMetadataPDA(mint)
+	if err != nil {
+		return operation{}, err
+	}
+	inst := types.Instruction{
+		ProgramID: tokenMetadataProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: metadataPDA, IsSigner: false, IsWritable: true},
+			{PubKey: updateAuth, IsSigner: true, IsWritable: false},
+		},
+		Data: data,
+	}
+
+	return operation{opts: req.SendOptions, feePayer: req.UpdateAuthority, instructions: []types.Instruction{inst}}, nil
+}
+
+// mergeMetadata applies the fields a request sets to the current metadata. Verification can
+// only change for the signing authority itself, and collections are verified separately.
+func mergeMetadata(current *models.TokenMetadata, req models.SetTokenMetadataRequest, signer string) (metadataData, error) {
+	d := metadataData{
+		Name:                 current.Name,
+		Symbol:               current.Symbol,
+		URI:                  current.URI,
+		SellerFeeBasisPoints: current.SellerFeeBasisPoints,
+		Creators:             current.Creators,
+		Collection:           current.Collection,
+		Uses:                 current.Uses,
+	}
+	if req.Name != nil {
+		d.Name = *req.Name
+	}
+	if req.Symbol != nil {
+		d.Symbol = *req.Symbol
+	}
+	if req.URI != nil {
+		d.URI = *req.URI
+	}
+	if req.SellerFeeBasisPoints != nil {
+		d.SellerFeeBasisPoints = *req.SellerFeeBasisPoints
+	}
+	if req.Creators != nil {
+		verified := map[string]bool{}
+		for _, cr := range current.Creators {
+			verified[cr.Address] = cr.Verified
+		}
+		for _, cr := range *req.Creators {
+			if cr.Verified != verified[cr.Address] && cr.Address != signer {
+				return metadataData{}, fmt.Errorf("creator %s can only change its verification by signing itself", cr.Address)
+			}
+		}
+		d.Creators = *req.Creators
+	}
+	if req.Collection != nil {
+		if req.Collection.Verified && (current.Collection == nil || !current.Collection.Verified || current.Collection.Key != req.Collection.Key) {
+			return metadataData{}, fmt.Errorf("a collection is verified by its update authority, not by a metadata update")
+		}
+		d.Collection = req.Collection
+	}
+	if req.Uses != nil {
+		d.Uses = req.Uses
+	}
+	if err := d.validate(); err != nil {
+		return metadataData{}, err
+	}
+	return d, nil
+}
+
+// validate applies the limits the program enforces, so a bad request fails before it is sent
+func (d metadataData) validate() error {
+	switch {
+	case len(d.Name) > metadataMaxNameLength:
+		return fmt.Errorf("name is longer than %d bytes", metadataMaxNameLength)
+	case len(d.Symbol) > metadataMaxSymbolLength:
+		return fmt.Errorf("symbol is longer than %d bytes", metadataMaxSymbolLength)
+	case len(d.URI) > metadataMaxURILength:
+		return fmt.Errorf("uri is longer than %d bytes", metadataMaxURILength)
+	case d.SellerFeeBasisPoints > maxBasisPoints:
+		return fmt.Errorf("seller fee of %d basis points is over %d", d.SellerFeeBasisPoints, maxBasisPoints)
+	case len(d.Creators) > metadataMaxCreators:
+		return fmt.Errorf("at most %d creators are allowed", metadataMaxCreators)
+	}
+	if len(d.Creators) == 0 {
+		return nil
+	}
+	total := 0
+	seen := map[string]bool{}
+	for _, cr := range d.Creators {
+		if seen[cr.Address] {
+			return fmt.Errorf("creator %s is listed twice", cr.Address)
+		}
+		seen[cr.Address] = true
+		total += int(cr.Share)
+	}
+	if total != 100 {
+		return fmt.Errorf("creator shares add up to %d instead of 100", total)
+	}
+	return nil
+}
+
+// appendTo encodes DataV2; an empty creator list is sent as None, which the program requires
+func (d metadataData) appendTo(data []byte) ([]byte, error) {
+	data = appendBorshString(data, d.Name)
+	data = appendBorshString(data, d.Symbol)
+	data = appendBorshString(data, d.URI)
+	data = binary.LittleEndian.AppendUint16(data, d.SellerFeeBasisPoints)
+	if len(d.Creators) == 0 {
+		data = append(data, 0)
+	} else {
+		data = binary.LittleEndian.AppendUint32(append(data, 1), uint32(len(d.Creators)))
+		for _, cr := range d.Creators {
+			key, err := publicKey(cr.Address)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, key.Bytes()...)
+			data = appendBool(data, cr.Verified)
+			data = append(data, cr.Share)
+		}
+	}
+	if col := d.Collection; col != nil {
+		key, err := publicKey(col.Key)
+		if err != nil {
+			return nil, err
+		}
+		data = appendBool(append(data, 1), col.Verified)
+		data = append(data, key.Bytes()...)
+	} else {
+		data = append(data, 0)
+	}
+	if u := d.Uses; u != nil {
+		data = append(data, 1, byte(u.UseMethod))
+		data = binary.LittleEndian.AppendUint64(data, u.Remaining)
+		data = binary.LittleEndian.AppendUint64(data, u.Total)
+	} else {
+		data = append(data, 0)
+	}
+	return data, nil
+}
+
+func deriveMetadataPDA(mint common.PublicKey) (common.PublicKey, error) {
+	seeds := [][]byte{
+		[]byte("metadata"),
+		tokenMetadataProgramID.Bytes(),
+		mint.Bytes(),
+	}
+	pda, _, err := common.FindProgramAddress(seeds, tokenMetadataProgramID)
+	if err != nil {
+		return common.PublicKey{}, err
+	}
+	return pda, nil
+}
+
+func appendBorshString(data []byte, s string) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(s)))
+	return append(data, s...)
+}
+
+func appendBool(data []byte, v bool) []byte {
+	if v {
+		return append(data, 1)
+	}
+	return append(data, 0)
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of data")
+
+// borshReader decodes Borsh fields in order; after the first short read every read returns
+// the zero value and err is set
+type borshReader struct {
+	data []byte
+	off  int
+	err  error
+}
+
+func (r *borshReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data)-r.off < n {
+		r.err = errUnexpectedEnd
+		return nil
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// more reports whether data is left to read
+func (r *borshReader) more() bool {
+	return r.err == nil && r.off < len(r.data)
+}
+
+func (r *borshReader) u8() uint8 {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *borshReader) flag() bool {
+	return r.u8() != 0
+}
+
+// option reads the tag of an Option and reports whether a value follows
+func (r *borshReader) option() bool {
+	return r.u8() == 1
+}
+
+func (r *borshReader) u16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *borshReader) u32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *borshReader) u64() uint64 {
+	if b := r.next(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// key reads a public key as base58
+func (r *borshReader) key() string {
+	if b := r.next(32); b != nil {
+		return base58.Encode(b)
+	}
+	return ""
+}
+
+func (r *borshReader) str() string {
+	n := r.u32()
+	return string(r.next(int(n)))
+}
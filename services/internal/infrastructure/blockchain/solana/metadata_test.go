@@ -0,0 +1,181 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+func TestMetadata_CreateAndParse(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	artist := c.CreateAccount()
+	collection := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 0})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+
+	_, err = c.GetTokenMetadata(ctx, models.GetTokenMetadataRequest{Mint: mint})
+	if !errors.Is(err, sdk.ErrAccountNotFound) {
+		t.Fatalf("expected no metadata yet, got %v", err)
+	}
+
+	address, _, err := c.CreateTokenMetadata(ctx, models.CreateTokenMetadataRequest{
+		SendOptions:          confirmed,
+		Payer:                mustSigner(t, alice),
+		Mint:                 mint,
+		Name:                 "Deed #1",
+		Symbol:               "DEED",
+		URI:                  "https://example.com/deed/1.json",
+		SellerFeeBasisPoints: 250,
+		Creators: []models.Creator{
+			{Address: alice.PublicKey, Verified: true, Share: 60},
+			{Address: artist.PublicKey, Share: 40},
+		},
+		Collection: &models.Collection{Key: collection.PublicKey},
+		Uses:       &models.Uses{UseMethod: models.UseMethodMultiple, Remaining: 3, Total: 3},
+	})
+	if err != nil {
+		t.Fatalf("create metadata failed: %v", err)
+	}
+
+	meta, err := c.GetTokenMetadata(ctx, models.GetTokenMetadataRequest{Mint: mint})
+	if err != nil {
+		t.Fatalf("get metadata failed: %v", err)
+	}
+	if meta.Address != address || meta.Mint != mint || meta.UpdateAuthority != alice.PublicKey ||
+		meta.Name != "Deed #1" || meta.Symbol != "DEED" || meta.URI != "https://example.com/deed/1.json" ||
+		meta.SellerFeeBasisPoints != 250 || meta.PrimarySaleHappened || !meta.IsMutable || meta.EditionNonce == nil {
+		t.Fatalf("unexpected metadata: %+v", meta)
+	}
+	if len(meta.Creators) != 2 || meta.Creators[0] != (models.Creator{Address: alice.PublicKey, Verified: true, Share: 60}) ||
+		meta.Creators[1] != (models.Creator{Address: artist.PublicKey, Share: 40}) {
+		t.Fatalf("unexpected creators: %+v", meta.Creators)
+	}
+	if meta.Collection == nil || meta.Collection.Key != collection.PublicKey || meta.Collection.Verified ||
+		meta.Uses == nil || *meta.Uses != (models.Uses{UseMethod: models.UseMethodMultiple, Remaining: 3, Total: 3}) {
+		t.Fatalf("unexpected collection or uses: %+v %+v", meta.Collection, meta.Uses)
+	}
+	if meta.TokenStandard == nil || *meta.TokenStandard != models.TokenStandardFungibleAsset {
+		t.Fatalf("unexpected token standard: %v", meta.TokenStandard)
+	}
+
+	// a second creation fails in the program
+	_, _, err = c.CreateTokenMetadata(ctx, models.CreateTokenMetadataRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Mint: mint, Name: "Again"})
+	var txErr *sdk.TransactionError
+	if !errors.As(err, &txErr) || txErr.Name != "AlreadyInitialized" {
+		t.Fatalf("expected already initialized, got %v", err)
+	}
+
+	// only the mint authority can create metadata
+	other, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: artist.PublicKey, Decimals: 6})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	_, _, err = c.CreateTokenMetadata(ctx, models.CreateTokenMetadataRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Mint: other, Name: "Other"})
+	if !errors.As(err, &txErr) || txErr.Name != "InvalidMintAuthority" {
+		t.Fatalf("expected an invalid mint authority, got %v", err)
+	}
+
+	_, _, err = c.CreateTokenMetadata(ctx, models.CreateTokenMetadataRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, alice), Mint: mint, Name: "Deed",
+		Creators: []models.Creator{{Address: artist.PublicKey, Verified: true, Share: 100}},
+	})
+	if err == nil || errors.As(err, &txErr) {
+		t.Fatalf("expected a verified creator that does not sign to be refused, got %v", err)
+	}
+}
+
+func TestMetadata_UpdatePreservesUnchangedFields(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	bob := c.CreateAccount()
+	artist := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: bob.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 6})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	if _, _, err := c.CreateTokenMetadata(ctx, models.CreateTokenMetadataRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, alice), Mint: mint,
+		Name: "Fund Share", Symbol: "FUND", URI: "https://example.com/fund.json", SellerFeeBasisPoints: 100,
+		Creators: []models.Creator{{Address: alice.PublicKey, Verified: true, Share: 50}, {Address: artist.PublicKey, Share: 50}},
+	}); err != nil {
+		t.Fatalf("create metadata failed: %v", err)
+	}
+
+	uri := "https://example.com/fund-v2.json"
+	if _, err := c.SetTokenMetadata(ctx, models.SetTokenMetadataRequest{
+		SendOptions: confirmed, UpdateAuthority: mustSigner(t, alice), Mint: mint, URI: &uri, PrimarySaleHappened: true,
+	}); err != nil {
+		t.Fatalf("set metadata failed: %v", err)
+	}
+	meta, err := c.GetTokenMetadata(ctx, models.GetTokenMetadataRequest{Mint: mint})
+	if err != nil {
+		t.Fatalf("get metadata failed: %v", err)
+	}
+	if meta.URI != uri || meta.Name != "Fund Share" || meta.Symbol != "FUND" || meta.SellerFeeBasisPoints != 100 ||
+		len(meta.Creators) != 2 || !meta.Creators[0].Verified || !meta.PrimarySaleHappened || !meta.IsMutable || meta.Decimals != 6 ||
+		meta.TokenStandard == nil || *meta.TokenStandard != models.TokenStandardFungible {
+		t.Fatalf("update did not preserve the other fields: %+v", meta)
+	}
+
+	// verification of another creator cannot be changed
+	creators := []models.Creator{{Address: alice.PublicKey, Verified: true, Share: 50}, {Address: artist.PublicKey, Verified: true, Share: 50}}
+	if _, err := c.SetTokenMetadata(ctx, models.SetTokenMetadataRequest{
+		SendOptions: confirmed, UpdateAuthority: mustSigner(t, alice), Mint: mint, Creators: &creators,
+	}); err == nil {
+		t.Fatal("expected verifying another creator to fail")
+	}
+
+	if _, err := c.SetTokenMetadata(ctx, models.SetTokenMetadataRequest{
+		SendOptions: confirmed, UpdateAuthority: mustSigner(t, alice), Mint: mint, NewUpdateAuthority: bob.PublicKey,
+	}); err != nil {
+		t.Fatalf("hand over failed: %v", err)
+	}
+	name := "Renamed"
+	_, err = c.SetTokenMetadata(ctx, models.SetTokenMetadataRequest{SendOptions: confirmed, UpdateAuthority: mustSigner(t, alice), Mint: mint, Name: &name})
+	var txErr *sdk.TransactionError
+	if !errors.As(err, &txErr) || txErr.Name != "UpdateAuthorityIncorrect" {
+		t.Fatalf("expected the old authority to be refused, got %v", err)
+	}
+
+	if _, err := c.SetTokenMetadata(ctx, models.SetTokenMetadataRequest{
+		SendOptions: confirmed, UpdateAuthority: mustSigner(t, bob), Mint: mint, Name: &name, Immutable: true,
+	}); err != nil {
+		t.Fatalf("set metadata failed: %v", err)
+	}
+	meta, err = c.GetTokenMetadata(ctx, models.GetTokenMetadataRequest{Mint: mint})
+	if err != nil {
+		t.Fatalf("get metadata failed: %v", err)
+	}
+	if meta.Name != name || meta.UpdateAuthority != bob.PublicKey || meta.IsMutable || meta.URI != uri {
+		t.Fatalf("unexpected metadata: %+v", meta)
+	}
+	if _, err := c.SetTokenMetadata(ctx, models.SetTokenMetadataRequest{SendOptions: confirmed, UpdateAuthority: mustSigner(t, bob), Mint: mint, URI: &uri}); err == nil {
+		t.Fatal("expected immutable metadata to refuse updates")
+	}
+}
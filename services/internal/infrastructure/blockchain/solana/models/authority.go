@@ -0,0 +1,47 @@
+package models
+
+// AuthorityType is the authority changed by a token SetAuthority instruction
+type AuthorityType uint8
+
+const (
+	AuthorityMintTokens AuthorityType = iota
+	AuthorityFreezeAccount
+	AuthorityAccountOwner
+	AuthorityCloseAccount
+	AuthorityTransferFeeConfig
+	AuthorityWithheldWithdraw
+	AuthorityCloseMint
+	AuthorityInterestRate
+	AuthorityPermanentDelegate
+	AuthorityConfidentialTransferMint
+	AuthorityTransferHookProgramID
+	AuthorityConfidentialTransferFeeConfig
+	AuthorityMetadataPointer
+	AuthorityGroupPointer
+	AuthorityGroupMemberPointer
+)
+
+var authorityTypeNames = [...]string{
+	"mintTokens",
+	"freezeAccount",
+	"accountOwner",
+	"closeAccount",
+	"transferFeeConfig",
+	"withheldWithdraw",
+	"closeMint",
+	"interestRate",
+	"permanentDelegate",
+	"confidentialTransferMint",
+	"transferHookProgramId",
+	"confidentialTransferFeeConfig",
+	"metadataPointer",
+	"groupPointer",
+	"groupMemberPointer",
+}
+
+func (t AuthorityType) String() string {
+	if int(t) < len(authorityTypeNames) {
+		return authorityTypeNames[t]
+	}
+	return "unknown"
+}
@@ -0,0 +1,27 @@
+package models
+
+// ComputeBudget sets the ComputeBudget instructions prepended to a transaction
+type ComputeBudget struct {
+	// UnitLimit caps compute units for the transaction; zero keeps the cluster default
+	UnitLimit uint32
+	// UnitPrice is the priority fee in micro-lamports per compute unit
+	UnitPrice uint64
+	// AutoPrice derives UnitPrice from getRecentPrioritizationFees for the writable accounts
+	AutoPrice bool
+	// MaxUnitPrice caps the automatic price; zero means no cap
+	MaxUnitPrice uint64
+}
+
+// TransactionFee is the fee a landed transaction actually paid
+type TransactionFee struct {
+	Signature string
+	// Total is the fee in lamports charged to the fee payer
+	Total uint64
+	// Base is the signature fee part of Total
+	Base uint64
+	// Priority is the compute-budget part of Total
+	Priority             uint64
+	ComputeUnitLimit     uint32
+	ComputeUnitPrice     uint64
+	ComputeUnitsConsumed uint64
+}
@@ -0,0 +1,40 @@
+package models
+
+// Commitment is the cluster confirmation level of a transaction
+type Commitment string
+
+const (
+	CommitmentProcessed Commitment = "processed"
+	CommitmentConfirmed Commitment = "confirmed"
+	CommitmentFinalized Commitment = "finalized"
+)
+
+// SendOptions controls how a write request is submitted
+type SendOptions struct {
+	// Commitment makes the call wait until the transaction reaches it; empty returns right after sending
+	Commitment Commitment
+	// ComputeBudget overrides the client-wide compute budget for this transaction
+	ComputeBudget *ComputeBudget
+	// Nonce signs the transaction with a durable nonce instead of the latest blockhash
+	Nonce *DurableNonce
+	// IdempotencyKey names the request in the submission store; a retried call with the same
+	// key resumes the earlier submission instead of signing a new transaction
+	IdempotencyKey string
+	// LookupTables compiles the transaction as a v0 message that loads the accounts found in
+	// these address lookup tables instead of listing them
+	LookupTables []string
+	// Simulate dry-runs the transaction instead of sending it: nothing is signed or broadcast,
+	// the outcome is written to the pointed Simulation and the call returns no signature
+	Simulate *Simulation
+}
+
+// ConfirmationResult is the state of a transaction once it reached the requested commitment
+type ConfirmationResult struct {
+	Signature  string
+	Slot       uint64
+	Commitment Commitment
+	// Err is the on-chain transaction error as reported by the cluster, nil on success
+	Err any
+	// Fee is the fee in lamports; zero when the transaction is only processed
+	Fee uint64
+}
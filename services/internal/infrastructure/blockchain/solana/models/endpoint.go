@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// EndpointStats describes one RPC endpoint of the client's pool
+type EndpointStats struct {
+	URL     string
+	Healthy bool
+	// Slot is the slot reported by the last health check
+	Slot uint64
+	// SlotLag is how far Slot trails the most advanced endpoint
+	SlotLag  uint64
+	Requests uint64
+	Errors   uint64
+	// AvgLatency is the mean round trip of successful requests
+	AvgLatency  time.Duration
+	LastLatency time.Duration
+	LastError   string
+	LastChecked time.Time
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+// EventType names a decoded instruction
+type EventType string
+
+const (
+	EventSOLTransfer            EventType = "solTransfer"
+	EventCreateAccount          EventType = "createAccount"
+	EventTransfer               EventType = "transfer"
+	EventTransferChecked        EventType = "transferChecked"
+	EventTransferCheckedWithFee EventType = "transferCheckedWithFee"
+	EventMintTo                 EventType = "mintTo"
+	EventMintToChecked          EventType = "mintToChecked"
+	EventBurn                   EventType = "burn"
+	EventBurnChecked            EventType = "burnChecked"
+	EventApprove                EventType = "approve"
+	EventApproveChecked         EventType = "approveChecked"
+	EventRevoke                 EventType = "revoke"
+	EventCloseAccount           EventType = "closeAccount"
+	EventSetAuthority           EventType = "setAuthority"
+	EventInitializeAccount      EventType = "initializeAccount"
+	EventInitializeAccount2     EventType = "initializeAccount2"
+	EventInitializeAccount3     EventType = "initializeAccount3"
+	EventMemo                   EventType = "memo"
+)
+
+// Amount is a raw on-chain amount together with its decimal-normalized value
+type Amount struct {
+	Raw      uint64
+	Decimals uint8
+	Value    decimal.Decimal
+	// Unscaled is set when the decimals of the mint could not be read; only Raw is meaningful then
+	Unscaled bool
+}
+
+// UnscaledAmount is raw base units of a mint whose decimals are unknown
+func UnscaledAmount(raw uint64) Amount {
+	return Amount{Raw: raw, Unscaled: true}
+}
+
+// NewAmount scales raw by decimals
+func NewAmount(raw uint64, decimals uint8) Amount {
+	return Amount{
+		Raw:      raw,
+		Decimals: decimals,
+		Value:    decimal.NewFromBigInt(new(big.Int).SetUint64(raw), -int32(decimals)),
+	}
+}
+
+// InstructionRef locates a decoded instruction in its transaction
+type InstructionRef struct {
+	Type    EventType
+	Program string
+	// Index is the outer instruction; InnerIndex is the position among its inner
+	// instructions and is only meaningful when IsInner is set
+	Index      int
+	InnerIndex int
+	IsInner    bool
+}
+
+// Ref lets every event expose its location through the Event interface
+func (r InstructionRef) Ref() InstructionRef {
+	return r
+}
+
+// Event is a decoded System, token or memo instruction. The concrete types are the *Event
+// structs of this file.
+type Event interface {
+	Ref() InstructionRef
+}
+
+// SOLTransferEvent is a System Transfer
+type SOLTransferEvent struct {
+	InstructionRef
+	From   string
+	To     string
+	Amount Amount
+	// Memo is the text of the memo instruction sent with the transfer, see MemoEvent
+	Memo string
+	// References are the read-only accounts appended to the transfer
+	References []string
+}
+
+// CreateAccountEvent is a System CreateAccount
+type CreateAccountEvent struct {
+	InstructionRef
+	Funder  string
+	Account string
+	Owner   string
+	Amount  Amount
+	Space   uint64
+}
+
+// TokenTransferEvent is a Transfer, TransferChecked or TransferCheckedWithFee. Mint and decimals
+// of unchecked transfers come from the transaction's token balances.
+type TokenTransferEvent struct {
+	InstructionRef
+	Source      string
+	Destination string
+	Authority   string
+	Mint        string
+	Amount      Amount
+	// Fee is the Token-2022 transfer fee withheld from Amount
+	Fee       Amount
+	NetAmount Amount
+	// FeeUnresolved is set when the mint could not be read to work out the fee of a transfer
+	// that does not state it; Fee and NetAmount are then unset
+	FeeUnresolved bool
+	// Memo is the text of the memo instruction sent with the transfer, see MemoEvent
+	Memo string
+	// References are the read-only accounts appended to the transfer, after its authority
+	// and any multisig signers
+	References []string
+}
+
+// MintToEvent is a MintTo or MintToChecked
+type MintToEvent struct {
+	InstructionRef
+	Mint        string
+	Destination string
+	Authority   string
+	Amount      Amount
+}
+
+// BurnEvent is a Burn or BurnChecked
+type BurnEvent struct {
+	InstructionRef
+	Account   string
+	Mint      string
+	Authority string
+	Amount    Amount
+}
+
+// ApproveEvent is an Approve or ApproveChecked
+type ApproveEvent struct {
+	InstructionRef
+	Source   string
+	Mint     string
+	Delegate string
+	Owner    string
+	Amount   Amount
+}
+
+// RevokeEvent is a Revoke
+type RevokeEvent struct {
+	InstructionRef
+	Source string
+	Owner  string
+}
+
+// CloseAccountEvent is a CloseAccount; its rent goes to Destination
+type CloseAccountEvent struct {
+	InstructionRef
+	Account     string
+	Mint        string
+	Destination string
+	Owner       string
+}
+
+// SetAuthorityEvent is a SetAuthority on a mint or token account
+type SetAuthorityEvent struct {
+	InstructionRef
+	Account          string
+	CurrentAuthority string
+	AuthorityType    AuthorityType
+	// NewAuthority is empty when the authority is removed
+	NewAuthority string
+}
+
+// InitializeAccountEvent is an InitializeAccount, InitializeAccount2 or InitializeAccount3
+type InitializeAccountEvent struct {
+	InstructionRef
+	Account string
+	Mint    string
+	Owner   string
+}
+
+// MemoEvent is an SPL Memo instruction. Transfers take the text of the closest memo before
+// them that no earlier transfer took.
+type MemoEvent struct {
+	InstructionRef
+	Memo string
+	// Signers are the accounts that signed the memo
+	Signers []string
+}
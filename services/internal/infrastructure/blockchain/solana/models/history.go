@@ -0,0 +1,67 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// SignatureInfo is a transaction that involves an address, as listed by getSignaturesForAddress
+type SignatureInfo struct {
+	Signature string
+	Slot      uint64
+	// BlockTime is zero when the cluster does not know it
+	BlockTime time.Time
+	// Err is the on-chain error of a failed transaction, nil on success
+	Err  any
+	Memo string
+}
+
+// HistoryDirection tells how an entry moved funds relative to the address it belongs to
+type HistoryDirection string
+
+const (
+	HistoryIn  HistoryDirection = "in"
+	HistoryOut HistoryDirection = "out"
+	// HistorySelf moves funds between the address and itself, such as two token accounts of a wallet
+	HistorySelf HistoryDirection = "self"
+	// HistoryFee is a transaction the address paid for without sending or receiving funds
+	HistoryFee HistoryDirection = "fee"
+)
+
+// HistoryEntry is one SOL transfer or token transfer, mint or burn of an address, normalized
+// for a statement
+type HistoryEntry struct {
+	// Address is the wallet or token account the history belongs to
+	Address   string
+	Signature string
+	Slot      uint64
+	BlockTime time.Time
+	Direction HistoryDirection
+	// Counterparty is the other wallet, the other token account when its owner is unknown, or
+	// the mint of a mint or burn
+	Counterparty string
+	// Mint is empty for SOL
+	Mint   string
+	Amount Amount
+	// Fee is the fee in lamports when Address paid it. Only the first entry of a transaction
+	// carries it, so summing entries counts every fee once.
+	Fee uint64
+	// Err is the on-chain error of a failed transaction; failed transactions only record their fee
+	Err string
+	// Instruction locates the transfer; it is zero for fee entries
+	Instruction InstructionRef
+}
+
+// HistoryStore persists synced history and how far each followed account has been synced
+type HistoryStore interface {
+	// LoadHistoryCursors returns the newest synced signature of every account followed for
+	// address: the address itself and, for a wallet, its token accounts. It is empty before
+	// the first sync.
+	LoadHistoryCursors(ctx context.Context, address string) (map[string]string, error)
+	// SaveHistory adds entries, newest first, and replaces the cursors of address. Entries of
+	// signatures that are already stored are ignored.
+	SaveHistory(ctx context.Context, address string, entries []HistoryEntry, cursors map[string]string) error
+	// ListHistory returns up to limit entries newest first, starting after the entries of
+	// signature before when it is set; a zero limit returns them all
+	ListHistory(ctx context.Context, address, before string, limit int) ([]HistoryEntry, error)
+}
@@ -0,0 +1,12 @@
+package models
+
+// LookupTable is a parsed address lookup table account
+type LookupTable struct {
+	Address string
+	// Authority may extend, freeze and close the table; empty once the table is frozen
+	Authority string
+	// DeactivationSlot is the slot the table was deactivated in; zero while it is active
+	DeactivationSlot uint64
+	LastExtendedSlot uint64
+	Addresses        []string
+}
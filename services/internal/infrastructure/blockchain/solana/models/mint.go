@@ -0,0 +1,72 @@
+package models
+
+import "math/bits"
+
+const maxFeeBasisPoints = 10_000
+
+// MintInfo is a parsed mint account of the Token or Token-2022 program
+type MintInfo struct {
+	Address      string
+	TokenProgram string
+	Supply       uint64
+	Decimals     uint8
+	// MintAuthority is empty when the supply is fixed
+	MintAuthority   string
+	FreezeAuthority string
+	// TransferFee is the Token-2022 TransferFeeConfig extension, nil when absent
+	TransferFee *TransferFeeConfig
+}
+
+// TransferFeeConfig is the Token-2022 TransferFeeConfig mint extension
+type TransferFeeConfig struct {
+	ConfigAuthority           string
+	WithdrawWithheldAuthority string
+	WithheldAmount            uint64
+	Older                     TransferFee
+	Newer                     TransferFee
+}
+
+// TransferFee is a fee schedule that takes effect at Epoch
+type TransferFee struct {
+	Epoch       uint64
+	MaximumFee  uint64
+	BasisPoints uint16
+}
+
+// Fee returns the amount withheld from a transfer of amount made in epoch
+func (c *TransferFeeConfig) Fee(epoch, amount uint64) uint64 {
+	schedule := c.Older
+	if epoch >= c.Newer.Epoch {
+		schedule = c.Newer
+	}
+	if schedule.BasisPoints == 0 || amount == 0 {
+		return 0
+	}
+	// ceil(amount * bps / 10_000) without overflowing u64
+	hi, lo := bits.Mul64(amount, uint64(schedule.BasisPoints))
+	lo, carry := bits.Add64(lo, maxFeeBasisPoints-1, 0)
+	hi += carry
+	if hi >= maxFeeBasisPoints {
+		return schedule.MaximumFee
+	}
+	q, _ := bits.Div64(hi, lo, maxFeeBasisPoints)
+	return min(q, schedule.MaximumFee)
+}
+
+// TransferFeeParams initializes the TransferFeeConfig extension of a new Token-2022 mint
+type TransferFeeParams struct {
+	BasisPoints uint16
+	MaximumFee  uint64
+	// ConfigAuthority may change the fee later; empty leaves it fixed
+	ConfigAuthority string
+	// WithdrawWithheldAuthority may collect withheld fees; empty disables withdrawal
+	WithdrawWithheldAuthority string
+}
+
+// TransferFeeQuote is the fee a transfer of Amount would pay in the current epoch
+type TransferFeeQuote struct {
+	Amount    uint64
+	Fee       uint64
+	NetAmount uint64
+	Epoch     uint64
+}
@@ -0,0 +1,19 @@
+package models
+
+// Multisig is an SPL Token multisig account: an authority that acts once M of its N
+// signers approve
+type Multisig struct {
+	Address      string
+	TokenProgram string
+	// Threshold is the number of signers required, M
+	Threshold uint8
+	Signers   []string
+}
+
+// MultisigAuthority stands in for a single authority signer when a multisig holds the
+// authority. Signers are the members approving the instruction; at least the threshold of
+// them must sign, and signer.WatchOnly members can sign later through PartialSign or AddSignature.
+type MultisigAuthority struct {
+	Address string
+	Signers []Signer
+}
@@ -0,0 +1,43 @@
+package models
+
+// MasterEdition is the Metaplex master edition of an NFT. It holds the mint authority, so no
+// further token of the mint can be minted.
+type MasterEdition struct {
+	Address string
+	Mint    string
+	// Supply is the number of prints made from the master edition
+	Supply uint64
+	// MaxSupply is the number of prints allowed; nil allows any number
+	MaxSupply *uint64
+}
+
+// OffChainMetadata is the JSON document a metadata URI points at, in the Metaplex token
+// metadata standard
+type OffChainMetadata struct {
+	Name         string              `json:"name"`
+	Symbol       string              `json:"symbol,omitempty"`
+	Description  string              `json:"description,omitempty"`
+	Image        string              `json:"image,omitempty"`
+	AnimationURL string              `json:"animation_url,omitempty"`
+	ExternalURL  string              `json:"external_url,omitempty"`
+	Attributes   []MetadataAttribute `json:"attributes,omitempty"`
+	Properties   *MetadataProperties `json:"properties,omitempty"`
+}
+
+// MetadataAttribute is a trait of a token; Value is a string or a number
+type MetadataAttribute struct {
+	TraitType string `json:"trait_type"`
+	Value     any    `json:"value"`
+}
+
+type MetadataProperties struct {
+	Files    []MetadataFile `json:"files,omitempty"`
+	Category string         `json:"category,omitempty"`
+}
+
+// MetadataFile is a file of a token; Type is its MIME type
+type MetadataFile struct {
+	URI  string `json:"uri"`
+	Type string `json:"type,omitempty"`
+	CDN  bool   `json:"cdn,omitempty"`
+}
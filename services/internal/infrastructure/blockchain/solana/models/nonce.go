@@ -0,0 +1,20 @@
+package models
+
+// DurableNonce signs a transaction against a nonce account instead of a recent blockhash,
+// so it stays valid until the nonce is advanced
+type DurableNonce struct {
+	// Account is the nonce account address
+	Account string
+	// Authority signs the AdvanceNonceAccount instruction prepended to the transaction
+	Authority Signer
+}
+
+// NonceAccount is a parsed System program nonce account
+type NonceAccount struct {
+	Address   string
+	Authority string
+	// Nonce is the stored value used in place of a recent blockhash
+	Nonce                string
+	LamportsPerSignature uint64
+	Lamports             uint64
+}
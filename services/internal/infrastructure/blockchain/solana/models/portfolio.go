@@ -0,0 +1,27 @@
+package models
+
+// Portfolio is what a wallet holds: its native SOL and its Token and Token-2022 accounts
+type Portfolio struct {
+	Owner string
+	// SOL is the native balance of the owner, in lamports with 9 decimals
+	SOL    Amount
+	Tokens []PortfolioToken
+}
+
+// PortfolioToken is a token account of a portfolio
+type PortfolioToken struct {
+	Account      string
+	Mint         string
+	TokenProgram string
+	// Amount is scaled by the mint decimals; it is unscaled when the mint is unknown
+	Amount Amount
+	// Metadata is nil when the mint has no Metaplex metadata, or either does not decode
+	Metadata *TokenMetadata
+	// UnknownMint is set when the mint is closed or does not decode, so its decimals are unknown
+	UnknownMint bool
+	// NonATA accounts are not the owner's associated token account of the mint
+	NonATA bool
+	// ZeroBalance accounts hold no tokens and can be closed to reclaim their rent
+	ZeroBalance bool
+	Frozen      bool
+}
@@ -0,0 +1,16 @@
+package models
+
+// PreparedTransaction is a compiled transaction that may still be missing signatures
+type PreparedTransaction struct {
+	// Transaction is the base64 wire transaction; missing signatures are zero-filled
+	Transaction string
+	// Message is the base64 serialized message each signer signs
+	Message string
+	// Signers lists every required signer, fee payer first
+	Signers []string
+	// Missing lists the required signers that have not signed yet
+	Missing   []string
+	Blockhash string
+	// LastValidBlockHeight of Blockhash; zero for durable-nonce transactions or when unknown
+	LastValidBlockHeight uint64
+}
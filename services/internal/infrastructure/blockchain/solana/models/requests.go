@@ -5,14 +5,22 @@ type BalanceRequest struct {
 }
 
 type AirdropRequest struct {
+	SendOptions
 	PublicKey string
 	Lamports  uint64
 }
 
 type TransferSOLRequest struct {
-	FromPrivateKey string
-	ToPublicKey    string
-	Lamports       uint64
+	SendOptions
+	From        Signer
+	ToPublicKey string
+	Lamports    uint64
+	// Memo is recorded with the transfer through the SPL Memo program, e.g. the ID of the
+	// transfer entity it settles
+	Memo string
+	// References are read-only keys added to the transfer; the transfer can then be looked
+	// up by any of them
+	References []string
 }
 
 type RentRequest struct {
@@ -22,56 +30,288 @@ type RentRequest struct {
 type DeriveATARequest struct {
 	Owner string
 	Mint  string
+	// TokenProgram owning the mint; empty derives for the original Token program
+	TokenProgram string
 }
 
 type CreateATARequest struct {
-	PayerPrivateKey string
-	Owner           string
-	Mint            string
+	SendOptions
+	Payer Signer
+	Owner string
+	Mint  string
 }
 
 type GetMintDecimalsRequest struct {
 	Mint string
 }
 
+type GetMintInfoRequest struct {
+	Mint string
+}
+
+type GetTransferFeeRequest struct {
+	Mint   string
+	Amount uint64
+}
+
 type TransferTokenCheckedRequest struct {
-	AuthorityPrivateKey string
-	SourceATA           string
-	DestinationATA      string
-	Mint                string
-	Amount              uint64
-	Decimals            uint8
+	SendOptions
+	// Authority is the owner of SourceATA, or a delegate spending its allowance
+	Authority      Signer
+	SourceATA      string
+	DestinationATA string
+	Mint           string
+	Amount         uint64
+	Decimals       uint8
+	// Multisig, when set, holds the authority in place of Authority, which then only pays the fee
+	Multisig *MultisigAuthority
+	// Memo is recorded with the transfer through the SPL Memo program, e.g. the ID of the
+	// transfer entity it settles
+	Memo string
+	// References are read-only keys added to the transfer; the transfer can then be looked
+	// up by any of them
+	References []string
 }
 
 type CreateMintRequest struct {
-	PayerPrivateKey string
-	MintAuthority   string
+	SendOptions
+	Payer         Signer
+	MintAuthority string
+	// FreezeAuthority can freeze and thaw the accounts of the mint; empty creates a mint
+	// whose accounts can never be frozen
+	FreezeAuthority string
 	Decimals        uint8
+	// TokenProgram owning the new mint; empty selects the original Token program
+	TokenProgram string
+	// TransferFee adds the TransferFeeConfig extension; Token-2022 only
+	TransferFee *TransferFeeParams
 }
 
 type MintToRequest struct {
-	MintAuthorityPrivateKey string
-	Mint                    string
-	DestinationATA          string
-	Amount                  uint64
+	SendOptions
+	MintAuthority  Signer
+	Mint           string
+	DestinationATA string
+	Amount         uint64
+	// Multisig, when set, holds the authority in place of MintAuthority, which then only pays the fee
+	Multisig *MultisigAuthority
+}
+
+// BurnRequest destroys tokens held in Account, lowering the supply of Mint
+type BurnRequest struct {
+	SendOptions
+	// Authority is the owner of Account, or a delegate spending its allowance
+	Authority Signer
+	Account   string
+	Mint      string
+	Amount    uint64
+	// Decimals, when set, sends BurnChecked so a mismatch with the mint fails the transaction
+	Decimals *uint8
+	// Multisig, when set, holds the authority in place of Authority, which then only pays the fee
+	Multisig *MultisigAuthority
+}
+
+type FreezeAccountRequest struct {
+	SendOptions
+	// FreezeAuthority is the freeze authority of Mint
+	FreezeAuthority Signer
+	Account         string
+	Mint            string
+	// Multisig, when set, holds the authority in place of FreezeAuthority, which then only pays the fee
+	Multisig *MultisigAuthority
+}
+
+type ThawAccountRequest struct {
+	SendOptions
+	// FreezeAuthority is the freeze authority of Mint
+	FreezeAuthority Signer
+	Account         string
+	Mint            string
+	// Multisig, when set, holds the authority in place of FreezeAuthority, which then only pays the fee
+	Multisig *MultisigAuthority
+}
+
+// ApproveRequest lets Delegate transfer or burn up to Amount from Account, replacing any
+// earlier allowance
+type ApproveRequest struct {
+	SendOptions
+	// Owner is the owner of Account
+	Owner    Signer
+	Account  string
+	Mint     string
+	Delegate string
+	Amount   uint64
+	Decimals uint8
+	// Multisig, when set, holds the authority in place of Owner, which then only pays the fee
+	Multisig *MultisigAuthority
+}
+
+// RevokeRequest withdraws the allowance of the delegate of Account
+type RevokeRequest struct {
+	SendOptions
+	// Owner is the owner of Account
+	Owner   Signer
+	Account string
+	// Multisig, when set, holds the authority in place of Owner, which then only pays the fee
+	Multisig *MultisigAuthority
+}
+
+// CloseAccountRequest closes an empty token account and reclaims its rent
+type CloseAccountRequest struct {
+	SendOptions
+	// Authority is the close authority of Account, or its owner when none is set
+	Authority Signer
+	Account   string
+	// Destination receives the rent; empty selects the authority
+	Destination string
+	// Multisig, when set, holds the authority in place of Authority, which then only pays the fee
+	Multisig *MultisigAuthority
+}
+
+// SetAuthorityRequest changes an authority of a mint or token account. Mints hold the
+// mint tokens and freeze account authorities; token accounts the account owner and close account ones.
+type SetAuthorityRequest struct {
+	SendOptions
+	CurrentAuthority Signer
+	// Account is the mint or token account whose authority changes
+	Account       string
+	AuthorityType AuthorityType
+	// NewAuthority is the new holder; empty removes the authority for good. A token account
+	// always needs an owner.
+	NewAuthority string
+	// Multisig, when set, holds the authority in place of CurrentAuthority, which then only pays the fee
+	Multisig *MultisigAuthority
+}
+
+// CreateMultisigRequest creates an M-of-N SPL Token multisig that can hold mint, freeze and
+// owner authorities of the same token program
+type CreateMultisigRequest struct {
+	SendOptions
+	Payer Signer
+	// Signers are the N members; the token programs accept up to 11
+	Signers []string
+	// Threshold is the number of members that must approve, M
+	Threshold uint8
+	// TokenProgram owning the multisig; empty selects the original Token program
+	TokenProgram string
+}
+
+type GetMultisigRequest struct {
+	Address string
 }
 
 type GetTokenMetadataRequest struct {
 	Mint string
 }
 
+type CreateTokenMetadataRequest struct {
+	SendOptions
+	Payer Signer
+	// MintAuthority is the current authority of the mint; nil selects the payer
+	MintAuthority Signer
+	Mint          string
+	// UpdateAuthority may change the metadata later; empty selects the mint authority
+	UpdateAuthority      string
+	Name                 string
+	Symbol               string
+	URI                  string
+	SellerFeeBasisPoints uint16
+	// Creators may only be verified when they are the update authority, which then signs
+	Creators   []Creator
+	Collection *Collection
+	Uses       *Uses
+	// Immutable freezes the metadata for good; by default the update authority can change it
+	Immutable bool
+}
+
+// SetTokenMetadataRequest changes the fields that are set; nil fields keep their current value
 type SetTokenMetadataRequest struct {
-	UpdateAuthorityPrivateKey string
-	Mint                      string
-	Name                      string
-	Symbol                    string
-	URI                       string
+	SendOptions
+	UpdateAuthority      Signer
+	Mint                 string
+	Name                 *string
+	Symbol               *string
+	URI                  *string
+	SellerFeeBasisPoints *uint16
+	// Creators replaces the creator list; an empty slice removes every creator
+	Creators   *[]Creator
+	Collection *Collection
+	Uses       *Uses
+	// NewUpdateAuthority hands the metadata to another authority
+	NewUpdateAuthority string
+	// PrimarySaleHappened marks the primary sale; it cannot be unset
+	PrimarySaleHappened bool
+	// Immutable freezes the metadata for good
+	Immutable bool
+}
+
+// MintNFTRequest mints a single token with metadata and a master edition. The payer creates
+// the mint and stays its update authority; the token goes to Owner.
+type MintNFTRequest struct {
+	SendOptions
+	Payer Signer
+	// Owner receives the token; empty selects the payer
+	Owner                string
+	Name                 string
+	Symbol               string
+	URI                  string
+	SellerFeeBasisPoints uint16
+	Creators             []Creator
+	Collection           *Collection
+	// Immutable freezes the metadata for good; by default the payer can still update it
+	Immutable bool
+	// MaxSupply is the number of prints the master edition allows; zero makes the token unique
+	MaxSupply uint64
+}
+
+// TransferNFTRequest moves an NFT from the associated account of its owner to the one of To,
+// creating it when missing
+type TransferNFTRequest struct {
+	SendOptions
+	Owner Signer
+	// Payer pays for the recipient's token account; nil selects the owner
+	Payer Signer
+	Mint  string
+	To    string
+	// Memo is recorded with the transfer through the SPL Memo program, e.g. the ID of the
+	// transfer entity it settles
+	Memo string
+	// References are read-only keys added to the transfer; the transfer can then be looked
+	// up by any of them
+	References []string
+}
+
+type GetMasterEditionRequest struct {
+	Mint string
+}
+
+type VerifyNFTOwnerRequest struct {
+	Mint  string
+	Owner string
+}
+
+type FetchOffChainMetadataRequest struct {
+	// URI is an http or https URL of a Metaplex JSON document
+	URI string
+}
+
+type ResolveTokenMetadataRequest struct {
+	Mint string
 }
 
 type GetTransactionTransfersRequest struct {
 	Signature string
 }
 
+type ParseSolanaPayURLRequest struct {
+	URL string
+}
+
+type VerifySolanaPayTransferRequest struct {
+	// Transfer is the request the payment answers; it needs an amount and at least one reference
+	Transfer SolanaPayTransfer
+}
+
 type GetTokenAccountRequest struct {
 	ATA string
 }
@@ -79,3 +319,219 @@ type GetTokenAccountRequest struct {
 type GetTokenMintFromATARequest struct {
 	ATA string
 }
+
+type WaitForConfirmationRequest struct {
+	Signature  string
+	Commitment Commitment
+	// LastValidBlockHeight of the blockhash the transaction was signed with; zero disables expiry detection
+	LastValidBlockHeight uint64
+}
+
+type GetTransactionFeeRequest struct {
+	Signature string
+}
+
+type CreateNonceAccountRequest struct {
+	SendOptions
+	Payer Signer
+	// Authority may advance and withdraw the nonce; empty selects the payer
+	Authority string
+}
+
+type GetNonceAccountRequest struct {
+	Address string
+}
+
+type AdvanceNonceAccountRequest struct {
+	SendOptions
+	Authority Signer
+	Address   string
+}
+
+type WithdrawNonceAccountRequest struct {
+	SendOptions
+	Authority   Signer
+	Address     string
+	ToPublicKey string
+	Lamports    uint64
+}
+
+type PartialSignRequest struct {
+	Transaction PreparedTransaction
+	Signers     []Signer
+}
+
+type AddSignatureRequest struct {
+	Transaction PreparedTransaction
+	PublicKey   string
+	// Signature is the base58 ed25519 signature of the message
+	Signature string
+}
+
+type SubmitRequest struct {
+	SendOptions
+	Transaction PreparedTransaction
+}
+
+type GetSubmissionRequest struct {
+	// Key is the request's IdempotencyKey, or the first signature of a keyless submission
+	Key string
+}
+
+type DeriveAccountRequest struct {
+	Mnemonic   string
+	Passphrase string
+	// Index is the account n of the Solana path m/44'/501'/n'/0'
+	Index uint32
+}
+
+type RestoreAccountsRequest struct {
+	Mnemonic   string
+	Passphrase string
+	// Count restores accounts 0 to Count-1; zero scans the chain instead and stops after
+	// GapLimit unused accounts in a row
+	Count    uint32
+	GapLimit uint32
+}
+
+type ImportKeypairFileRequest struct {
+	Path string
+}
+
+type ExportKeypairFileRequest struct {
+	// Keypair is the account to export, e.g. a *signer.Keypair, which keeps its secret out of logs
+	Keypair ExportableSigner
+	Path    string
+}
+
+type GetTransactionEventsRequest struct {
+	Signature string
+}
+
+type CreateLookupTableRequest struct {
+	SendOptions
+	Payer Signer
+	// Authority may extend and close the table; nil selects the payer
+	Authority Signer
+	// Addresses are added to the table in the same transaction, at most 20
+	Addresses []string
+}
+
+type ExtendLookupTableRequest struct {
+	SendOptions
+	Payer     Signer
+	Authority Signer
+	Table     string
+	// Addresses are appended to the table, at most 20 per request
+	Addresses []string
+}
+
+type GetLookupTableRequest struct {
+	Address string
+}
+
+type GetSignaturesForAddressRequest struct {
+	Address string
+	// Before starts the listing after this signature; empty starts from the newest
+	Before string
+	// Until stops the listing at this signature, excluding it
+	Until string
+	// Limit is between 1 and 1000; zero selects 1000
+	Limit int
+}
+
+type SyncHistoryRequest struct {
+	// Address is a wallet, whose token accounts are followed through their owner, or a token account
+	Address string
+	// PageSize is how many signatures are fetched per call; zero selects 1000
+	PageSize int
+}
+
+type GetHistoryRequest struct {
+	Address string
+	// Before continues a previous page from its last signature
+	Before string
+	Limit  int
+}
+
+type SimulateRequest struct {
+	Transaction PreparedTransaction
+}
+
+type CreateStakeAccountRequest struct {
+	SendOptions
+	// Payer funds the stake and the rent-exempt reserve on top of it
+	Payer Signer
+	// Seed derives the address from the payer, so no new keypair has to be kept; empty
+	// generates a keypair for the account
+	Seed string
+	// Lamports is the amount to stake, excluding the rent-exempt reserve
+	Lamports uint64
+	// Staker and Withdrawer default to the payer
+	Staker     string
+	Withdrawer string
+	Lockup     *StakeLockup
+}
+
+type DelegateStakeRequest struct {
+	SendOptions
+	Staker       Signer
+	StakeAccount string
+	VoteAccount  string
+}
+
+type DeactivateStakeRequest struct {
+	SendOptions
+	Staker       Signer
+	StakeAccount string
+}
+
+type WithdrawStakeRequest struct {
+	SendOptions
+	Withdrawer   Signer
+	StakeAccount string
+	ToPublicKey  string
+	// Lamports may include active stake only once it has cooled down; withdrawing the whole
+	// balance closes the account
+	Lamports uint64
+	// Custodian signs to withdraw while the lockup is in force
+	Custodian Signer
+}
+
+type SplitStakeRequest struct {
+	SendOptions
+	// Staker also pays the rent-exempt reserve of the new account
+	Staker       Signer
+	StakeAccount string
+	// Lamports of stake moved into the new account, less than the delegated stake
+	Lamports uint64
+	// Seed derives the new address from the staker; empty generates a keypair for it
+	Seed string
+}
+
+type MergeStakeRequest struct {
+	SendOptions
+	Staker Signer
+	// Source is drained into Destination and closed; both need the same authorities, lockup
+	// and vote account, and neither may be warming up or cooling down
+	Destination string
+	Source      string
+}
+
+type GetStakeAccountRequest struct {
+	Address string
+}
+
+type GetStakeActivationRequest struct {
+	Address string
+}
+
+type GetStakeRewardsRequest struct {
+	StakeAccounts []string
+	// Epoch selects the epoch the rewards were earned in; nil selects the last completed epoch
+	Epoch *uint64
+}
+
+type GetPortfolioRequest struct {
+	Owner string
+}
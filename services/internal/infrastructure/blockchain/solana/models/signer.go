@@ -0,0 +1,26 @@
+package models
+
+import "context"
+
+// Signer signs transaction messages on behalf of a Solana account
+type Signer interface {
+	// PublicKey returns the base58 public key of the account
+	PublicKey() string
+	// Sign returns the ed25519 signature of a serialized transaction message
+	Sign(message []byte) ([]byte, error)
+}
+
+// ContextSigner is a signer whose signing can be cancelled, such as *signer.Remote.
+// The client calls SignContext with the operation's context instead of Sign.
+type ContextSigner interface {
+	Signer
+	// SignContext is Sign stopping once ctx is done
+	SignContext(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// ExportableSigner is a signer that holds its secret key in memory, such as *signer.Keypair
+type ExportableSigner interface {
+	Signer
+	// SecretKey returns a copy of the 64-byte secret key (seed followed by public key)
+	SecretKey() []byte
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Simulation is the outcome of a dry run of a transaction
+type Simulation struct {
+	// Err is the transaction error as reported by the cluster, nil on success
+	Err any
+	// Error decodes Err; nil on success
+	Error         *TransactionError
+	Logs          []string
+	UnitsConsumed uint64
+	// Fee is the fee in lamports the fee payer would pay
+	Fee uint64
+	// BalanceChanges lists the accounts whose lamports would change. Balance changes are
+	// only known when the simulation succeeds.
+	BalanceChanges      []BalanceChange
+	TokenBalanceChanges []TokenBalanceChange
+}
+
+// TransactionError is a decoded transaction or instruction error
+type TransactionError struct {
+	// Instruction is the index of the failing instruction, -1 when the transaction as a whole failed
+	Instruction int
+	// Program raised the error: the program of the failing instruction, or the program it
+	// invoked when the logs show the error came from there
+	Program string
+	// Account is the account the error is most likely about: the fee payer for transaction
+	// errors, otherwise the first account of the failing instruction
+	Account string
+	// Name is the error name, such as InsufficientFundsForFee or a program error like InsufficientFunds
+	Name string
+	// Code is the custom program error code, nil for builtin errors
+	Code    *uint32
+	Message string
+}
+
+func (e *TransactionError) Error() string {
+	if e.Instruction < 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("instruction %d: %s", e.Instruction, e.Message)
+}
+
+// BalanceChange is the lamport balance of an account before and after a transaction
+type BalanceChange struct {
+	Account string
+	Pre     uint64
+	Post    uint64
+	// Change is the difference in SOL; negative when lamports leave the account
+	Change decimal.Decimal
+}
+
+// TokenBalanceChange is the token balance of a token account before and after a transaction
+type TokenBalanceChange struct {
+	Account  string
+	Owner    string
+	Mint     string
+	Decimals uint8
+	Pre      uint64
+	Post     uint64
+	// Change is the difference in tokens; negative when tokens leave the account
+	Change decimal.Decimal
+}
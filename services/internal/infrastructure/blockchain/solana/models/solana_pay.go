@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SolanaPayTransfer is a Solana Pay transfer request, the content of a solana: URL
+type SolanaPayTransfer struct {
+	// Recipient is the wallet paid; token payments go to its associated token account
+	Recipient string
+	// Amount is in SOL, or in tokens of SPLToken; nil lets the payer enter it
+	Amount *decimal.Decimal
+	// SPLToken is the mint paid in; empty pays SOL
+	SPLToken string
+	// References are keys the payment must carry as read-only accounts, so it can be found by them
+	References []string
+	// Label names the merchant and Message describes the purchase to the payer
+	Label   string
+	Message string
+	// Memo is recorded with the payment through the SPL Memo program
+	Memo string
+}
+
+// SolanaPayment is a confirmed transaction that settles a SolanaPayTransfer
+type SolanaPayment struct {
+	Signature string
+	Slot      uint64
+	// BlockTime is zero when the cluster does not know it
+	BlockTime time.Time
+	// Payer is the wallet, or token account authority, the amount came from
+	Payer  string
+	Amount Amount
+}
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// StakeState is the state of a stake account as the Stake program stores it
+type StakeState string
+
+const (
+	StakeStateUninitialized StakeState = "uninitialized"
+	// StakeStateInitialized accounts have authorities but no delegation
+	StakeStateInitialized StakeState = "initialized"
+	// StakeStateDelegated accounts carry a delegation, which may be deactivated already
+	StakeStateDelegated StakeState = "delegated"
+)
+
+// StakeLockup keeps the withdrawer from withdrawing before Until and Epoch unless the
+// custodian also signs
+type StakeLockup struct {
+	// Until is zero when the lockup has no time limit
+	Until     time.Time
+	Epoch     uint64
+	Custodian string
+}
+
+// StakeAccount is a parsed Stake program account
+type StakeAccount struct {
+	Address  string
+	Lamports uint64
+	State    StakeState
+	// RentExemptReserve is the part of Lamports that can never be staked
+	RentExemptReserve uint64
+	// Staker delegates, deactivates, splits and merges; Withdrawer moves lamports out
+	Staker     string
+	Withdrawer string
+	Lockup     StakeLockup
+	// Delegation is nil unless State is StakeStateDelegated
+	Delegation *StakeDelegation
+}
+
+// StakeDelegation is the stake delegated to a validator's vote account
+type StakeDelegation struct {
+	VoteAccount     string
+	Stake           uint64
+	ActivationEpoch uint64
+	// DeactivationEpoch is nil while the stake is not being deactivated
+	DeactivationEpoch *uint64
+}
+
+// StakeActivationState tells how far a delegation has warmed up or cooled down
+type StakeActivationState string
+
+const (
+	StakeInactive     StakeActivationState = "inactive"
+	StakeActivating   StakeActivationState = "activating"
+	StakeActive       StakeActivationState = "active"
+	StakeDeactivating StakeActivationState = "deactivating"
+)
+
+// StakeActivation is the share of a stake account that earns rewards in an epoch. Stake
+// warms up and cools down over epoch boundaries, limited by how much the whole cluster's
+// stake may change per epoch.
+type StakeActivation struct {
+	Address string
+	Epoch   uint64
+	State   StakeActivationState
+	// Active earns rewards; Inactive is the rest of the balance above the rent-exempt reserve
+	Active   uint64
+	Inactive uint64
+	// Activating and Deactivating are the parts of the delegation still changing
+	Activating   uint64
+	Deactivating uint64
+}
+
+// StakeReward is the inflation reward a stake account received for an epoch
+type StakeReward struct {
+	Address string
+	Epoch   uint64
+	// EffectiveSlot is the slot the reward was credited in
+	EffectiveSlot uint64
+	Lamports      uint64
+	// PostBalance is the account balance once the reward was credited
+	PostBalance uint64
+	// Commission is the validator's cut, in percent, taken before the reward
+	Commission *uint8
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// SubmissionStatus is the state of a write request tracked by the submission engine
+type SubmissionStatus string
+
+const (
+	// SubmissionPending is sent (or about to be) and not yet known to have landed
+	SubmissionPending SubmissionStatus = "pending"
+	// SubmissionLanded reached the requested commitment; Err holds any on-chain error
+	SubmissionLanded SubmissionStatus = "landed"
+	// SubmissionFailed was proven not to have landed and the engine gave up
+	SubmissionFailed SubmissionStatus = "failed"
+)
+
+// SubmissionAttempt is one signed version of a submission. It is rebroadcast until it lands
+// or its blockhash expires.
+type SubmissionAttempt struct {
+	Signature string
+	// Transaction is the signed wire transaction, base64
+	Transaction          string
+	Blockhash            string
+	LastValidBlockHeight uint64
+	Broadcasts           int
+	FirstSentAt          time.Time
+	LastSentAt           time.Time
+	// Expired is set once the chain passed LastValidBlockHeight without the signature
+	Expired bool
+	// Rejected is the reason the cluster refused the transaction before it could land
+	Rejected string
+}
+
+// Submission records every attempt made for one write request
+type Submission struct {
+	// Key is SendOptions.IdempotencyKey, or the first attempt's signature when none was given
+	Key    string
+	Status SubmissionStatus
+	// Signature is the attempt that landed
+	Signature string
+	// Err is the on-chain error of the landed transaction, or why the submission failed
+	Err string
+	// Error decodes Err when the transaction failed on chain or in preflight
+	Error    *TransactionError
+	Attempts []SubmissionAttempt
+}
+
+// SubmissionStore keeps submissions so a retried request resumes instead of executing twice
+type SubmissionStore interface {
+	// LoadSubmission returns nil without error when the key is unknown
+	LoadSubmission(ctx context.Context, key string) (*Submission, error)
+	SaveSubmission(ctx context.Context, sub Submission) error
+}
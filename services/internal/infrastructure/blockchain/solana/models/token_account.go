@@ -4,4 +4,15 @@ type TokenAccount struct {
 	Mint   string
 	Owner  string
 	Amount uint64
+	// Delegate may transfer or burn up to DelegatedAmount on behalf of the owner; empty when none
+	Delegate        string
+	DelegatedAmount uint64
+	// Frozen accounts can neither send nor receive until the freeze authority thaws them
+	Frozen bool
+	// CloseAuthority may close the account in place of the owner; empty when none
+	CloseAuthority string
+	// TokenProgram owning the account
+	TokenProgram string
+	// WithheldAmount is the Token-2022 transfer fee withheld in the account
+	WithheldAmount uint64
 }
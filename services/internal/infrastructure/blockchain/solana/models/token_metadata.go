@@ -1,8 +1,98 @@
 package models
 
+// TokenMetadata is a decoded Metaplex Metadata account and the decimals of its mint
 type TokenMetadata struct {
-	Name     string
-	Symbol   string
-	URI      string
-	Decimals uint8
+	// Address is the metadata account, derived from the mint
+	Address         string
+	Mint            string
+	UpdateAuthority string
+	Name            string
+	Symbol          string
+	URI             string
+	Decimals        uint8
+	// SellerFeeBasisPoints is the royalty secondary sales pay to the creators
+	SellerFeeBasisPoints uint16
+	Creators             []Creator
+	PrimarySaleHappened  bool
+	IsMutable            bool
+	// EditionNonce is the bump of the edition account; nil on old accounts
+	EditionNonce  *uint8
+	TokenStandard *TokenStandard
+	Collection    *Collection
+	Uses          *Uses
+}
+
+// Creator shares the royalties of a token. Verified means the creator signed to confirm it.
+type Creator struct {
+	Address  string
+	Verified bool
+	// Share is a percentage; the shares of all creators add up to 100
+	Share uint8
+}
+
+// Collection is the collection mint a token belongs to; only its update authority can verify it
+type Collection struct {
+	Verified bool
+	Key      string
+}
+
+// UseMethod says what consuming a use does to the token
+type UseMethod uint8
+
+const (
+	UseMethodBurn UseMethod = iota
+	UseMethodMultiple
+	UseMethodSingle
+)
+
+var useMethodNames = [...]string{"burn", "multiple", "single"}
+
+func (m UseMethod) String() string {
+	if int(m) < len(useMethodNames) {
+		return useMethodNames[m]
+	}
+	return "unknown"
+}
+
+// Uses limits how many times a token can be used
+type Uses struct {
+	UseMethod UseMethod
+	Remaining uint64
+	Total     uint64
+}
+
+// TokenStandard is the kind of token a metadata account describes
+type TokenStandard uint8
+
+const (
+	TokenStandardNonFungible TokenStandard = iota
+	TokenStandardFungibleAsset
+	TokenStandardFungible
+	TokenStandardNonFungibleEdition
+	TokenStandardProgrammableNonFungible
+	TokenStandardProgrammableNonFungibleEdition
+)
+
+var tokenStandardNames = [...]string{
+	"nonFungible",
+	"fungibleAsset",
+	"fungible",
+	"nonFungibleEdition",
+	"programmableNonFungible",
+	"programmableNonFungibleEdition",
+}
+
+func (s TokenStandard) String() string {
+	if int(s) < len(tokenStandardNames) {
+		return tokenStandardNames[s]
+	}
+	return "unknown"
+}
+
+// ResolvedTokenMetadata is on-chain metadata together with the document its URI points at
+type ResolvedTokenMetadata struct {
+	TokenMetadata
+	OffChain *OffChainMetadata
+	// Mismatches describes where the document disagrees with the chain; empty when consistent
+	Mismatches []string
 }
@@ -0,0 +1,155 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
+)
+
+// An SPL multisig account is m u8, n u8, is_initialized u8 and room for 11 signer keys
+const (
+	multisigSize       = 355
+	multisigMaxSigners = 11
+
+	tokenInstructionInitializeMultisig2 = 19
+)
+
+// CreateMultisig creates and initializes an SPL Token multisig; returns its address and the signature
+func (c *Client) CreateMultisig(ctx context.Context, req models.CreateMultisigRequest) (string, string, error) {
+	op, address, err := c.createMultisig(ctx, req)
+	if err != nil {
+		return "", "", err
+	}
+	sig, err := c.run(ctx, op)
+	if err != nil {
+		return "", "", err
+	}
+	return address, sig, nil
+}
+
+func (c *Client) createMultisig(ctx context.Context, req models.CreateMultisigRequest) (operation, string, error) {
+	payer, err := signerPublicKey(req.Payer)
+	if err != nil {
+		return operation{}, "", err
+	}
+	program, err := tokenProgramFromString(req.TokenProgram)
+	if err != nil {
+		return operation{}, "", err
+	}
+	if len(req.Signers) == 0 || len(req.Signers) > multisigMaxSigners {
+		return operation{}, "", fmt.Errorf("a multisig takes 1 to %d signers, got %d", multisigMaxSigners, len(req.Signers))
+	}
+	if req.Threshold == 0 || int(req.Threshold) > len(req.Signers) {
+		return operation{}, "", fmt.Errorf("threshold %d is not between 1 and %d signers", req.Threshold, len(req.Signers))
+	}
+
+	multisigAccount := signer.NewKeypair()
+	address := multisigAccount.PublicKey()
+	multisigKey := common.PublicKeyFromString(address)
+
+	// InitializeMultisig2: 19, m u8; accounts are the multisig and then its signers
+	initialize := types.Instruction{
+		ProgramID: program,
+		Accounts:  []types.AccountMeta{{PubKey: multisigKey, IsSigner: false, IsWritable: true}},
+		Data:      []byte{tokenInstructionInitializeMultisig2, req.Threshold},
+	}
+	seen := make(map[common.PublicKey]bool, len(req.Signers))
+	for _, s := range req.Signers {
+		key, err := publicKey(s)
+		if err != nil {
+			return operation{}, "", err
+		}
+		if seen[key] {
+			return operation{}, "", fmt.Errorf("signer %s is listed twice", s)
+		}
+		seen[key] = true
+		initialize.Accounts = append(initialize.Accounts, types.AccountMeta{PubKey: key, IsSigner: false, IsWritable: false})
+	}
+
+	rent, err := c.GetMinimumBalanceForRentExemption(ctx, models.RentRequest{DataLen: multisigSize})
+	if err != nil {
+		return operation{}, "", err
+	}
+	// CreateAccount: 0 (u32 LE), lamports u64, space u64, owner pubkey
+	create := []byte{0, 0, 0, 0}
+	create = binary.LittleEndian.AppendUint64(create, rent)
+	create = binary.LittleEndian.AppendUint64(create, multisigSize)
+	create = append(create, program.Bytes()...)
+
+	return operation{
+		opts:     req.SendOptions,
+		feePayer: req.Payer,
+		instructions: []types.Instruction{
+			{
+				ProgramID: common.SystemProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: payer, IsSigner: true, IsWritable: true},
+					{PubKey: multisigKey, IsSigner: true, IsWritable: true},
+				},
+				Data: create,
+			},
+			initialize,
+		},
+		generated: []models.Signer{multisigAccount},
+	}, address, nil
+}
+
+// GetMultisig returns the threshold and signers of an SPL Token multisig
+func (c *Client) GetMultisig(ctx context.Context, req models.GetMultisigRequest) (*models.Multisig, error) {
+	acc, err := c.tokenAccountInfo(ctx, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if len(acc.Data) != multisigSize {
+		return nil, accountError(req.Address, fmt.Errorf("%w: not a multisig", ErrInvalidAccountData))
+	}
+	n := int(acc.Data[1])
+	if acc.Data[2] == 0 || n > multisigMaxSigners {
+		return nil, accountError(req.Address, fmt.Errorf("%w: multisig is not initialized", ErrInvalidAccountData))
+	}
+	ms := &models.Multisig{
+		Address:      req.Address,
+		TokenProgram: acc.Owner.ToBase58(),
+		Threshold:    acc.Data[0],
+		Signers:      make([]string, 0, n),
+	}
+	for i := 0; i < n; i++ {
+		ms.Signers = append(ms.Signers, base58.Encode(acc.Data[3+32*i:35+32*i]))
+	}
+	return ms, nil
+}
+
+// authorityAccounts returns the accounts that close a token instruction for its authority: the
+// single signer, or the multisig followed by its approving members, who also sign the transaction.
+// The single signer pays the fee either way.
+func authorityAccounts(single models.Signer, multisig *models.MultisigAuthority) ([]types.AccountMeta, []models.Signer, error) {
+	key, err := signerPublicKey(single)
+	if err != nil {
+		return nil, nil, err
+	}
+	if multisig == nil {
+		return []types.AccountMeta{{PubKey: key, IsSigner: true, IsWritable: false}}, nil, nil
+	}
+	address, err := publicKey(multisig.Address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(multisig.Signers) == 0 {
+		return nil, nil, fmt.Errorf("multisig %s needs at least one approving signer", multisig.Address)
+	}
+	metas := []types.AccountMeta{{PubKey: address, IsSigner: false, IsWritable: false}}
+	for _, s := range multisig.Signers {
+		key, err := signerPublicKey(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		metas = append(metas, types.AccountMeta{PubKey: key, IsSigner: true, IsWritable: false})
+	}
+	return metas, multisig.Signers, nil
+}
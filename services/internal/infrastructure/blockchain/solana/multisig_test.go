@@ -0,0 +1,134 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
+)
+
+func TestMultisig_FundWalletNeedsTwoOfThree(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	platform := c.CreateAccount()
+	founder := c.CreateAccount()
+	member := c.CreateAccount()
+	investor := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: platform.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	if _, _, err := c.CreateMultisig(ctx, models.CreateMultisigRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, platform), Signers: []string{founder.PublicKey}, Threshold: 2,
+	}); err == nil {
+		t.Fatal("expected a threshold above the signer count to be refused")
+	}
+	fund, _, err := c.CreateMultisig(ctx, models.CreateMultisigRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, platform), Signers: []string{founder.PublicKey, member.PublicKey, platform.PublicKey}, Threshold: 2,
+	})
+	if err != nil {
+		t.Fatalf("create multisig failed: %v", err)
+	}
+	ms, err := c.GetMultisig(ctx, models.GetMultisigRequest{Address: fund})
+	if err != nil {
+		t.Fatalf("get multisig failed: %v", err)
+	}
+	if ms.Threshold != 2 || len(ms.Signers) != 3 || ms.Signers[0] != founder.PublicKey {
+		t.Fatalf("unexpected multisig: %+v", ms)
+	}
+
+	// the fund controls the mint, freezes and holds its own share account
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, platform), MintAuthority: fund, FreezeAuthority: fund, Decimals: 2,
+	})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	fundATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, platform), Owner: fund, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	investorATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, platform), Owner: investor.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+
+	var txErr *sdk.TransactionError
+	_, err = c.MintTo(ctx, models.MintToRequest{
+		SendOptions: confirmed, MintAuthority: mustSigner(t, platform), Mint: mint, DestinationATA: fundATA, Amount: 10_000,
+		Multisig: &models.MultisigAuthority{Address: fund, Signers: []models.Signer{mustSigner(t, platform)}},
+	})
+	if !errors.As(err, &txErr) || txErr.Name != "MissingRequiredSignature" {
+		t.Fatalf("expected one of three signers to be refused, got %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{
+		SendOptions: confirmed, MintAuthority: mustSigner(t, platform), Mint: mint, DestinationATA: fundATA, Amount: 10_000,
+		Multisig: &models.MultisigAuthority{Address: fund, Signers: []models.Signer{mustSigner(t, platform), mustSigner(t, founder)}},
+	}); err != nil {
+		t.Fatalf("multisig mint failed: %v", err)
+	}
+
+	// founder and member approve a payout on their own devices; the platform pays the fee
+	prepared, err := c.BuildTransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		Authority: signer.NewWatchOnly(platform.PublicKey), SourceATA: fundATA, DestinationATA: investorATA, Mint: mint, Amount: 2_500, Decimals: 2,
+		Multisig: &models.MultisigAuthority{Address: fund, Signers: []models.Signer{signer.NewWatchOnly(founder.PublicKey), signer.NewWatchOnly(member.PublicKey)}},
+	})
+	if err != nil {
+		t.Fatalf("build transfer failed: %v", err)
+	}
+	if len(prepared.Missing) != 3 {
+		t.Fatalf("expected the payer and two members to sign, missing %v", prepared.Missing)
+	}
+	approved, err := c.PartialSign(ctx, models.PartialSignRequest{Transaction: *prepared, Signers: []models.Signer{mustSigner(t, founder)}})
+	if err != nil {
+		t.Fatalf("founder sign failed: %v", err)
+	}
+	msg, err := base64.StdEncoding.DecodeString(approved.Message)
+	if err != nil {
+		t.Fatalf("decode message failed: %v", err)
+	}
+	sig, err := mustSigner(t, member).Sign(msg)
+	if err != nil {
+		t.Fatalf("member sign failed: %v", err)
+	}
+	approved, err = c.AddSignature(models.AddSignatureRequest{Transaction: *approved, PublicKey: member.PublicKey, Signature: base58.Encode(sig)})
+	if err != nil {
+		t.Fatalf("add signature failed: %v", err)
+	}
+	signed, err := c.PartialSign(ctx, models.PartialSignRequest{Transaction: *approved, Signers: []models.Signer{mustSigner(t, platform)}})
+	if err != nil {
+		t.Fatalf("payer sign failed: %v", err)
+	}
+	if _, err := c.Submit(ctx, models.SubmitRequest{SendOptions: confirmed, Transaction: *signed}); err != nil {
+		t.Fatalf("submit failed: %v", err)
+	}
+	ta, err := c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: investorATA})
+	if err != nil || ta.Amount != 2_500 {
+		t.Fatalf("unexpected investor account %+v: %v", ta, err)
+	}
+
+	// the same quorum freezes the investor account
+	if _, err := c.FreezeAccount(ctx, models.FreezeAccountRequest{
+		SendOptions: confirmed, FreezeAuthority: mustSigner(t, platform), Account: investorATA, Mint: mint,
+		Multisig: &models.MultisigAuthority{Address: fund, Signers: []models.Signer{mustSigner(t, founder), mustSigner(t, member)}},
+	}); err != nil {
+		t.Fatalf("multisig freeze failed: %v", err)
+	}
+	_, err = c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: confirmed, Authority: mustSigner(t, platform), SourceATA: fundATA, DestinationATA: investorATA, Mint: mint, Amount: 1, Decimals: 2,
+		Multisig: &models.MultisigAuthority{Address: fund, Signers: []models.Signer{mustSigner(t, founder), mustSigner(t, member)}},
+	})
+	if !errors.As(err, &txErr) || txErr.Name != "AccountFrozen" {
+		t.Fatalf("expected a transfer into a frozen account to fail, got %v", err)
+	}
+}
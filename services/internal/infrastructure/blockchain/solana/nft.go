@@ -0,0 +1,249 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+const (
+	masterEditionKeyV2 = 6
+	// masterEditionMetaSize covers the key, supply and max supply of a master edition account
+	masterEditionMetaSize = 1 + 8 + 9
+
+	metadataInstructionCreateMasterEditionV3 = 17
+)
+
+// MintNFT mints a 0-decimal token with a supply of one, its Metaplex metadata and a master
+// edition in a single transaction; returns the mint and the signature. The master edition takes
+// over the mint authority, which fixes the supply.
+func (c *Client) MintNFT(ctx context.Context, req models.MintNFTRequest) (string, string, error) {
+	op, mint, err := c.mintNFT(ctx, req)
+	if err != nil {
+		return "", "", err
+	}
+	sig, err := c.run(ctx, op)
+	if err != nil {
+		return "", "", err
+	}
+	return mint, sig, nil
+}
+
+func (c *Client) mintNFT(ctx context.Context, req models.MintNFTRequest) (operation, string, error) {
+	payer, err := signerPublicKey(req.Payer)
+	if err != nil {
+		return operation{}, "", err
+	}
+	owner := payer
+	if req.Owner != "" {
+		if owner, err = publicKey(req.Owner); err != nil {
+			return operation{}, "", err
+		}
+	}
+
+	op, mint, err := c.createMint(ctx, models.CreateMintRequest{Payer: req.Payer, MintAuthority: payer.ToBase58(), Decimals: 0})
+	if err != nil {
+		return operation{}, "", err
+	}
+	mintKey := common.PublicKeyFromString(mint)
+	metaOp, metadataAddress, err := c.createTokenMetadata(models.CreateTokenMetadataRequest{
+		Payer:                req.Payer,
+		Mint:                 mint,
+		Name:                 req.Name,
+		Symbol:               req.Symbol,
+		URI:                  req.URI,
+		SellerFeeBasisPoints: req.SellerFeeBasisPoints,
+		Creators:             req.Creators,
+		Collection:           req.Collection,
+		Immutable:            req.Immutable,
+	})
+	if err != nil {
+		return operation{}, "", err
+	}
+	ata, err := deriveATA(owner, mintKey, common.TokenProgramID)
+	if err != nil {
+		return operation{}, "", err
+	}
+	edition, err := deriveEditionPDA(mintKey)
+	if err != nil {
+		return operation{}, "", err
+	}
+
+	instructions := append(op.instructions,
+		// associated token account Create
+		types.Instruction{
+			ProgramID: common.SPLAssociatedTokenAccountProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: payer, IsSigner: true, IsWritable: true},
+				{PubKey: ata, IsSigner: false, IsWritable: true},
+				{PubKey: owner, IsSigner: false, IsWritable: false},
+				{PubKey: mintKey, IsSigner: false, IsWritable: false},
+				{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false},
+				{PubKey: common.TokenProgramID, IsSigner: false, IsWritable: false},
+			},
+			Data: []byte{},
+		},
+		// MintTo one token
+		types.Instruction{
+			ProgramID: common.TokenProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: mintKey, IsSigner: false, IsWritable: true},
+				{PubKey: ata, IsSigner: false, IsWritable: true},
+				{PubKey: payer, IsSigner: true, IsWritable: false},
+			},
+			Data: binary.LittleEndian.AppendUint64([]byte{7}, 1),
+		},
+	)
+	instructions = append(instructions, metaOp.instructions...)
+
+	// CreateMasterEditionV3: 17, max_supply Option<u64>
+	data := binary.LittleEndian.AppendUint64([]byte{metadataInstructionCreateMasterEditionV3, 1}, req.MaxSupply)
+	instructions = append(instructions, types.Instruction{
+		ProgramID: tokenMetadataProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: edition, IsSigner: false, IsWritable: true},
+			{PubKey: mintKey, IsSigner: false, IsWritable: true},
+			{PubKey: payer, IsSigner: true, IsWritable: false},
+			{PubKey: payer, IsSigner: true, IsWritable: false},
+			{PubKey: payer, IsSigner: true, IsWritable: true},
+			{PubKey: common.PublicKeyFromString(metadataAddress), IsSigner: false, IsWritable: true},
+			{PubKey: common.TokenProgramID, IsSigner: false, IsWritable: false},
+			{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false},
+		},
+		Data: data,
+	})
+
+	op.opts = req.SendOptions
+	op.instructions = instructions
+	return op, mint, nil
+}
+
+// TransferNFT moves an NFT to another wallet, creating its associated token account when missing
+func (c *Client) TransferNFT(ctx context.Context, req models.TransferNFTRequest) (string, error) {
+	op, err := c.transferNFT(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) transferNFT(ctx context.Context, req models.TransferNFTRequest) (operation, error) {
+	owner, err := signerPublicKey(req.Owner)
+	if err != nil {
+		return operation{}, err
+	}
+	payer := req.Payer
+	if payer == nil {
+		payer = req.Owner
+	}
+	mint, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: req.Mint})
+	if err != nil {
+		return operation{}, err
+	}
+	if mint.Decimals != 0 || mint.Supply != 1 {
+		return operation{}, accountError(req.Mint, fmt.Errorf("%w: not an NFT mint", ErrInvalidAccountData))
+	}
+	source, err := c.DeriveAssociatedTokenAddress(models.DeriveATARequest{Owner: owner.ToBase58(), Mint: req.Mint, TokenProgram: mint.TokenProgram})
+	if err != nil {
+		return operation{}, err
+	}
+
+	createOp, destination, err := c.createATA(ctx, models.CreateATARequest{Payer: payer, Owner: req.To, Mint: req.Mint}, true)
+	if err != nil {
+		return operation{}, err
+	}
+	transferOp, err := c.transferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		Authority: req.Owner, SourceATA: source, DestinationATA: destination, Mint: req.Mint, Amount: 1, Decimals: 0,
+		Memo: req.Memo, References: req.References,
+	})
+	if err != nil {
+		return operation{}, err
+	}
+	return operation{
+		opts:         req.SendOptions,
+		feePayer:     payer,
+		instructions: append(createOp.instructions, transferOp.instructions...),
+		signers:      []models.Signer{req.Owner},
+	}, nil
+}
+
+// GetMasterEdition reads the master edition of an NFT mint
+func (c *Client) GetMasterEdition(ctx context.Context, req models.GetMasterEditionRequest) (*models.MasterEdition, error) {
+	mint, err := publicKey(req.Mint)
+	if err != nil {
+		return nil, err
+	}
+	edition, err := deriveEditionPDA(mint)
+	if err != nil {
+		return nil, err
+	}
+	address := edition.ToBase58()
+	acc, err := c.c.GetAccountInfo(ctx, address)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	if len(acc.Data) == 0 {
+		return nil, accountError(address, ErrAccountNotFound)
+	}
+	if acc.Owner != tokenMetadataProgramID || len(acc.Data) < masterEditionMetaSize || acc.Data[0] != masterEditionKeyV2 {
+		return nil, accountError(address, fmt.Errorf("%w: not a master edition", ErrInvalidAccountData))
+	}
+	me := &models.MasterEdition{
+		Address: address,
+		Mint:    req.Mint,
+		Supply:  binary.LittleEndian.Uint64(acc.Data[1:9]),
+	}
+	if acc.Data[9] == 1 {
+		maxSupply := binary.LittleEndian.Uint64(acc.Data[10:18])
+		me.MaxSupply = &maxSupply
+	}
+	return me, nil
+}
+
+// VerifyNFTOwner reports whether owner holds the NFT of mint in any of its token accounts. The
+// mint must be a master edition NFT, so that nobody can hold a second token of it.
+func (c *Client) VerifyNFTOwner(ctx context.Context, req models.VerifyNFTOwnerRequest) (bool, error) {
+	if _, err := publicKey(req.Owner); err != nil {
+		return false, err
+	}
+	mint, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: req.Mint})
+	if err != nil {
+		return false, err
+	}
+	edition, err := c.GetMasterEdition(ctx, models.GetMasterEditionRequest{Mint: req.Mint})
+	if err != nil {
+		return false, err
+	}
+	if mint.Decimals != 0 || mint.Supply != 1 || mint.MintAuthority != edition.Address {
+		return false, accountError(req.Mint, fmt.Errorf("%w: not an NFT mint", ErrInvalidAccountData))
+	}
+
+	accounts, err := c.c.GetTokenAccountsByOwnerByMint(ctx, req.Owner, req.Mint)
+	if err != nil {
+		return false, rpcError(err)
+	}
+	for _, acc := range accounts {
+		if acc.Amount == 1 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func deriveEditionPDA(mint common.PublicKey) (common.PublicKey, error) {
+	seeds := [][]byte{
+		[]byte("metadata"),
+		tokenMetadataProgramID.Bytes(),
+		mint.Bytes(),
+		[]byte("edition"),
+	}
+	pda, _, err := common.FindProgramAddress(seeds, tokenMetadataProgramID)
+	if err != nil {
+		return common.PublicKey{}, err
+	}
+	return pda, nil
+}
@@ -0,0 +1,128 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	domain "github.com/whiteelite/superapp/internal/domain/entities"
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/mappers"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+func TestNFT_MintTransferAndVerifyDeed(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	platform := c.CreateAccount()
+	bob := c.CreateAccount()
+	carol := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: platform.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: bob.PublicKey, Lamports: 10_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	subtype := domain.RealEstateSubType("penthouse")
+	estate := domain.RealEstate{
+		Owner:     domain.PublicKey(bob.PublicKey),
+		GeoPoint:  domain.GeoPoint{Latitude: decimal.RequireFromString("41.3851"), Longitude: decimal.RequireFromString("2.1734")},
+		OwnerType: domain.RealEstateOwnerTypeUser,
+		Images: []domain.Image{
+			{PublicImage: "https://example.com/front.png"},
+			{PublicImage: "https://example.com/passport.jpg", IsKYC: true},
+		},
+		Info:  domain.RealEstateInfo{"apartment": {Content: "120 m2", RealEstateSubType: &subtype}},
+		Rooms: &domain.RealEstateRooms{Count: 4},
+	}
+	doc := mappers.ToDeedMetadata("Deed #7", "DEED", estate)
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if doc.Image != "https://example.com/front.png" || strings.Contains(string(raw), "passport") ||
+		!strings.Contains(string(raw), `"trait_type":"apartment_subtype","value":"penthouse"`) {
+		t.Fatalf("unexpected deed document: %s", raw)
+	}
+
+	mint, _, err := c.MintNFT(ctx, models.MintNFTRequest{
+		SendOptions: confirmed,
+		Payer:       mustSigner(t, platform),
+		Owner:       string(estate.Owner),
+		Name:        doc.Name,
+		Symbol:      doc.Symbol,
+		URI:         "https://example.com/deeds/7.json",
+	})
+	if err != nil {
+		t.Fatalf("mint nft failed: %v", err)
+	}
+
+	meta, err := c.GetTokenMetadata(ctx, models.GetTokenMetadataRequest{Mint: mint})
+	if err != nil {
+		t.Fatalf("get metadata failed: %v", err)
+	}
+	if meta.Name != "Deed #7" || meta.UpdateAuthority != platform.PublicKey || meta.Decimals != 0 || !meta.IsMutable ||
+		meta.TokenStandard == nil || *meta.TokenStandard != models.TokenStandardNonFungible {
+		t.Fatalf("unexpected metadata: %+v", meta)
+	}
+	edition, err := c.GetMasterEdition(ctx, models.GetMasterEditionRequest{Mint: mint})
+	if err != nil {
+		t.Fatalf("get master edition failed: %v", err)
+	}
+	info, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: mint})
+	if err != nil {
+		t.Fatalf("get mint failed: %v", err)
+	}
+	if edition.MaxSupply == nil || *edition.MaxSupply != 0 || info.Supply != 1 || info.MintAuthority != edition.Address {
+		t.Fatalf("unexpected edition %+v for mint %+v", edition, info)
+	}
+
+	for owner, want := range map[string]bool{bob.PublicKey: true, platform.PublicKey: false} {
+		ok, err := c.VerifyNFTOwner(ctx, models.VerifyNFTOwnerRequest{Mint: mint, Owner: owner})
+		if err != nil || ok != want {
+			t.Fatalf("verify %s: got %v, %v; want %v", owner, ok, err, want)
+		}
+	}
+
+	// the edition holds the mint authority, so the platform cannot mint a second deed
+	bobATA, err := c.DeriveAssociatedTokenAddress(models.DeriveATARequest{Owner: bob.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("derive ata failed: %v", err)
+	}
+	_, err = c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, platform), Mint: mint, DestinationATA: bobATA, Amount: 1})
+	var txErr *sdk.TransactionError
+	if !errors.As(err, &txErr) {
+		t.Fatalf("expected minting past the edition to fail, got %v", err)
+	}
+
+	// the platform pays for carol's account, bob signs the transfer of his deed
+	if _, err := c.TransferNFT(ctx, models.TransferNFTRequest{
+		SendOptions: confirmed, Owner: mustSigner(t, bob), Payer: mustSigner(t, platform), Mint: mint, To: carol.PublicKey,
+	}); err != nil {
+		t.Fatalf("transfer nft failed: %v", err)
+	}
+	for owner, want := range map[string]bool{carol.PublicKey: true, bob.PublicKey: false} {
+		ok, err := c.VerifyNFTOwner(ctx, models.VerifyNFTOwnerRequest{Mint: mint, Owner: owner})
+		if err != nil || ok != want {
+			t.Fatalf("verify %s after transfer: got %v, %v; want %v", owner, ok, err, want)
+		}
+	}
+
+	// a fungible mint is no deed
+	fungible, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, platform), MintAuthority: platform.PublicKey, Decimals: 0})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	if _, err := c.VerifyNFTOwner(ctx, models.VerifyNFTOwnerRequest{Mint: fungible, Owner: bob.PublicKey}); !errors.Is(err, sdk.ErrAccountNotFound) {
+		t.Fatalf("expected a mint without edition to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
+)
+
+// nonceAccountSize is the size of a versioned System nonce account
+const nonceAccountSize = 80
+
+// CreateNonceAccount creates and initializes a rent-exempt nonce account; returns its address and the signature
+func (c *Client) CreateNonceAccount(ctx context.Context, req models.CreateNonceAccountRequest) (string, string, error) {
+	payer, err := signerPublicKey(req.Payer)
+	if err != nil {
+		return "", "", err
+	}
+	authority := payer
+	if req.Authority != "" {
+		if authority, err = publicKey(req.Authority); err != nil {
+			return "", "", err
+		}
+	}
+
+	nonceAccount := signer.NewKeypair()
+	nonceKey := common.PublicKeyFromString(nonceAccount.PublicKey())
+
+	rent, err := c.GetMinimumBalanceForRentExemption(ctx, models.RentRequest{DataLen: nonceAccountSize})
+	if err != nil {
+		return "", "", err
+	}
+
+	// CreateAccount: 0 (u32 LE), lamports u64, space u64, owner pubkey
+	create := []byte{0, 0, 0, 0}
+	create = binary.LittleEndian.AppendUint64(create, rent)
+	create = binary.LittleEndian.AppendUint64(create, nonceAccountSize)
+	create = append(create, common.SystemProgramID.Bytes()...)
+
+	// InitializeNonceAccount: 6 (u32 LE), authority pubkey
+	initialize := append([]byte{6, 0, 0, 0}, authority.Bytes()...)
+
+	instructions := []types.Instruction{
+		{
+			ProgramID: common.SystemProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: payer, IsSigner: true, IsWritable: true},
+				{PubKey: nonceKey, IsSigner: true, IsWritable: true},
+			},
+			Data: create,
+		},
+		{
+			ProgramID: common.SystemProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: nonceKey, IsSigner: false, IsWritable: true},
+				{PubKey: common.SysVarRecentBlockhashsPubkey, IsSigner: false, IsWritable: false},
+				{PubKey: common.SysVarRentPubkey, IsSigner: false, IsWritable: false},
+			},
+			Data: initialize,
+		},
+	}
+
+	sig, err := c.sendInstructions(ctx, req.SendOptions, req.Payer, instructions, nonceAccount)
+	if err != nil {
+		return "", "", err
+	}
+	return nonceKey.ToBase58(), sig, nil
+}
+
+// GetNonceAccount returns the authority and current value of a nonce account
+func (c *Client) GetNonceAccount(ctx context.Context, req models.GetNonceAccountRequest) (*models.NonceAccount, error) {
+	if _, err := publicKey(req.Address); err != nil {
+		return nil, err
+	}
+	acc, err := c.c.GetAccountInfo(ctx, req.Address)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	if len(acc.Data) == 0 {
+		return nil, accountError(req.Address, ErrAccountNotFound)
+	}
+	if acc.Owner != common.SystemProgramID || len(acc.Data) != nonceAccountSize {
+		return nil, accountError(req.Address, fmt.Errorf("%w: not a nonce account", ErrInvalidAccountData))
+	}
+	// version u32, state u32, authority, nonce, lamports per signature u64
+	if binary.LittleEndian.Uint32(acc.Data[4:8]) != 1 {
+		return nil, accountError(req.Address, fmt.Errorf("%w: nonce account is not initialized", ErrInvalidAccountData))
+	}
+	return &models.NonceAccount{
+		Address:              req.Address,
+		Authority:            base58.Encode(acc.Data[8:40]),
+		Nonce:                base58.Encode(acc.Data[40:72]),
+		LamportsPerSignature: binary.LittleEndian.Uint64(acc.Data[72:80]),
+		Lamports:             acc.Lamports,
+	}, nil
+}
+
+// AdvanceNonceAccount replaces the stored nonce, invalidating transactions signed against it
+func (c *Client) AdvanceNonceAccount(ctx context.Context, req models.AdvanceNonceAccountRequest) (string, error) {
+	authority, err := signerPublicKey(req.Authority)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := publicKey(req.Address)
+	if err != nil {
+		return "", err
+	}
+	inst := advanceNonceInstruction(nonce, authority)
+	return c.sendInstructions(ctx, req.SendOptions, req.Authority, []types.Instruction{inst})
+}
+
+// WithdrawNonceAccount moves lamports out of a nonce account; withdrawing everything closes it
+func (c *Client) WithdrawNonceAccount(ctx context.Context, req models.WithdrawNonceAccountRequest) (string, error) {
+	authority, err := signerPublicKey(req.Authority)
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := publicKey(req.Address)
+	if err != nil {
+		return "", err
+	}
+	to, err := publicKey(req.ToPublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	// WithdrawNonceAccount: 5 (u32 LE), lamports u64
+	data := binary.LittleEndian.AppendUint64([]byte{5, 0, 0, 0}, req.Lamports)
+	inst := types.Instruction{
+		ProgramID: common.SystemProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: nonce, IsSigner: false, IsWritable: true},
+			{PubKey: to, IsSigner: false, IsWritable: true},
+			{PubKey: common.SysVarRecentBlockhashsPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: common.SysVarRentPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: authority, IsSigner: true, IsWritable: false},
+		},
+		Data: data,
+	}
+	return c.sendInstructions(ctx, req.SendOptions, req.Authority, []types.Instruction{inst})
+}
+
+func advanceNonceInstruction(nonce, authority common.PublicKey) types.Instruction {
+	return types.Instruction{
+		ProgramID: common.SystemProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: nonce, IsSigner: false, IsWritable: true},
+			{PubKey: common.SysVarRecentBlockhashsPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: authority, IsSigner: true, IsWritable: false},
+		},
+		Data: []byte{4, 0, 0, 0}, // AdvanceNonceAccount (u32 LE)
+	}
+}
+
+// useNonce prepends the AdvanceNonceAccount instruction, which must come first, and
+// returns the stored nonce to sign with in place of a blockhash
+func (c *Client) useNonce(ctx context.Context, nonce *models.DurableNonce, instructions []types.Instruction) (string, []types.Instruction, error) {
+	authority, err := signerPublicKey(nonce.Authority)
+	if err != nil {
+		return "", nil, err
+	}
+	acc, err := c.GetNonceAccount(ctx, models.GetNonceAccountRequest{Address: nonce.Account})
+	if err != nil {
+		return "", nil, err
+	}
+	if acc.Authority != authority.ToBase58() {
+		return "", nil, fmt.Errorf("nonce account %s is controlled by %s, not %s", nonce.Account, acc.Authority, authority.ToBase58())
+	}
+	advance := advanceNonceInstruction(common.PublicKeyFromString(nonce.Account), authority)
+	return acc.Nonce, append([]types.Instruction{advance}, instructions...), nil
+}
@@ -0,0 +1,79 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func TestNonce_DurableTransfer(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	payer := c.CreateAccount()
+	to := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: payer.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	address, _, err := c.CreateNonceAccount(ctx, models.CreateNonceAccountRequest{SendOptions: confirmed, Payer: mustSigner(t, payer)})
+	if err != nil {
+		t.Fatalf("create nonce account failed: %v", err)
+	}
+	before, err := c.GetNonceAccount(ctx, models.GetNonceAccountRequest{Address: address})
+	if err != nil {
+		t.Fatalf("get nonce account failed: %v", err)
+	}
+	if before.Authority != payer.PublicKey || before.Nonce == "" || before.Lamports != solanatest.RentExemptMinimum(80) {
+		t.Fatalf("unexpected nonce account: %+v", before)
+	}
+
+	// every recent blockhash from before has expired by now
+	srv.AdvanceSlots(300)
+
+	opts := confirmed
+	opts.Nonce = &models.DurableNonce{Account: address, Authority: mustSigner(t, payer)}
+	if _, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: opts, From: mustSigner(t, payer), ToPublicKey: to.PublicKey, Lamports: 1_000_000}); err != nil {
+		t.Fatalf("durable transfer failed: %v", err)
+	}
+	assertBalance(t, ctx, c, to.PublicKey, 1_000_000)
+
+	after, err := c.GetNonceAccount(ctx, models.GetNonceAccountRequest{Address: address})
+	if err != nil {
+		t.Fatalf("get nonce account failed: %v", err)
+	}
+	if after.Nonce == before.Nonce {
+		t.Fatalf("nonce was not advanced")
+	}
+
+	if _, err := c.AdvanceNonceAccount(ctx, models.AdvanceNonceAccountRequest{SendOptions: confirmed, Authority: mustSigner(t, payer), Address: address}); err != nil {
+		t.Fatalf("advance nonce failed: %v", err)
+	}
+	advanced, err := c.GetNonceAccount(ctx, models.GetNonceAccountRequest{Address: address})
+	if err != nil {
+		t.Fatalf("get nonce account failed: %v", err)
+	}
+	if advanced.Nonce == after.Nonce {
+		t.Fatalf("nonce was not advanced")
+	}
+
+	if _, err := c.WithdrawNonceAccount(ctx, models.WithdrawNonceAccountRequest{
+		SendOptions: confirmed,
+		Authority:   mustSigner(t, payer),
+		Address:     address,
+		ToPublicKey: to.PublicKey,
+		Lamports:    advanced.Lamports,
+	}); err != nil {
+		t.Fatalf("withdraw nonce failed: %v", err)
+	}
+	assertBalance(t, ctx, c, to.PublicKey, 1_000_000+advanced.Lamports)
+	if _, err := c.GetNonceAccount(ctx, models.GetNonceAccountRequest{Address: address}); err == nil {
+		t.Fatalf("expected the emptied nonce account to be gone")
+	}
+}
@@ -0,0 +1,284 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	json "github.com/goccy/go-json"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+const (
+	defaultMetadataTimeout  = 10 * time.Second
+	defaultMetadataMaxBytes = 1 << 20
+	defaultMetadataTTL      = 10 * time.Minute
+	// maxCachedDocuments bounds the cache; the entries closest to expiry make room first
+	maxCachedDocuments   = 1024
+	maxMetadataRedirects = 5
+)
+
+// nonPublicPrefixes are the ranges netip does not classify that still never reach a public host
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// WithMetadataFetch limits off-chain metadata downloads to timeout and maxBytes, and keeps the
+// documents for ttl; a zero ttl disables the cache
+func WithMetadataFetch(timeout time.Duration, maxBytes int64, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.metadata.client.Timeout = timeout
+		c.metadata.maxBytes = maxBytes
+		c.metadata.ttl = ttl
+	}
+}
+
+// WithPrivateMetadataHosts lets metadata URIs reach loopback, private and link-local addresses.
+// URIs are set by whoever creates the metadata, so only enable it for tests or a trusted cluster.
+func WithPrivateMetadataHosts() Option {
+	return func(c *Client) {
+		c.metadata.allowPrivate = true
+	}
+}
+
+// metadataFetcher downloads the JSON documents metadata URIs point at. Anyone can set a URI, so
+// it only connects to public addresses, checked after DNS resolution and on every redirect.
+type metadataFetcher struct {
+	client       *http.Client
+	maxBytes     int64
+	ttl          time.Duration
+	allowPrivate bool
+
+	mu    sync.Mutex
+	cache map[string]cachedDocument
+}
+
+// cachedDocument keeps the validated body, so every hit decodes a copy of its own
+type cachedDocument struct {
+	body    []byte
+	expires time.Time
+}
+
+func newMetadataFetcher() *metadataFetcher {
+	f := &metadataFetcher{
+		maxBytes: defaultMetadataMaxBytes,
+		ttl:      defaultMetadataTTL,
+		cache:    map[string]cachedDocument{},
+	}
+	dialer := &net.Dialer{Timeout: defaultMetadataTimeout, Control: f.checkDial}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the only address the dialer sees
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	f.client = &http.Client{
+		Timeout:   defaultMetadataTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxMetadataRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxMetadataRedirects)
+			}
+			_, err := f.checkURL(req.URL.String())
+			return err
+		},
+	}
+	return f
+}
+
+// checkURL accepts http and https URLs whose host, when it is an IP address, is public.
+// Host names are checked once resolved, when the connection is dialed.
+func (f *metadataFetcher) checkURL(uri string) (*url.URL, error) {
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("metadata uri %q is not an http or https URL", uri)
+	}
+	if ip, err := netip.ParseAddr(u.Hostname()); err == nil && !f.allowed(ip) {
+		return nil, fmt.Errorf("%w: %s", ErrMetadataHostNotPublic, uri)
+	}
+	return u, nil
+}
+
+// checkDial runs on the resolved address of every connection
+func (f *metadataFetcher) checkDial(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrMetadataHostNotPublic, address)
+	}
+	if !f.allowed(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrMetadataHostNotPublic, addrPort.Addr())
+	}
+	return nil
+}
+
+func (f *metadataFetcher) allowed(ip netip.Addr) bool {
+	if f.allowPrivate {
+		return true
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// FetchOffChainMetadata downloads and parses the Metaplex JSON document at an http(s) URI
+func (c *Client) FetchOffChainMetadata(ctx context.Context, req models.FetchOffChainMetadataRequest) (*models.OffChainMetadata, error) {
+	return c.metadata.fetch(ctx, req.URI)
+}
+
+// ResolveTokenMetadata reads the metadata of a mint and the document its URI points at, and
+// reports where the document disagrees with the on-chain name and symbol
+func (c *Client) ResolveTokenMetadata(ctx context.Context, req models.ResolveTokenMetadataRequest) (*models.ResolvedTokenMetadata, error) {
+	meta, err := c.GetTokenMetadata(ctx, models.GetTokenMetadataRequest{Mint: req.Mint})
+	if err != nil {
+		return nil, err
+	}
+	res := &models.ResolvedTokenMetadata{TokenMetadata: *meta}
+	if meta.URI == "" {
+		return res, nil
+	}
+	doc, err := c.metadata.fetch(ctx, meta.URI)
+	if err != nil {
+		return nil, err
+	}
+	res.OffChain = doc
+	if doc.Name != meta.Name {
+		res.Mismatches = append(res.Mismatches, fmt.Sprintf("name %q differs from %q on chain", doc.Name, meta.Name))
+	}
+	// the standard makes the symbol optional in the document
+	if doc.Symbol != "" && doc.Symbol != meta.Symbol {
+		res.Mismatches = append(res.Mismatches, fmt.Sprintf("symbol %q differs from %q on chain", doc.Symbol, meta.Symbol))
+	}
+	return res, nil
+}
+
+func (f *metadataFetcher) fetch(ctx context.Context, uri string) (*models.OffChainMetadata, error) {
+	if _, err := f.checkURL(uri); err != nil {
+		return nil, err
+	}
+	if doc, ok := f.cached(uri); ok {
+		return doc, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch metadata %s: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch metadata %s: %s", uri, resp.Status)
+	}
+	if resp.ContentLength > f.maxBytes {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidMetadataDocument, uri, f.maxBytes)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("fetch metadata %s: %w", uri, err)
+	}
+	if int64(len(body)) > f.maxBytes {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidMetadataDocument, uri, f.maxBytes)
+	}
+
+	var doc models.OffChainMetadata
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidMetadataDocument, uri, err)
+	}
+	if err := validateDocument(doc); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidMetadataDocument, uri, err)
+	}
+	f.store(uri, body)
+	return &doc, nil
+}
+
+// validateDocument checks the parts of the standard clients rely on to render a token
+func validateDocument(doc models.OffChainMetadata) error {
+	if doc.Name == "" {
+		return fmt.Errorf("name is missing")
+	}
+	for i, attr := range doc.Attributes {
+		if attr.TraitType == "" {
+			return fmt.Errorf("attribute %d has no trait_type", i)
+		}
+	}
+	if doc.Properties != nil {
+		for i, file := range doc.Properties.Files {
+			if file.URI == "" {
+				return fmt.Errorf("file %d has no uri", i)
+			}
+		}
+	}
+	return nil
+}
+
+// cached returns a fresh copy of the document of uri unless it expired
+func (f *metadataFetcher) cached(uri string) (*models.OffChainMetadata, bool) {
+	f.mu.Lock()
+	entry, ok := f.cache[uri]
+	if ok && time.Now().After(entry.expires) {
+		delete(f.cache, uri)
+		ok = false
+	}
+	f.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	var doc models.OffChainMetadata
+	if err := json.Unmarshal(entry.body, &doc); err != nil {
+		return nil, false
+	}
+	return &doc, true
+}
+
+func (f *metadataFetcher) store(uri string, body []byte) {
+	if f.ttl <= 0 {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.cache[uri]; !ok && len(f.cache) >= maxCachedDocuments {
+		f.evict()
+	}
+	f.cache[uri] = cachedDocument{body: body, expires: time.Now().Add(f.ttl)}
+}
+
+// evict drops the expired documents, or the one closest to expiry when none has
+func (f *metadataFetcher) evict() {
+	now := time.Now()
+	var (
+		oldest  string
+		expires time.Time
+	)
+	for uri, entry := range f.cache {
+		if now.After(entry.expires) {
+			delete(f.cache, uri)
+			continue
+		}
+		if oldest == "" || entry.expires.Before(expires) {
+			oldest, expires = uri, entry.expires
+		}
+	}
+	if len(f.cache) >= maxCachedDocuments {
+		delete(f.cache, oldest)
+	}
+}
@@ -0,0 +1,162 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+const fundDocument = `{
+	"name": "Fund Share",
+	"symbol": "FUND",
+	"description": "A share of the fund",
+	"image": "https://example.com/fund.png",
+	"attributes": [{"trait_type": "series", "value": "A"}, {"trait_type": "year", "value": 2026}],
+	"properties": {"files": [{"uri": "https://example.com/fund.png", "type": "image/png"}], "category": "image"}
+}`
+
+func TestOffChainMetadata_ResolveValidatesAndCaches(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	docs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/fund.json":
+			_, _ = w.Write([]byte(fundDocument))
+		case "/renamed.json":
+			_, _ = w.Write([]byte(`{"name": "Something Else", "symbol": "ELSE"}`))
+		case "/large.json":
+			_, _ = w.Write([]byte(`{"name": "` + strings.Repeat("x", 4096) + `"}`))
+		case "/slow.json":
+			time.Sleep(300 * time.Millisecond)
+			_, _ = w.Write([]byte(fundDocument))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docs.Close()
+
+	srv := solanatest.NewServer()
+	t.Cleanup(srv.Close)
+	c, err := sdk.NewClient(srv.URL,
+		sdk.WithConfirmationPolling(time.Millisecond, 5*time.Millisecond),
+		sdk.WithMetadataFetch(100*time.Millisecond, 2048, 200*time.Millisecond),
+		sdk.WithPrivateMetadataHosts(),
+	)
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	newToken := func(name, symbol, uri string) string {
+		t.Helper()
+		mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 6})
+		if err != nil {
+			t.Fatalf("create mint failed: %v", err)
+		}
+		if _, _, err := c.CreateTokenMetadata(ctx, models.CreateTokenMetadataRequest{
+			SendOptions: confirmed, Payer: mustSigner(t, alice), Mint: mint, Name: name, Symbol: symbol, URI: uri,
+		}); err != nil {
+			t.Fatalf("create metadata failed: %v", err)
+		}
+		return mint
+	}
+
+	fund := newToken("Fund Share", "FUND", docs.URL+"/fund.json")
+	res, err := c.ResolveTokenMetadata(ctx, models.ResolveTokenMetadataRequest{Mint: fund})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if len(res.Mismatches) != 0 || res.Name != "Fund Share" || res.OffChain == nil || res.OffChain.Image != "https://example.com/fund.png" ||
+		len(res.OffChain.Attributes) != 2 || res.OffChain.Properties == nil || len(res.OffChain.Properties.Files) != 1 {
+		t.Fatalf("unexpected resolution: %+v %+v", res, res.OffChain)
+	}
+	if _, err := c.ResolveTokenMetadata(ctx, models.ResolveTokenMetadataRequest{Mint: fund}); err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected the document to be cached, fetched %d times", hits.Load())
+	}
+	// callers get copies they may change without touching the cache
+	res.OffChain.Attributes[0].TraitType = "changed"
+	again, err := c.FetchOffChainMetadata(ctx, models.FetchOffChainMetadataRequest{URI: docs.URL + "/fund.json"})
+	if err != nil || again.Attributes[0].TraitType != "series" || hits.Load() != 1 {
+		t.Fatalf("expected an untouched cached copy, got %+v: %v", again, err)
+	}
+	time.Sleep(250 * time.Millisecond)
+	if _, err := c.FetchOffChainMetadata(ctx, models.FetchOffChainMetadataRequest{URI: docs.URL + "/fund.json"}); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Fatalf("expected the cached document to expire, fetched %d times", hits.Load())
+	}
+
+	renamed := newToken("Fund Share", "FUND", docs.URL+"/renamed.json")
+	res, err = c.ResolveTokenMetadata(ctx, models.ResolveTokenMetadataRequest{Mint: renamed})
+	if err != nil {
+		t.Fatalf("resolve failed: %v", err)
+	}
+	if len(res.Mismatches) != 2 {
+		t.Fatalf("expected name and symbol mismatches, got %q", res.Mismatches)
+	}
+
+	_, err = c.FetchOffChainMetadata(ctx, models.FetchOffChainMetadataRequest{URI: docs.URL + "/large.json"})
+	if !errors.Is(err, sdk.ErrInvalidMetadataDocument) {
+		t.Fatalf("expected the size limit to apply, got %v", err)
+	}
+	if _, err := c.FetchOffChainMetadata(ctx, models.FetchOffChainMetadataRequest{URI: docs.URL + "/slow.json"}); err == nil {
+		t.Fatal("expected the time limit to apply")
+	}
+	if _, err := c.FetchOffChainMetadata(ctx, models.FetchOffChainMetadataRequest{URI: docs.URL + "/missing.json"}); err == nil {
+		t.Fatal("expected a missing document to fail")
+	}
+	if _, err := c.FetchOffChainMetadata(ctx, models.FetchOffChainMetadataRequest{URI: "ftp://example.com/fund.json"}); err == nil {
+		t.Fatal("expected only http and https to be fetched")
+	}
+}
+
+func TestOffChainMetadata_RejectsPrivateHosts(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int32
+	docs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(fundDocument))
+	}))
+	defer docs.Close()
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, port, _ := strings.Cut(strings.TrimPrefix(docs.URL, "http://"), ":")
+	for _, uri := range []string{
+		docs.URL + "/fund.json",
+		"http://localhost:" + port + "/fund.json",
+		"http://[::1]:" + port + "/fund.json",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/fund.json",
+		"http://[::ffff:127.0.0.1]:" + port + "/fund.json",
+	} {
+		if _, err := c.FetchOffChainMetadata(ctx, models.FetchOffChainMetadataRequest{URI: uri}); !errors.Is(err, sdk.ErrMetadataHostNotPublic) {
+			t.Errorf("%s: expected ErrMetadataHostNotPublic, got %v", uri, err)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Fatalf("expected no request to reach the private host, got %d", hits.Load())
+	}
+}
@@ -0,0 +1,553 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	json "github.com/goccy/go-json"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// poolEndpoint is the URL the rpc client posts to; the pool transport rewrites it to a real endpoint
+const poolEndpoint = "http://rpc-pool.invalid"
+
+// WithNetworkEndpoints sets the RPC URLs used for a network instead of its single public default
+func WithNetworkEndpoints(network Network, urls ...string) Option {
+	return func(c *Client) {
+		c.networkEndpoints[network] = append([]string(nil), urls...)
+	}
+}
+
+// WithHealthCheck probes every endpoint each interval. Endpoints that fail getHealth or trail
+// the most advanced one by more than maxSlotLag slots only receive requests when no healthy one is left.
+func WithHealthCheck(interval time.Duration, maxSlotLag uint64) Option {
+	return func(c *Client) {
+		c.healthInterval = interval
+		c.pool.maxSlotLag = maxSlotLag
+	}
+}
+
+// WithRateLimit caps the request rate sent to each endpoint; requests overflow to other endpoints first
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.pool.rateLimit = requestsPerSecond
+		c.pool.burst = burst
+	}
+}
+
+// SetEndpoints replaces the RPC endpoints; statistics of endpoints that stay are kept.
+// It is safe to call while requests are in flight.
+func (c *Client) SetEndpoints(urls ...string) error {
+	return c.pool.setEndpoints(urls)
+}
+
+// EndpointStats returns latency, error and health statistics of every endpoint
+func (c *Client) EndpointStats() []models.EndpointStats {
+	return c.pool.stats()
+}
+
+// CheckEndpoints runs a health and slot-lag check now and returns the updated statistics
+func (c *Client) CheckEndpoints(ctx context.Context) []models.EndpointStats {
+	c.pool.checkHealth(ctx)
+	return c.pool.stats()
+}
+
+// Close stops background health checks
+func (c *Client) Close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+func (c *Client) networkURLs(network Network) []string {
+	if urls := c.networkEndpoints[network]; len(urls) > 0 {
+		return urls
+	}
+	return []string{DefaultRPCURL(network)}
+}
+
+func (c *Client) startHealthChecks() {
+	if c.healthInterval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(c.healthInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), c.healthInterval)
+				c.pool.checkHealth(ctx)
+				cancel()
+			}
+		}
+	}()
+}
+
+// rpcPool is an http.RoundTripper that spreads JSON-RPC requests over healthy endpoints and fails
+// over on transport errors, 429 and 5xx responses, and nodes answering that they are behind or rate limited
+type rpcPool struct {
+	base       http.RoundTripper
+	rateLimit  float64
+	burst      int
+	maxSlotLag uint64
+
+	mu        sync.RWMutex
+	endpoints []*endpoint
+	next      atomic.Uint64
+}
+
+func newRPCPool() *rpcPool {
+	return &rpcPool{base: http.DefaultTransport}
+}
+
+// setEndpoints validates every URL before replacing any endpoint
+func (p *rpcPool) setEndpoints(urls []string) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("at least one RPC endpoint is required")
+	}
+	targets := make([]*url.URL, len(urls))
+	for i, raw := range urls {
+		target, err := parseEndpoint(raw)
+		if err != nil {
+			return err
+		}
+		targets[i] = target
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	existing := map[string]*endpoint{}
+	for _, e := range p.endpoints {
+		existing[e.url] = e
+	}
+	endpoints := make([]*endpoint, 0, len(urls))
+	for i, raw := range urls {
+		if e, ok := existing[raw]; ok {
+			endpoints = append(endpoints, e)
+			continue
+		}
+		endpoints = append(endpoints, &endpoint{url: raw, target: targets[i], limiter: newTokenBucket(p.rateLimit, p.burst)})
+	}
+	p.endpoints = endpoints
+	return nil
+}
+
+func parseEndpoint(raw string) (*url.URL, error) {
+	target, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RPC endpoint %q: %w", raw, err)
+	}
+	if (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("invalid RPC endpoint %q: expected an http or https URL", raw)
+	}
+	return target, nil
+}
+
+func (p *rpcPool) snapshot() []*endpoint {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.endpoints
+}
+
+// order returns healthy endpoints in round-robin order followed by unhealthy ones as a last resort
+func (p *rpcPool) order() []*endpoint {
+	endpoints := p.snapshot()
+	if len(endpoints) == 0 {
+		return nil
+	}
+	start := int(p.next.Add(1) % uint64(len(endpoints)))
+	healthy := make([]*endpoint, 0, len(endpoints))
+	var unhealthy []*endpoint
+	for i := range endpoints {
+		e := endpoints[(start+i)%len(endpoints)]
+		if e.isHealthy() {
+			healthy = append(healthy, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+func (p *rpcPool) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	candidates := p.order()
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no RPC endpoints configured")
+	}
+
+	// rate-limited endpoints are only waited for once every other candidate failed
+	var (
+		deferred []*endpoint
+		lastErr  error
+	)
+	for _, e := range candidates {
+		if !e.limiter.allow() {
+			deferred = append(deferred, e)
+			continue
+		}
+		res, err := p.try(req, body, e)
+		if err == nil || req.Context().Err() != nil {
+			return res, err
+		}
+		lastErr = err
+	}
+	for _, e := range deferred {
+		if err := e.limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+		res, err := p.try(req, body, e)
+		if err == nil || req.Context().Err() != nil {
+			return res, err
+		}
+		lastErr = err
+	}
+	return exhausted(req, lastErr)
+}
+
+// statusError is an endpoint answering with a status the pool fails over on
+type statusError struct {
+	url    string
+	status int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: status %d", e.url, e.status)
+}
+
+// nodeError is an endpoint answering with a JSON-RPC error the pool fails over on
+type nodeError struct {
+	url     string
+	code    int
+	message string
+}
+
+func (e *nodeError) Error() string {
+	return fmt.Sprintf("%s: %s", e.url, e.message)
+}
+
+// failoverCode returns the JSON-RPC error of a node that is behind or rate limited, which another
+// endpoint may not give; batch responses and other errors pass through to the caller
+func failoverCode(body []byte) (int, string, bool) {
+	var envelope struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &envelope) != nil || envelope.Error == nil {
+		return 0, "", false
+	}
+	switch envelope.Error.Code {
+	case rpcCodeNodeUnhealthy, rpcCodeRateLimited:
+		return envelope.Error.Code, envelope.Error.Message, true
+	}
+	return 0, "", false
+}
+
+// exhausted answers a request every endpoint failed with a JSON-RPC error, since the rpc client
+// only keeps the text of transport errors. It is rate limited when the last endpoint was.
+func exhausted(req *http.Request, lastErr error) (*http.Response, error) {
+	code := rpcCodeNodeUnhealthy
+	var (
+		status *statusError
+		node   *nodeError
+	)
+	if errors.As(lastErr, &status) && status.status == http.StatusTooManyRequests {
+		code = rpcCodeRateLimited
+	}
+	if errors.As(lastErr, &node) {
+		code = node.code
+	}
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"error":   map[string]any{"code": code, "message": lastErr.Error()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (p *rpcPool) try(req *http.Request, body []byte, e *endpoint) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	target := *e.target
+	out.URL = &target
+	out.Host = target.Host
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	out.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+
+	start := time.Now()
+	res, err := p.base.RoundTrip(out)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", e.url, err)
+		e.record(0, err, true)
+		return nil, err
+	}
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+		res.Body.Close()
+		err := &statusError{url: e.url, status: res.StatusCode}
+		e.record(0, err, res.StatusCode != http.StatusTooManyRequests)
+		return nil, err
+	}
+	// a node behind the cluster or over its rate limit still answers 200 with a JSON-RPC error
+	payload, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		err = fmt.Errorf("%s: %w", e.url, err)
+		e.record(0, err, true)
+		return nil, err
+	}
+	if code, message, ok := failoverCode(payload); ok {
+		err := &nodeError{url: e.url, code: code, message: message}
+		e.record(0, err, code != rpcCodeRateLimited)
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(payload))
+	res.ContentLength = int64(len(payload))
+	e.record(time.Since(start), nil, false)
+	return res, nil
+}
+
+func (p *rpcPool) checkHealth(ctx context.Context) {
+	endpoints := p.snapshot()
+	slots := make([]uint64, len(endpoints))
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots[i], errs[i] = p.probe(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	var best uint64
+	for i := range endpoints {
+		if errs[i] == nil {
+			best = max(best, slots[i])
+		}
+	}
+	for i, e := range endpoints {
+		e.setHealth(slots[i], best-min(slots[i], best), p.maxSlotLag, errs[i])
+	}
+}
+
+// probe asks an endpoint for getHealth and its current slot
+func (p *rpcPool) probe(ctx context.Context, e *endpoint) (uint64, error) {
+	var health string
+	if err := p.call(ctx, e, "getHealth", &health); err != nil {
+		return 0, err
+	}
+	if health != "ok" {
+		return 0, fmt.Errorf("getHealth returned %q", health)
+	}
+	var slot uint64
+	if err := p.call(ctx, e, "getSlot", &slot); err != nil {
+		return 0, err
+	}
+	return slot, nil
+}
+
+func (p *rpcPool) call(ctx context.Context, e *endpoint, method string, result any) error {
+	payload, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": 1, "method": method})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := p.base.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", res.StatusCode)
+	}
+	var out struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return err
+	}
+	if out.Error != nil {
+		return fmt.Errorf("%s: %s", method, out.Error.Message)
+	}
+	return json.Unmarshal(out.Result, result)
+}
+
+func (p *rpcPool) stats() []models.EndpointStats {
+	endpoints := p.snapshot()
+	stats := make([]models.EndpointStats, 0, len(endpoints))
+	for _, e := range endpoints {
+		stats = append(stats, e.stats())
+	}
+	return stats
+}
+
+type endpoint struct {
+	url     string
+	target  *url.URL
+	limiter *tokenBucket
+
+	mu sync.Mutex
+	// down is set by a failed request and cleared by a success or a passing health check
+	down        bool
+	checkErr    error
+	lagging     bool
+	slot        uint64
+	slotLag     uint64
+	requests    uint64
+	errors      uint64
+	successes   uint64
+	latency     time.Duration
+	lastLatency time.Duration
+	lastErr     string
+	lastChecked time.Time
+}
+
+func (e *endpoint) isHealthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !e.down && !e.lagging && e.checkErr == nil
+}
+
+func (e *endpoint) record(latency time.Duration, err error, down bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests++
+	if err != nil {
+		e.errors++
+		e.lastErr = err.Error()
+		e.down = e.down || down
+		return
+	}
+	e.successes++
+	e.latency += latency
+	e.lastLatency = latency
+	e.down = false
+}
+
+func (e *endpoint) setHealth(slot, lag, maxLag uint64, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastChecked = time.Now()
+	e.checkErr = err
+	if err != nil {
+		e.lastErr = err.Error()
+		return
+	}
+	e.down = false
+	e.slot, e.slotLag = slot, lag
+	e.lagging = maxLag > 0 && lag > maxLag
+}
+
+func (e *endpoint) stats() models.EndpointStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s := models.EndpointStats{
+		URL:         e.url,
+		Healthy:     !e.down && !e.lagging && e.checkErr == nil,
+		Slot:        e.slot,
+		SlotLag:     e.slotLag,
+		Requests:    e.requests,
+		Errors:      e.errors,
+		LastLatency: e.lastLatency,
+		LastError:   e.lastErr,
+		LastChecked: e.lastChecked,
+	}
+	if e.successes > 0 {
+		s.AvgLatency = e.latency / time.Duration(e.successes)
+	}
+	return s
+}
+
+// tokenBucket is a minimal rate limiter; a nil bucket never limits
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	b := float64(max(burst, 1))
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: time.Now()}
+}
+
+// take consumes a token or returns how long until one is available
+func (b *tokenBucket) take() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) allow() bool {
+	return b == nil || b.take() == 0
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		d := b.take()
+		if d == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
+		}
+	}
+}
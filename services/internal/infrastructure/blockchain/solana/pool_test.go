@@ -0,0 +1,249 @@
+package sdk_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func TestPool_FailsOverToHealthyEndpoint(t *testing.T) {
+	t.Parallel()
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	srv := solanatest.NewServer()
+	defer srv.Close()
+
+	c, err := sdk.NewClient(down.URL)
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer c.Close()
+	if err := c.SetEndpoints(down.URL, srv.URL); err != nil {
+		t.Fatalf("set endpoints failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	acc := c.CreateAccount()
+	for i := 0; i < 4; i++ {
+		if _, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: acc.PublicKey}); err != nil {
+			t.Fatalf("get balance %d failed: %v", i, err)
+		}
+	}
+
+	stats := c.EndpointStats()
+	if len(stats) != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	// the failing endpoint is tried at most once before it is marked down
+	if stats[0].Healthy || stats[0].Errors != 1 || stats[0].LastError == "" {
+		t.Fatalf("unexpected stats for the failing endpoint: %+v", stats[0])
+	}
+	if !stats[1].Healthy || stats[1].Requests != 4 || stats[1].Errors != 0 || stats[1].AvgLatency <= 0 {
+		t.Fatalf("unexpected stats for the healthy endpoint: %+v", stats[1])
+	}
+}
+
+func TestPool_FailsOverNodeBehind(t *testing.T) {
+	t.Parallel()
+
+	// a node behind the cluster answers 200 with a JSON-RPC error
+	behind := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"Node is behind by 120 slots","data":{"numSlotsBehind":120}}}`))
+	}))
+	defer behind.Close()
+	srv := solanatest.NewServer()
+	defer srv.Close()
+
+	c, err := sdk.NewClient(behind.URL)
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer c.Close()
+	if err := c.SetEndpoints(behind.URL, srv.URL); err != nil {
+		t.Fatalf("set endpoints failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	acc := c.CreateAccount()
+	for i := 0; i < 4; i++ {
+		if _, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: acc.PublicKey}); err != nil {
+			t.Fatalf("get balance %d failed: %v", i, err)
+		}
+	}
+
+	stats := c.EndpointStats()
+	if stats[0].Healthy || stats[0].Errors != 1 || !strings.Contains(stats[0].LastError, "behind") {
+		t.Fatalf("unexpected stats for the endpoint behind: %+v", stats[0])
+	}
+	if !stats[1].Healthy || stats[1].Errors != 0 {
+		t.Fatalf("unexpected stats for the healthy endpoint: %+v", stats[1])
+	}
+}
+
+func TestPool_SlotLagHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	ahead := solanatest.NewServer()
+	defer ahead.Close()
+	behind := solanatest.NewServer()
+	defer behind.Close()
+	ahead.AdvanceSlots(100)
+
+	c, err := sdk.NewClientForNetwork(sdk.NetworkDevnet,
+		sdk.WithNetworkEndpoints(sdk.NetworkDevnet, behind.URL, ahead.URL),
+		sdk.WithHealthCheck(time.Hour, 10),
+	)
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stats := c.CheckEndpoints(ctx)
+	if stats[0].Healthy || stats[0].SlotLag < 90 {
+		t.Fatalf("lagging endpoint should be unhealthy: %+v", stats[0])
+	}
+	if !stats[1].Healthy || stats[1].SlotLag != 0 || stats[1].Slot != ahead.Slot() {
+		t.Fatalf("leading endpoint should be healthy: %+v", stats[1])
+	}
+
+	acc := c.CreateAccount()
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: acc.PublicKey}); err != nil {
+			t.Fatalf("get balance failed: %v", err)
+		}
+	}
+	stats = c.EndpointStats()
+	if stats[0].Requests != 0 || stats[1].Requests != 3 {
+		t.Fatalf("requests should avoid the lagging endpoint: %+v", stats)
+	}
+}
+
+func TestPool_RateLimitSpreadsRequests(t *testing.T) {
+	t.Parallel()
+
+	a := solanatest.NewServer()
+	defer a.Close()
+	b := solanatest.NewServer()
+	defer b.Close()
+
+	c, err := sdk.NewClient(a.URL, sdk.WithRateLimit(0.001, 1))
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer c.Close()
+	if err := c.SetEndpoints(a.URL, b.URL); err != nil {
+		t.Fatalf("set endpoints failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	acc := c.CreateAccount()
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: acc.PublicKey}); err != nil {
+			t.Fatalf("get balance failed: %v", err)
+		}
+	}
+	stats := c.EndpointStats()
+	if stats[0].Requests != 1 || stats[1].Requests != 1 {
+		t.Fatalf("each endpoint should serve one request: %+v", stats)
+	}
+
+	// both buckets are empty; the next request waits until the context gives up
+	short, cancelShort := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelShort()
+	if _, err := c.GetBalance(short, models.BalanceRequest{PublicKey: acc.PublicKey}); err == nil {
+		t.Fatalf("expected the rate-limited request to time out")
+	}
+}
+
+func TestPool_ConcurrentEndpointChanges(t *testing.T) {
+	t.Parallel()
+
+	a := solanatest.NewServer()
+	defer a.Close()
+	b := solanatest.NewServer()
+	defer b.Close()
+
+	c, err := sdk.NewClient(a.URL)
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	acc := c.CreateAccount()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if _, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: acc.PublicKey}); err != nil {
+					t.Errorf("get balance failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 20; i++ {
+		if i%2 == 0 {
+			if err := c.SwitchNetworkByURL(b.URL); err != nil {
+				t.Fatalf("switch network failed: %v", err)
+			}
+		} else if err := c.SetEndpoints(a.URL, b.URL); err != nil {
+			t.Fatalf("set endpoints failed: %v", err)
+		}
+	}
+	wg.Wait()
+}
+
+func TestPool_RejectsInvalidEndpoints(t *testing.T) {
+	t.Parallel()
+
+	srv := solanatest.NewServer()
+	defer srv.Close()
+
+	for _, raw := range []string{"", "://missing-scheme", "localhost:8899", "ftp://example.com", "http://"} {
+		if _, err := sdk.NewClient(raw); err == nil {
+			t.Errorf("expected %q to be rejected", raw)
+		}
+	}
+	if _, err := sdk.NewClientForNetwork(sdk.NetworkDevnet, sdk.WithNetworkEndpoints(sdk.NetworkDevnet, srv.URL, "not a url")); err == nil {
+		t.Error("expected an invalid network endpoint to be rejected")
+	}
+
+	c, err := sdk.NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	defer c.Close()
+	if err := c.SwitchNetworkByURL("http//typo"); err == nil {
+		t.Fatal("expected an invalid switch to be rejected")
+	}
+	if err := c.SetEndpoints(srv.URL, "%zz"); err == nil {
+		t.Fatal("expected invalid endpoints to be rejected")
+	}
+	// the client keeps its working endpoint
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: c.CreateAccount().PublicKey}); err != nil {
+		t.Fatalf("get balance failed: %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package sdk
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// maxMultipleAccounts is the most keys getMultipleAccounts takes per call
+const maxMultipleAccounts = 100
+
+// GetPortfolio lists the SOL balance and every Token and Token-2022 account of an owner, with
+// the decimals and Metaplex metadata of their mints
+func (c *Client) GetPortfolio(ctx context.Context, req models.GetPortfolioRequest) (*models.Portfolio, error) {
+	owner, err := publicKey(req.Owner)
+	if err != nil {
+		return nil, err
+	}
+	lamports, err := c.c.GetBalance(ctx, owner.ToBase58())
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	portfolio := &models.Portfolio{Owner: owner.ToBase58(), SOL: models.NewAmount(lamports, solDecimals)}
+
+	var accounts []*models.TokenAccount
+	var addresses []string
+	for _, program := range []common.PublicKey{common.TokenProgramID, common.Token2022ProgramID} {
+		res, err := c.c.RpcClient.GetTokenAccountsByOwnerWithConfig(ctx, owner.ToBase58(),
+			rpc.GetTokenAccountsByOwnerConfigFilter{ProgramId: program.ToBase58()},
+			rpc.GetTokenAccountsByOwnerConfig{Encoding: rpc.AccountEncodingBase64},
+		)
+		if err != nil {
+			return nil, rpcError(err)
+		}
+		if res.Error != nil {
+			return nil, rpcError(res.Error)
+		}
+		for _, acc := range res.Result.Value {
+			data, err := accountData(acc.Account)
+			if err != nil {
+				return nil, accountError(acc.Pubkey, err)
+			}
+			ta, err := parseTokenAccount(acc.Pubkey, program, data)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, ta)
+			addresses = append(addresses, acc.Pubkey)
+		}
+	}
+
+	// one lookup per mint, however many accounts hold it
+	var mints []string
+	seen := make(map[string]bool)
+	for _, ta := range accounts {
+		if !seen[ta.Mint] {
+			seen[ta.Mint] = true
+			mints = append(mints, ta.Mint)
+		}
+	}
+	decimals, metadata, err := c.mintDetails(ctx, mints)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, ta := range accounts {
+		ata, err := deriveATA(owner, common.PublicKeyFromString(ta.Mint), common.PublicKeyFromString(ta.TokenProgram))
+		if err != nil {
+			return nil, err
+		}
+		dec, known := decimals[ta.Mint]
+		amount := models.UnscaledAmount(ta.Amount)
+		if known {
+			amount = models.NewAmount(ta.Amount, dec)
+		}
+		portfolio.Tokens = append(portfolio.Tokens, models.PortfolioToken{
+			Account:      addresses[i],
+			Mint:         ta.Mint,
+			TokenProgram: ta.TokenProgram,
+			Amount:       amount,
+			Metadata:     metadata[ta.Mint],
+			UnknownMint:  !known,
+			NonATA:       ata.ToBase58() != addresses[i],
+			ZeroBalance:  ta.Amount == 0,
+			Frozen:       ta.Frozen,
+		})
+	}
+	return portfolio, nil
+}
+
+// mintDetails fetches the decimals and metadata of mints, together with their metadata accounts.
+// Anyone can create a token and send it to a wallet, so a mint or metadata account that does
+// not decode is left out of the maps instead of failing the portfolio, as is a closed mint.
+func (c *Client) mintDetails(ctx context.Context, mints []string) (map[string]uint8, map[string]*models.TokenMetadata, error) {
+	keys := make([]string, 0, 2*len(mints))
+	for _, mint := range mints {
+		metaPDA, err := deriveMetadataPDA(common.PublicKeyFromString(mint))
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, mint, metaPDA.ToBase58())
+	}
+	infos := make([]client.AccountInfo, 0, len(keys))
+	for start := 0; start < len(keys); start += maxMultipleAccounts {
+		end := min(start+maxMultipleAccounts, len(keys))
+		batch, err := c.c.GetMultipleAccounts(ctx, keys[start:end])
+		if err != nil {
+			return nil, nil, rpcError(err)
+		}
+		infos = append(infos, batch...)
+	}
+	if len(infos) != len(keys) {
+		return nil, nil, fmt.Errorf("%w: expected %d accounts, got %d", ErrInvalidAccountData, len(keys), len(infos))
+	}
+
+	decimals := make(map[string]uint8, len(mints))
+	metadata := make(map[string]*models.TokenMetadata, len(mints))
+	for i, mint := range mints {
+		if acc := infos[2*i]; len(acc.Data) > 0 {
+			if info, err := parseMint(mint, acc.Owner, acc.Data); err == nil {
+				decimals[mint] = info.Decimals
+			}
+		}
+		dec, ok := decimals[mint]
+		if acc := infos[2*i+1]; ok && len(acc.Data) > 0 {
+			if meta, err := decodeMetadataAccount(keys[2*i+1], acc); err == nil {
+				meta.Decimals = dec
+				metadata[mint] = meta
+			}
+		}
+	}
+	return decimals, metadata, nil
+}
+
+// accountData decodes the base64 data of an account returned by the raw RPC client
+func accountData(acc rpc.AccountInfo) ([]byte, error) {
+	data, ok := acc.Data.([]any)
+	if !ok || len(data) != 2 || data[1] != string(rpc.AccountEncodingBase64) {
+		return nil, fmt.Errorf("%w: expected base64 account data", ErrInvalidAccountData)
+	}
+	encoded, _ := data[0].(string)
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAccountData, err)
+	}
+	return raw, nil
+}
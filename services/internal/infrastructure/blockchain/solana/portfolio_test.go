@@ -0,0 +1,144 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func TestPortfolio_BalancesWithMetadata(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	bob := c.CreateAccount()
+	for _, pub := range []string{alice.PublicKey, bob.PublicKey} {
+		if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: pub, Lamports: 2 * lamportsPerSOL}); err != nil {
+			t.Fatalf("airdrop failed: %v", err)
+		}
+	}
+
+	coin, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 6})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	if _, _, err := c.CreateTokenMetadata(ctx, models.CreateTokenMetadataRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, alice), Mint: coin, Name: "Coin", Symbol: "CN", URI: "https://example.com/coin.json",
+	}); err != nil {
+		t.Fatalf("create metadata failed: %v", err)
+	}
+	ata, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: alice.PublicKey, Mint: coin})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, alice), Mint: coin, DestinationATA: ata, Amount: 1_500_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	// bob hands his account of the same mint over to alice, which leaves her a second, non-ATA account
+	handed, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, bob), Owner: bob.PublicKey, Mint: coin})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, alice), Mint: coin, DestinationATA: handed, Amount: 250_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+	if _, err := c.SetAuthority(ctx, models.SetAuthorityRequest{
+		SendOptions: confirmed, CurrentAuthority: mustSigner(t, bob), Account: handed, AuthorityType: models.AuthorityAccountOwner, NewAuthority: alice.PublicKey,
+	}); err != nil {
+		t.Fatalf("set owner failed: %v", err)
+	}
+
+	// an empty Token-2022 account without metadata
+	points, _, err := c.CreateMint(ctx, models.CreateMintRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 2, TokenProgram: common.Token2022ProgramID.ToBase58(),
+	})
+	if err != nil {
+		t.Fatalf("create token-2022 mint failed: %v", err)
+	}
+	empty, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: alice.PublicKey, Mint: points})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+
+	// a spam token whose metadata account does not decode
+	spam, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 3})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	spamATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: alice.PublicKey, Mint: spam})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, alice), Mint: spam, DestinationATA: spamATA, Amount: 7_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+	spamMetadata, _, err := common.FindProgramAddress([][]byte{[]byte("metadata"), solanatest.TokenMetadataProgramID.Bytes(), common.PublicKeyFromString(spam).Bytes()}, solanatest.TokenMetadataProgramID)
+	if err != nil {
+		t.Fatalf("derive metadata address failed: %v", err)
+	}
+	srv.SetAccount(spamMetadata.ToBase58(), solanatest.Account{Lamports: solanatest.RentExemptMinimum(4), Owner: solanatest.TokenMetadataProgramID, Data: []byte{4, 0, 0, 0}})
+
+	// a token whose mint no longer decodes, so its decimals are unknown
+	broken, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 4})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	brokenATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: alice.PublicKey, Mint: broken})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, alice), Mint: broken, DestinationATA: brokenATA, Amount: 7_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+	srv.SetAccount(broken, solanatest.Account{Lamports: solanatest.RentExemptMinimum(3), Owner: common.TokenProgramID, Data: []byte{1, 2, 3}})
+
+	portfolio, err := c.GetPortfolio(ctx, models.GetPortfolioRequest{Owner: alice.PublicKey})
+	if err != nil {
+		t.Fatalf("get portfolio failed: %v", err)
+	}
+	balance, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: alice.PublicKey})
+	if err != nil {
+		t.Fatalf("get balance failed: %v", err)
+	}
+	if portfolio.Owner != alice.PublicKey || portfolio.SOL.Raw != balance || portfolio.SOL.Decimals != 9 {
+		t.Fatalf("unexpected SOL balance %+v, want %d", portfolio.SOL, balance)
+	}
+	if len(portfolio.Tokens) != 5 {
+		t.Fatalf("expected 5 token accounts, got %+v", portfolio.Tokens)
+	}
+	tokens := make(map[string]models.PortfolioToken)
+	for _, token := range portfolio.Tokens {
+		tokens[token.Account] = token
+	}
+
+	if got := tokens[ata]; got.Mint != coin || got.UnknownMint || got.NonATA || got.ZeroBalance || got.Amount.Value.String() != "1.5" ||
+		got.Metadata == nil || got.Metadata.Name != "Coin" || got.Metadata.Symbol != "CN" || got.Metadata.Decimals != 6 {
+		t.Fatalf("unexpected ata entry: %+v", got)
+	}
+	if got := tokens[handed]; got.Mint != coin || !got.NonATA || got.Amount.Value.String() != "0.25" || got.Amount.Decimals != 6 || got.Metadata == nil {
+		t.Fatalf("unexpected non-ata entry: %+v", got)
+	}
+	if got := tokens[empty]; got.Mint != points || got.TokenProgram != common.Token2022ProgramID.ToBase58() || got.NonATA ||
+		!got.ZeroBalance || got.Amount.Decimals != 2 || !got.Amount.Value.IsZero() || got.Metadata != nil {
+		t.Fatalf("unexpected empty entry: %+v", got)
+	}
+
+	if got := tokens[spamATA]; got.Mint != spam || got.Metadata != nil || got.Amount.Value.String() != "7" || got.UnknownMint || got.NonATA || got.ZeroBalance {
+		t.Fatalf("unexpected spam entry: %+v", got)
+	}
+	if got := tokens[brokenATA]; got.Mint != broken || !got.UnknownMint || !got.Amount.Unscaled || got.Amount.Raw != 7_000 || !got.Amount.Value.IsZero() || got.Metadata != nil {
+		t.Fatalf("unexpected entry of an unknown mint: %+v", got)
+	}
+
+	if none, err := c.GetPortfolio(ctx, models.GetPortfolioRequest{Owner: c.CreateAccount().PublicKey}); err != nil || none.SOL.Raw != 0 || len(none.Tokens) != 0 {
+		t.Fatalf("expected an empty portfolio, got %+v: %v", none, err)
+	}
+}
@@ -0,0 +1,80 @@
+package signer
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// hardenedOffset marks a hardened child index; SLIP-0010 ed25519 only derives hardened children
+const hardenedOffset uint32 = 1 << 31
+
+// SolanaPath returns the derivation path of account n as used by Solana wallets and the CLI
+func SolanaPath(account uint32) string {
+	return fmt.Sprintf("m/44'/501'/%d'/0'", account)
+}
+
+// DeriveKeypair derives the ed25519 keypair at a hardened path such as m/44'/501'/0'/0' from a
+// BIP39 seed, following SLIP-0010
+func DeriveKeypair(seed []byte, path string) (*Keypair, error) {
+	indexes, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	key, chain := slip10Master(seed)
+	for _, i := range indexes {
+		key, chain = slip10Child(key, chain, i)
+	}
+	return KeypairFromSeed(key)
+}
+
+// KeypairFromMnemonic derives the keypair at path from a BIP39 mnemonic and passphrase
+func KeypairFromMnemonic(mnemonic, passphrase, path string) (*Keypair, error) {
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return DeriveKeypair(seed, path)
+}
+
+func slip10Master(seed []byte) (key, chain []byte) {
+	mac := hmac.New(sha512.New, []byte("ed25519 seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+func slip10Child(key, chain []byte, index uint32) ([]byte, []byte) {
+	data := make([]byte, 0, 37)
+	data = append(data, 0)
+	data = append(data, key...)
+	data = binary.BigEndian.AppendUint32(data, index)
+	mac := hmac.New(sha512.New, chain)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+// parsePath accepts ' or h as the hardened marker and rejects non-hardened segments
+func parsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q: must start with m", path)
+	}
+	indexes := make([]uint32, 0, len(segments)-1)
+	for _, seg := range segments[1:] {
+		trimmed := strings.TrimRight(seg, "'hH")
+		if len(seg)-len(trimmed) != 1 {
+			return nil, fmt.Errorf("invalid derivation path %q: ed25519 only supports hardened segments", path)
+		}
+		n, err := strconv.ParseUint(trimmed, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q: %w", path, err)
+		}
+		indexes = append(indexes, uint32(n)+hardenedOffset)
+	}
+	return indexes, nil
+}
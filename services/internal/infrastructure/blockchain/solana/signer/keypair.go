@@ -0,0 +1,83 @@
+// Package signer provides models.Signer implementations: in-memory keypairs,
+// an encrypted file keystore, a remote signer over HTTP and watch-only keys
+// for building transactions that are signed elsewhere. Keypairs can also be
+// derived from BIP39 mnemonics along SLIP-0010 paths, or loaded from and
+// saved to Solana CLI keypair files.
+package signer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/mr-tron/base58"
+)
+
+// Keypair is an in-memory ed25519 signer. Its String form only exposes the public key.
+type Keypair struct {
+	key ed25519.PrivateKey
+	pub string
+}
+
+// NewKeypair generates a random keypair
+func NewKeypair() *Keypair {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return newKeypair(key)
+}
+
+// KeypairFromBytes loads a 64-byte secret key (seed followed by public key)
+func KeypairFromBytes(secret []byte) (*Keypair, error) {
+	if len(secret) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key length: expected 64 bytes")
+	}
+	key := ed25519.NewKeyFromSeed(secret[:ed25519.SeedSize])
+	if !bytes.Equal(key[ed25519.SeedSize:], secret[ed25519.SeedSize:]) {
+		return nil, fmt.Errorf("private key does not match its public key")
+	}
+	return newKeypair(key), nil
+}
+
+// KeypairFromBase58 loads a base58 encoded 64-byte secret key
+func KeypairFromBase58(secret string) (*Keypair, error) {
+	b, err := base58.Decode(secret)
+	if err != nil {
+		return nil, err
+	}
+	return KeypairFromBytes(b)
+}
+
+// KeypairFromSeed derives a keypair from a 32-byte ed25519 seed
+func KeypairFromSeed(seed []byte) (*Keypair, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid seed length: expected 32 bytes")
+	}
+	return newKeypair(ed25519.NewKeyFromSeed(seed)), nil
+}
+
+func newKeypair(key ed25519.PrivateKey) *Keypair {
+	return &Keypair{key: key, pub: base58.Encode(key[ed25519.SeedSize:])}
+}
+
+// PublicKey returns the base58 public key
+func (k *Keypair) PublicKey() string {
+	return k.pub
+}
+
+// Sign signs message with the private key
+func (k *Keypair) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(k.key, message), nil
+}
+
+// String keeps the secret out of logs and formatted errors
+func (k *Keypair) String() string {
+	return "Keypair(" + k.pub + ")"
+}
+
+// GoString keeps the secret out of %#v output
+func (k *Keypair) GoString() string {
+	return k.String()
+}
@@ -0,0 +1,58 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+
+	json "github.com/goccy/go-json"
+)
+
+// KeypairFromJSON loads a Solana CLI keypair: a JSON array of the 64 secret key bytes
+func KeypairFromJSON(data []byte) (*Keypair, error) {
+	var secret []byte
+	var ints []int
+	if err := json.Unmarshal(data, &ints); err != nil {
+		return nil, fmt.Errorf("invalid keypair file: %w", err)
+	}
+	for _, v := range ints {
+		if v < 0 || v > 255 {
+			return nil, fmt.Errorf("invalid keypair file: byte out of range")
+		}
+		secret = append(secret, byte(v))
+	}
+	return KeypairFromBytes(secret)
+}
+
+// ReadKeypairFile loads a keypair file written by solana-keygen
+func ReadKeypairFile(path string) (*Keypair, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return KeypairFromJSON(data)
+}
+
+// MarshalKeypairJSON encodes the keypair in the Solana CLI format. The output is the
+// unencrypted secret key.
+func MarshalKeypairJSON(k *Keypair) ([]byte, error) {
+	ints := make([]int, ed25519.PrivateKeySize)
+	for i, b := range k.key {
+		ints[i] = int(b)
+	}
+	return json.Marshal(ints)
+}
+
+// WriteKeypairFile writes the keypair in the Solana CLI format with owner-only permissions
+func WriteKeypairFile(path string, k *Keypair) error {
+	data, err := MarshalKeypairJSON(k)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// SecretKey returns a copy of the 64-byte secret key (seed followed by public key)
+func (k *Keypair) SecretKey() []byte {
+	return append([]byte(nil), k.key...)
+}
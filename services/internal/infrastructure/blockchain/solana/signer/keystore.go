@@ -0,0 +1,122 @@
+package signer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	json "github.com/goccy/go-json"
+)
+
+const (
+	keystoreVersion    = 1
+	keystoreKDF        = "pbkdf2-sha256"
+	keystoreIterations = 210_000
+	keystoreSaltSize   = 16
+	keystoreKeySize    = 32
+)
+
+// keystoreFile is the on-disk format of an encrypted keypair
+type keystoreFile struct {
+	Version    int    `json:"version"`
+	PublicKey  string `json:"publicKey"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// WriteKeystore encrypts the keypair with a passphrase (PBKDF2-SHA256, AES-256-GCM)
+// and writes it to path with owner-only permissions
+func WriteKeystore(path string, k *Keypair, passphrase string) error {
+	salt := make([]byte, keystoreSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := keystoreCipher(passphrase, salt, keystoreIterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	ks := keystoreFile{
+		Version:    keystoreVersion,
+		PublicKey:  k.pub,
+		KDF:        keystoreKDF,
+		Iterations: keystoreIterations,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, k.key, []byte(k.pub))),
+	}
+	data, err := json.MarshalIndent(ks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// LoadKeystore decrypts a keystore written by WriteKeystore
+func LoadKeystore(path string, passphrase string) (*Keypair, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ks keystoreFile
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("invalid keystore file: %w", err)
+	}
+	if ks.Version != keystoreVersion || ks.KDF != keystoreKDF || ks.Iterations <= 0 {
+		return nil, fmt.Errorf("unsupported keystore format")
+	}
+	salt, err := base64.StdEncoding.DecodeString(ks.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore salt: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(ks.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(ks.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore ciphertext: %w", err)
+	}
+	gcm, err := keystoreCipher(passphrase, salt, ks.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid keystore nonce length")
+	}
+	secret, err := gcm.Open(nil, nonce, ciphertext, []byte(ks.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("keystore decryption failed: wrong passphrase or corrupted file")
+	}
+	k, err := KeypairFromBytes(secret)
+	if err != nil {
+		return nil, err
+	}
+	if k.pub != ks.PublicKey {
+		return nil, fmt.Errorf("keystore public key mismatch")
+	}
+	return k, nil
+}
+
+func keystoreCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, keystoreKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
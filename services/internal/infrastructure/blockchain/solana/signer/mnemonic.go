@@ -0,0 +1,120 @@
+package signer
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	mnemonicSeedIterations = 2048
+	mnemonicSeedSize       = 64
+	bitsPerWord            = 11
+)
+
+// ErrInvalidMnemonic is returned for phrases with unknown words, a wrong length or a bad checksum
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+//go:embed bip39_english.txt
+var englishWordlist string
+
+var (
+	wordsOnce sync.Once
+	wordList  []string
+	wordIndex map[string]int
+)
+
+func words() ([]string, map[string]int) {
+	wordsOnce.Do(func() {
+		wordList = strings.Fields(englishWordlist)
+		wordIndex = make(map[string]int, len(wordList))
+		for i, w := range wordList {
+			wordIndex[w] = i
+		}
+	})
+	return wordList, wordIndex
+}
+
+// NewMnemonic generates a BIP39 English mnemonic of 12, 15, 18, 21 or 24 words
+func NewMnemonic(wordCount int) (string, error) {
+	if wordCount < 12 || wordCount > 24 || wordCount%3 != 0 {
+		return "", fmt.Errorf("unsupported mnemonic length %d: expected 12, 15, 18, 21 or 24 words", wordCount)
+	}
+	entropy := make([]byte, wordCount*bitsPerWord*32/33/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+	return MnemonicFromEntropy(entropy)
+}
+
+// MnemonicFromEntropy encodes 16 to 32 bytes of entropy (a multiple of 4) as a BIP39 English mnemonic
+func MnemonicFromEntropy(entropy []byte) (string, error) {
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", fmt.Errorf("invalid entropy length %d: expected 16 to 32 bytes in steps of 4", len(entropy))
+	}
+	list, _ := words()
+	sum := sha256.Sum256(entropy)
+	// entropy followed by its checksum bits, read 11 bits at a time
+	bits := append(append([]byte(nil), entropy...), sum[0])
+	n := len(entropy) * 8 * 33 / 32 / bitsPerWord
+	out := make([]string, n)
+	for i := range out {
+		idx := 0
+		for b := i * bitsPerWord; b < (i+1)*bitsPerWord; b++ {
+			idx = idx<<1 | int(bits[b/8]>>(7-b%8)&1)
+		}
+		out[i] = list[idx]
+	}
+	return strings.Join(out, " "), nil
+}
+
+// ValidateMnemonic checks the words and checksum of a BIP39 English mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	_, err := mnemonicEntropy(mnemonic)
+	return err
+}
+
+func mnemonicEntropy(mnemonic string) ([]byte, error) {
+	fields := strings.Fields(mnemonic)
+	if len(fields) < 12 || len(fields) > 24 || len(fields)%3 != 0 {
+		return nil, fmt.Errorf("%w: %d words", ErrInvalidMnemonic, len(fields))
+	}
+	_, index := words()
+	bits := make([]byte, (len(fields)*bitsPerWord+7)/8)
+	for i, w := range fields {
+		idx, ok := index[w]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %d", ErrInvalidMnemonic, i+1)
+		}
+		for j := 0; j < bitsPerWord; j++ {
+			if idx>>(bitsPerWord-1-j)&1 == 1 {
+				b := i*bitsPerWord + j
+				bits[b/8] |= 1 << (7 - b%8)
+			}
+		}
+	}
+	entropyLen := len(fields) * bitsPerWord * 32 / 33 / 8
+	entropy := bits[:entropyLen]
+	checksumBits := entropyLen / 4
+	sum := sha256.Sum256(entropy)
+	if bits[entropyLen]>>(8-checksumBits) != sum[0]>>(8-checksumBits) {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidMnemonic)
+	}
+	return entropy, nil
+}
+
+// MnemonicToSeed validates a mnemonic and stretches it with the passphrase into the 64-byte
+// BIP39 seed. Non-ASCII passphrases must already be NFKD normalized.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if err := ValidateMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	normalized := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key(sha512.New, normalized, []byte("mnemonic"+passphrase), mnemonicSeedIterations, mnemonicSeedSize)
+}
@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	json "github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+)
+
+const defaultRemoteTimeout = 10 * time.Second
+
+// RemoteSignRequest is the body POSTed to a remote signer
+type RemoteSignRequest struct {
+	PublicKey string `json:"publicKey"`
+	// Message is the base64 encoded transaction message
+	Message string `json:"message"`
+}
+
+// RemoteSignResponse is the body a remote signer answers with
+type RemoteSignResponse struct {
+	// Signature is the base58 encoded ed25519 signature
+	Signature string `json:"signature"`
+}
+
+// Remote delegates signing to an HTTP service holding the key, e.g. an HSM or custody gateway.
+// Returned signatures are verified against the public key before use.
+type Remote struct {
+	endpoint string
+	pub      string
+	client   *http.Client
+
+	// Header is sent with every request, e.g. for authorization
+	Header http.Header
+}
+
+// NewRemote creates a signer for publicKey served at endpoint; a nil client uses a 10s timeout
+func NewRemote(endpoint, publicKey string, client *http.Client) (*Remote, error) {
+	b, err := base58.Decode(publicKey)
+	if err != nil || len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key: %s", publicKey)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: defaultRemoteTimeout}
+	}
+	return &Remote{endpoint: endpoint, pub: publicKey, client: client, Header: http.Header{}}, nil
+}
+
+// PublicKey returns the base58 public key
+func (r *Remote) PublicKey() string {
+	return r.pub
+}
+
+// Sign asks the remote service to sign message
+func (r *Remote) Sign(message []byte) ([]byte, error) {
+	return r.SignContext(context.Background(), message)
+}
+
+// SignContext asks the remote service to sign message, giving up once ctx is done
+func (r *Remote) SignContext(ctx context.Context, message []byte) ([]byte, error) {
+	body, err := json.Marshal(RemoteSignRequest{
+		PublicKey: r.pub,
+		Message:   base64.StdEncoding.EncodeToString(message),
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer returned status %d: %s", res.StatusCode, bytes.TrimSpace(data))
+	}
+
+	var out RemoteSignResponse
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("invalid remote signer response: %w", err)
+	}
+	sig, err := base58.Decode(out.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signature encoding: %w", err)
+	}
+	pub, _ := base58.Decode(r.pub)
+	if !ed25519.Verify(pub, message, sig) {
+		return nil, fmt.Errorf("remote signer returned an invalid signature for %s", r.pub)
+	}
+	return sig, nil
+}
+
+// String identifies the signer without its credentials
+func (r *Remote) String() string {
+	return "Remote(" + r.pub + ")"
+}
@@ -0,0 +1,187 @@
+package signer_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	json "github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
+)
+
+func TestKeypair_DoesNotLeakSecret(t *testing.T) {
+	kp := signer.NewKeypair()
+
+	for _, out := range []string{fmt.Sprint(kp), fmt.Sprintf("%+v", kp), fmt.Sprintf("%#v", kp)} {
+		if out != "Keypair("+kp.PublicKey()+")" {
+			t.Fatalf("unexpected formatted keypair: %q", out)
+		}
+	}
+}
+
+func TestKeystore_RoundTrip(t *testing.T) {
+	kp := signer.NewKeypair()
+	path := filepath.Join(t.TempDir(), "wallet.json")
+
+	if err := signer.WriteKeystore(path, kp, "correct horse"); err != nil {
+		t.Fatalf("write keystore failed: %v", err)
+	}
+
+	loaded, err := signer.LoadKeystore(path, "correct horse")
+	if err != nil {
+		t.Fatalf("load keystore failed: %v", err)
+	}
+	if loaded.PublicKey() != kp.PublicKey() {
+		t.Fatalf("public key mismatch: %s != %s", loaded.PublicKey(), kp.PublicKey())
+	}
+	msg := []byte("keystore message")
+	sig, _ := loaded.Sign(msg)
+	pub, _ := base58.Decode(kp.PublicKey())
+	if !ed25519.Verify(pub, msg, sig) {
+		t.Fatalf("loaded keypair produced an invalid signature")
+	}
+
+	if _, err := signer.LoadKeystore(path, "wrong"); err == nil {
+		t.Fatalf("expected error for wrong passphrase")
+	}
+}
+
+func TestRemote_SignsAndVerifies(t *testing.T) {
+	kp := signer.NewKeypair()
+	tamper := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req signer.RemoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PublicKey != kp.PublicKey() {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		msg, _ := base64.StdEncoding.DecodeString(req.Message)
+		if tamper {
+			msg = append(msg, 0)
+		}
+		sig, _ := kp.Sign(msg)
+		_ = json.NewEncoder(w).Encode(signer.RemoteSignResponse{Signature: base58.Encode(sig)})
+	}))
+	defer srv.Close()
+
+	remote, err := signer.NewRemote(srv.URL, kp.PublicKey(), nil)
+	if err != nil {
+		t.Fatalf("new remote failed: %v", err)
+	}
+
+	if _, err := remote.Sign([]byte("msg")); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+
+	remote.Header.Set("Authorization", "Bearer token")
+	msg := []byte("remote message")
+	sig, err := remote.Sign(msg)
+	if err != nil {
+		t.Fatalf("remote sign failed: %v", err)
+	}
+	pub, _ := base58.Decode(kp.PublicKey())
+	if !ed25519.Verify(pub, msg, sig) {
+		t.Fatalf("remote signature does not verify")
+	}
+
+	tamper = true
+	if _, err := remote.Sign(msg); err == nil {
+		t.Fatalf("expected error for a signature over a different message")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := remote.SignContext(ctx, msg); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a cancelled context to stop signing, got %v", err)
+	}
+}
+
+func TestWatchOnly_CannotSign(t *testing.T) {
+	kp := signer.NewKeypair()
+	w := signer.NewWatchOnly(kp.PublicKey())
+	if w.PublicKey() != kp.PublicKey() {
+		t.Fatalf("unexpected public key: %s", w.PublicKey())
+	}
+	if _, err := w.Sign([]byte("message")); !errors.Is(err, signer.ErrWatchOnly) {
+		t.Fatalf("expected ErrWatchOnly, got %v", err)
+	}
+}
+
+func TestMnemonic_Vectors(t *testing.T) {
+	// BIP39 reference vector for all-zero entropy with passphrase TREZOR
+	mnemonic, err := signer.MnemonicFromEntropy(make([]byte, 16))
+	if err != nil {
+		t.Fatalf("mnemonic from entropy failed: %v", err)
+	}
+	if want := strings.Repeat("abandon ", 11) + "about"; mnemonic != want {
+		t.Fatalf("unexpected mnemonic: %q", mnemonic)
+	}
+	seed, err := signer.MnemonicToSeed(mnemonic, "TREZOR")
+	if err != nil {
+		t.Fatalf("mnemonic to seed failed: %v", err)
+	}
+	if got := hex.EncodeToString(seed); got != "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04" {
+		t.Fatalf("unexpected seed: %s", got)
+	}
+
+	generated, err := signer.NewMnemonic(24)
+	if err != nil {
+		t.Fatalf("new mnemonic failed: %v", err)
+	}
+	if err := signer.ValidateMnemonic(generated); err != nil {
+		t.Fatalf("generated mnemonic is invalid: %v", err)
+	}
+	if err := signer.ValidateMnemonic(strings.Repeat("abandon ", 12)); !errors.Is(err, signer.ErrInvalidMnemonic) {
+		t.Fatalf("expected a checksum error, got %v", err)
+	}
+}
+
+func TestDeriveKeypair_SLIP10Vectors(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	for path, want := range map[string]string{
+		"m":          "a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed",
+		"m/0'":       "8c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c",
+		"m/0'/1'":    "1932a5270f335bed617d5b935c80aedb1a35bd9fc1e31acafd5372c30f5c1187",
+		"m/0h/1h/2h": "ae98736566d30ed0e9d2f4486a64bc95740d89c7db33f52121f8ea8f76ff0fc1",
+	} {
+		kp, err := signer.DeriveKeypair(seed, path)
+		if err != nil {
+			t.Fatalf("derive %s failed: %v", path, err)
+		}
+		pub, _ := base58.Decode(kp.PublicKey())
+		if got := hex.EncodeToString(pub); got != want {
+			t.Fatalf("unexpected public key at %s: %s", path, got)
+		}
+	}
+	if _, err := signer.DeriveKeypair(seed, "m/44'/501'/0"); err == nil {
+		t.Fatal("expected an error for a non-hardened segment")
+	}
+}
+
+func TestKeypairFile_RoundTrip(t *testing.T) {
+	kp := signer.NewKeypair()
+	path := filepath.Join(t.TempDir(), "id.json")
+	if err := signer.WriteKeypairFile(path, kp); err != nil {
+		t.Fatalf("write keypair file failed: %v", err)
+	}
+	loaded, err := signer.ReadKeypairFile(path)
+	if err != nil {
+		t.Fatalf("read keypair file failed: %v", err)
+	}
+	if loaded.PublicKey() != kp.PublicKey() {
+		t.Fatalf("public key mismatch: %s != %s", loaded.PublicKey(), kp.PublicKey())
+	}
+}
@@ -0,0 +1,30 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWatchOnly is returned when a watch-only signer is asked to sign
+var ErrWatchOnly = errors.New("watch-only signer cannot sign")
+
+// WatchOnly names an account by public key without holding its secret. Pass it to the
+// client's Build* methods when the key lives in a wallet app, hardware device or with another party.
+type WatchOnly struct {
+	pub string
+}
+
+// NewWatchOnly returns a signer that only knows the base58 public key
+func NewWatchOnly(publicKey string) WatchOnly {
+	return WatchOnly{pub: publicKey}
+}
+
+// PublicKey returns the base58 public key
+func (w WatchOnly) PublicKey() string {
+	return w.pub
+}
+
+// Sign always fails with ErrWatchOnly
+func (w WatchOnly) Sign(message []byte) ([]byte, error) {
+	return nil, fmt.Errorf("%w: %s", ErrWatchOnly, w.pub)
+}
@@ -0,0 +1,208 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	"github.com/shopspring/decimal"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// Every write method dry-runs instead of sending when SendOptions.Simulate is set. The
+// transaction is compiled as it would be sent, but left unsigned, so a simulation never asks a
+// signer for a signature.
+
+// Simulate dry-runs a prepared transaction; signatures are not verified, so it may still miss some
+func (c *Client) Simulate(ctx context.Context, req models.SimulateRequest) (*models.Simulation, error) {
+	tx, err := decodePrepared(req.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	return c.simulate(ctx, tx)
+}
+
+// simulateInto runs a simulation for a write request and stores it where opts asks
+func (c *Client) simulateInto(ctx context.Context, opts models.SendOptions, tx types.Transaction) error {
+	sim, err := c.simulate(ctx, tx)
+	if err != nil {
+		return err
+	}
+	*opts.Simulate = *sim
+	return nil
+}
+
+func (c *Client) simulate(ctx context.Context, tx types.Transaction) (*models.Simulation, error) {
+	keys, err := c.messageAccountKeys(ctx, tx.Message)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, len(keys))
+	for i, key := range keys {
+		addresses[i] = key.ToBase58()
+	}
+
+	pre, err := c.c.GetMultipleAccountsWithConfig(ctx, addresses, client.GetMultipleAccountsConfig{Commitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	res, err := c.c.SimulateTransactionWithConfig(ctx, tx, client.SimulateTransactionConfig{
+		Commitment: rpc.CommitmentConfirmed,
+		Addresses:  addresses,
+	})
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	fee, err := c.c.GetFeeForMessageWithConfig(ctx, tx.Message, client.GetFeeForMessageConfig{Commitment: rpc.CommitmentConfirmed})
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	sim := &models.Simulation{
+		Err:   res.Err,
+		Error: decodeTransactionError(res.Err, tx.Message.Instructions, keys, res.Logs),
+		Logs:  res.Logs,
+	}
+	if res.UnitConsumed != nil {
+		sim.UnitsConsumed = *res.UnitConsumed
+	}
+	if fee != nil {
+		sim.Fee = *fee
+	}
+	// the cluster only returns the resulting accounts of a successful simulation
+	if res.Err != nil || len(res.Accounts) != len(keys) || len(pre) != len(keys) {
+		return sim, nil
+	}
+
+	post := make([]client.AccountInfo, len(keys))
+	for i, acc := range res.Accounts {
+		if acc != nil {
+			post[i] = *acc
+		}
+	}
+	for i, addr := range addresses {
+		if pre[i].Lamports == post[i].Lamports {
+			continue
+		}
+		sim.BalanceChanges = append(sim.BalanceChanges, models.BalanceChange{
+			Account: addr,
+			Pre:     pre[i].Lamports,
+			Post:    post[i].Lamports,
+			Change:  lamportChange(pre[i].Lamports, post[i].Lamports, solDecimals),
+		})
+	}
+	for i, addr := range addresses {
+		change, ok, err := c.tokenBalanceChange(ctx, addr, pre[i], post[i], addresses, post)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			sim.TokenBalanceChanges = append(sim.TokenBalanceChanges, change)
+		}
+	}
+	return sim, nil
+}
+
+// tokenBalanceChange compares a token account before and after a simulation. Decimals come from
+// the mint as it is after the simulation, so mints created by the transaction are covered.
+func (c *Client) tokenBalanceChange(ctx context.Context, address string, pre, post client.AccountInfo, addresses []string, accounts []client.AccountInfo) (models.TokenBalanceChange, bool, error) {
+	preState, preOK := tokenAccountState(pre)
+	postState, postOK := tokenAccountState(post)
+	if !preOK && !postOK {
+		return models.TokenBalanceChange{}, false, nil
+	}
+	state := postState
+	if !postOK {
+		state = preState
+	}
+	if preState.amount == postState.amount {
+		return models.TokenBalanceChange{}, false, nil
+	}
+
+	var decimals uint8
+	found := false
+	for i, addr := range addresses {
+		if addr == state.mint && isTokenProgram(accounts[i].Owner) {
+			mint, err := parseMint(addr, accounts[i].Owner, accounts[i].Data)
+			if err != nil {
+				return models.TokenBalanceChange{}, false, err
+			}
+			decimals, found = mint.Decimals, true
+		}
+	}
+	if !found {
+		mint, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: state.mint})
+		if err != nil {
+			return models.TokenBalanceChange{}, false, err
+		}
+		decimals = mint.Decimals
+	}
+	return models.TokenBalanceChange{
+		Account:  address,
+		Owner:    state.owner,
+		Mint:     state.mint,
+		Decimals: decimals,
+		Pre:      preState.amount,
+		Post:     postState.amount,
+		Change:   lamportChange(preState.amount, postState.amount, decimals),
+	}, true, nil
+}
+
+type tokenAccountFields struct {
+	mint   string
+	owner  string
+	amount uint64
+}
+
+// tokenAccountState reads a token account; mints and other accounts are rejected
+func tokenAccountState(acc client.AccountInfo) (tokenAccountFields, bool) {
+	data := acc.Data
+	if !isTokenProgram(acc.Owner) || len(data) < tokenAccountSize ||
+		(len(data) > tokenAccountSize && data[accountTypeOffset] != 2) {
+		return tokenAccountFields{}, false
+	}
+	return tokenAccountFields{
+		mint:   base58.Encode(data[0:32]),
+		owner:  base58.Encode(data[32:64]),
+		amount: binary.LittleEndian.Uint64(data[64:72]),
+	}, true
+}
+
+// lamportChange is post minus pre scaled by decimals
+func lamportChange(pre, post uint64, decimals uint8) decimal.Decimal {
+	d := decimal.NewFromUint64(post).Sub(decimal.NewFromUint64(pre))
+	return d.Shift(-int32(decimals))
+}
+
+// messageAccountKeys resolves the accounts a message refers to, including the addresses a v0
+// message loads from lookup tables
+func (c *Client) messageAccountKeys(ctx context.Context, msg types.Message) ([]common.PublicKey, error) {
+	keys := append([]common.PublicKey(nil), msg.Accounts...)
+	if len(msg.AddressLookupTables) == 0 {
+		return keys, nil
+	}
+	var writable, readonly []common.PublicKey
+	for _, lookup := range msg.AddressLookupTables {
+		table, err := c.GetLookupTable(ctx, models.GetLookupTableRequest{Address: lookup.AccountKey.ToBase58()})
+		if err != nil {
+			return nil, err
+		}
+		for _, list := range []struct {
+			indexes []uint8
+			keys    *[]common.PublicKey
+		}{{lookup.WritableIndexes, &writable}, {lookup.ReadonlyIndexes, &readonly}} {
+			for _, idx := range list.indexes {
+				if int(idx) >= len(table.Addresses) {
+					return nil, fmt.Errorf("lookup table %s has no address at index %d", table.Address, idx)
+				}
+				*list.keys = append(*list.keys, common.PublicKeyFromString(table.Addresses[idx]))
+			}
+		}
+	}
+	return append(append(keys, writable...), readonly...), nil
+}
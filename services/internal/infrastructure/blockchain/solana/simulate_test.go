@@ -0,0 +1,133 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func TestSimulate_TransferReportsBalanceChangesWithoutSending(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	bob := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	var sim models.Simulation
+	sig, err := c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: models.SendOptions{Simulate: &sim}, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 30_000_000,
+	})
+	if err != nil {
+		t.Fatalf("simulate failed: %v", err)
+	}
+	if sig != "" {
+		t.Fatalf("a simulation returned signature %s", sig)
+	}
+	if sim.Error != nil || sim.UnitsConsumed == 0 || len(sim.Logs) == 0 || sim.Fee != solanatest.LamportsPerSignature {
+		t.Fatalf("unexpected simulation: %+v", sim)
+	}
+	changes := map[string]models.BalanceChange{}
+	for _, ch := range sim.BalanceChanges {
+		changes[ch.Account] = ch
+	}
+	if len(changes) != 2 || changes[alice.PublicKey].Change.String() != "-0.030005" || changes[bob.PublicKey].Pre != 0 ||
+		changes[bob.PublicKey].Post != 30_000_000 || changes[bob.PublicKey].Change.String() != "0.03" {
+		t.Fatalf("unexpected balance changes: %+v", sim.BalanceChanges)
+	}
+
+	assertBalance(t, ctx, c, alice.PublicKey, 100_000_000)
+	assertBalance(t, ctx, c, bob.PublicKey, 0)
+
+	// more lamports than alice has fail in the System program
+	sim = models.Simulation{}
+	if _, err := c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: models.SendOptions{Simulate: &sim}, From: mustSigner(t, alice), ToPublicKey: bob.PublicKey, Lamports: 200_000_000,
+	}); err != nil {
+		t.Fatalf("simulate failed: %v", err)
+	}
+	if sim.Error == nil || sim.Error.Name != "ResultWithNegativeLamports" || sim.Error.Code == nil || *sim.Error.Code != 1 ||
+		len(sim.BalanceChanges) != 0 {
+		t.Fatalf("expected insufficient lamports, got %+v (%v)", sim, sim.Error)
+	}
+}
+
+func TestSimulate_TokenTransferDecodesErrorsAndTokenChanges(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	bob := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 200_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, alice), MintAuthority: alice.PublicKey, Decimals: 6})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	aliceATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: alice.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	bobATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, alice), Owner: bob.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, alice), Mint: mint, DestinationATA: aliceATA, Amount: 5_000_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	var sim models.Simulation
+	if _, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: models.SendOptions{Simulate: &sim}, Authority: mustSigner(t, alice), SourceATA: aliceATA, DestinationATA: bobATA, Mint: mint, Amount: 1_250_000, Decimals: 6,
+	}); err != nil {
+		t.Fatalf("simulate failed: %v", err)
+	}
+	if sim.Error != nil || len(sim.TokenBalanceChanges) != 2 {
+		t.Fatalf("unexpected simulation: %+v", sim)
+	}
+	for _, ch := range sim.TokenBalanceChanges {
+		switch ch.Account {
+		case aliceATA:
+			if ch.Owner != alice.PublicKey || ch.Mint != mint || ch.Decimals != 6 || ch.Change.String() != "-1.25" {
+				t.Fatalf("unexpected source change: %+v", ch)
+			}
+		case bobATA:
+			if ch.Owner != bob.PublicKey || ch.Pre != 0 || ch.Post != 1_250_000 || ch.Change.String() != "1.25" {
+				t.Fatalf("unexpected destination change: %+v", ch)
+			}
+		default:
+			t.Fatalf("unexpected token account %s", ch.Account)
+		}
+	}
+
+	sim = models.Simulation{}
+	if _, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: models.SendOptions{Simulate: &sim}, Authority: mustSigner(t, alice), SourceATA: aliceATA, DestinationATA: bobATA, Mint: mint, Amount: 6_000_000, Decimals: 6,
+	}); err != nil {
+		t.Fatalf("simulate failed: %v", err)
+	}
+	if sim.Error == nil || sim.Error.Name != "InsufficientFunds" || sim.Error.Code == nil || *sim.Error.Code != 1 ||
+		sim.Error.Instruction < 0 || len(sim.TokenBalanceChanges) != 0 {
+		t.Fatalf("expected insufficient token funds, got %+v (%v)", sim, sim.Error)
+	}
+
+	bal, err := c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: bobATA})
+	if err != nil {
+		t.Fatalf("get token account failed: %v", err)
+	}
+	if bal.Amount != 0 {
+		t.Fatalf("simulation moved tokens: %+v", bal)
+	}
+}
@@ -0,0 +1,273 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/shopspring/decimal"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+const solanaPayScheme = "solana"
+
+// solanaPayAmount is a non-negative decimal without exponent, sign or superfluous leading zeros
+var solanaPayAmount = regexp.MustCompile(`^(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+// EncodeSolanaPayURL renders a Solana Pay transfer request as a solana: URL, for a QR code or link
+func (c *Client) EncodeSolanaPayURL(transfer models.SolanaPayTransfer) (string, error) {
+	if err := validateSolanaPayTransfer(transfer); err != nil {
+		return "", err
+	}
+	var params []string
+	add := func(key, value string) {
+		// wallets decode with URLSearchParams; %20 is safe wherever + may not be
+		params = append(params, key+"="+strings.ReplaceAll(url.QueryEscape(value), "+", "%20"))
+	}
+	if transfer.Amount != nil {
+		add("amount", transfer.Amount.String())
+	}
+	if transfer.SPLToken != "" {
+		add("spl-token", transfer.SPLToken)
+	}
+	for _, ref := range transfer.References {
+		add("reference", ref)
+	}
+	if transfer.Label != "" {
+		add("label", transfer.Label)
+	}
+	if transfer.Message != "" {
+		add("message", transfer.Message)
+	}
+	if transfer.Memo != "" {
+		add("memo", transfer.Memo)
+	}
+	u := solanaPayScheme + ":" + transfer.Recipient
+	if len(params) > 0 {
+		u += "?" + strings.Join(params, "&")
+	}
+	return u, nil
+}
+
+// ParseSolanaPayURL decodes a solana: transfer request URL. Transaction requests, whose
+// link is an https URL, are not supported.
+func (c *Client) ParseSolanaPayURL(req models.ParseSolanaPayURLRequest) (*models.SolanaPayTransfer, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSolanaPayURL, err)
+	}
+	if u.Scheme != solanaPayScheme {
+		return nil, fmt.Errorf("%w: scheme %q is not %s", ErrInvalidSolanaPayURL, u.Scheme, solanaPayScheme)
+	}
+	if u.Opaque == "" || strings.Contains(u.Opaque, ":") || u.Fragment != "" {
+		return nil, fmt.Errorf("%w: not a transfer request", ErrInvalidSolanaPayURL)
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSolanaPayURL, err)
+	}
+
+	transfer := &models.SolanaPayTransfer{Recipient: u.Opaque, References: query["reference"]}
+	single := func(key string) (string, error) {
+		values := query[key]
+		if len(values) > 1 {
+			return "", fmt.Errorf("%w: %s is given %d times", ErrInvalidSolanaPayURL, key, len(values))
+		}
+		if len(values) == 0 {
+			return "", nil
+		}
+		if values[0] == "" {
+			return "", fmt.Errorf("%w: %s is empty", ErrInvalidSolanaPayURL, key)
+		}
+		return values[0], nil
+	}
+	amount, err := single("amount")
+	if err != nil {
+		return nil, err
+	}
+	if amount != "" {
+		if !solanaPayAmount.MatchString(amount) {
+			return nil, fmt.Errorf("%w: amount %q is not a non-negative decimal", ErrInvalidSolanaPayURL, amount)
+		}
+		value, err := decimal.NewFromString(amount)
+		if err != nil {
+			return nil, fmt.Errorf("%w: amount %q: %v", ErrInvalidSolanaPayURL, amount, err)
+		}
+		transfer.Amount = &value
+	}
+	for key, field := range map[string]*string{"spl-token": &transfer.SPLToken, "label": &transfer.Label, "message": &transfer.Message, "memo": &transfer.Memo} {
+		if *field, err = single(key); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateSolanaPayTransfer(*transfer); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+// validateSolanaPayTransfer checks the keys and amount of a transfer request. Token amounts
+// are checked against the mint decimals only when a payment is verified.
+func validateSolanaPayTransfer(transfer models.SolanaPayTransfer) error {
+	if _, err := publicKey(transfer.Recipient); err != nil {
+		return fmt.Errorf("%w: recipient: %v", ErrInvalidSolanaPayURL, err)
+	}
+	if transfer.SPLToken != "" {
+		if _, err := publicKey(transfer.SPLToken); err != nil {
+			return fmt.Errorf("%w: spl-token: %v", ErrInvalidSolanaPayURL, err)
+		}
+	}
+	for i, ref := range transfer.References {
+		if _, err := publicKey(ref); err != nil {
+			return fmt.Errorf("%w: reference: %v", ErrInvalidSolanaPayURL, err)
+		}
+		if slices.Contains(transfer.References[:i], ref) {
+			return fmt.Errorf("%w: reference %s is given twice", ErrInvalidSolanaPayURL, ref)
+		}
+	}
+	if a := transfer.Amount; a != nil {
+		if a.IsNegative() {
+			return fmt.Errorf("%w: amount %s is negative", ErrInvalidSolanaPayURL, a)
+		}
+		if transfer.SPLToken == "" && -a.Exponent() > solDecimals && !a.Equal(a.Truncate(solDecimals)) {
+			return fmt.Errorf("%w: amount %s has more than %d decimals", ErrInvalidSolanaPayURL, a, solDecimals)
+		}
+	}
+	return nil
+}
+
+// VerifySolanaPayTransfer finds the oldest successful transaction carrying the first reference
+// of a transfer request that pays the request: the recipient, or for tokens its associated token
+// account, receives exactly the amount, every reference is on the transfer and the memo matches.
+// The reference is public, so transactions that carry it without paying are skipped.
+// ErrPaymentNotFound means the payer has not paid yet.
+func (c *Client) VerifySolanaPayTransfer(ctx context.Context, req models.VerifySolanaPayTransferRequest) (*models.SolanaPayment, error) {
+	transfer := req.Transfer
+	if err := validateSolanaPayTransfer(transfer); err != nil {
+		return nil, err
+	}
+	if transfer.Amount == nil || len(transfer.References) == 0 {
+		return nil, fmt.Errorf("verifying a payment needs the amount and a reference of the request")
+	}
+	pays, err := c.solanaPayMatcher(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	// signatures come newest first; page back to the oldest, since the first payment is the one that counts
+	var sigs []models.SignatureInfo
+	before := ""
+	for {
+		page, err := c.GetSignaturesForAddress(ctx, models.GetSignaturesForAddressRequest{Address: transfer.References[0], Before: before, Limit: maxSignaturesPage})
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, page...)
+		if len(page) < maxSignaturesPage {
+			break
+		}
+		before = page[len(page)-1].Signature
+	}
+
+	checked := 0
+	for i := len(sigs) - 1; i >= 0; i-- {
+		if sigs[i].Err != nil {
+			continue
+		}
+		checked++
+		payment, err := pays(sigs[i].Signature)
+		if err != nil {
+			return nil, err
+		}
+		if payment != nil {
+			payment.Signature, payment.Slot, payment.BlockTime = sigs[i].Signature, sigs[i].Slot, sigs[i].BlockTime
+			return payment, nil
+		}
+	}
+	if checked == 0 {
+		return nil, fmt.Errorf("%w: no transaction carries reference %s", ErrPaymentNotFound, transfer.References[0])
+	}
+	if transfer.SPLToken == "" {
+		return nil, fmt.Errorf("%w: none of %d transactions pays %s SOL to %s", ErrPaymentMismatch, checked, transfer.Amount, transfer.Recipient)
+	}
+	return nil, fmt.Errorf("%w: none of %d transactions pays %s of %s to %s", ErrPaymentMismatch, checked, transfer.Amount, transfer.SPLToken, transfer.Recipient)
+}
+
+// solanaPayMatcher returns a check of whether a transaction pays a transfer request, with the
+// payer and amount filled in when it does
+func (c *Client) solanaPayMatcher(ctx context.Context, transfer models.SolanaPayTransfer) (func(signature string) (*models.SolanaPayment, error), error) {
+	if transfer.SPLToken == "" {
+		expected, ok := rawAmount(*transfer.Amount, solDecimals)
+		if !ok {
+			return nil, fmt.Errorf("%w: amount %s is not a whole number of lamports", ErrPaymentMismatch, transfer.Amount)
+		}
+		return func(signature string) (*models.SolanaPayment, error) {
+			events, err := c.GetTransactionEvents(ctx, models.GetTransactionEventsRequest{Signature: signature})
+			if err != nil {
+				return nil, err
+			}
+			for _, ev := range events {
+				t, ok := ev.(*models.SOLTransferEvent)
+				if ok && t.To == transfer.Recipient && t.Amount.Raw == expected && paysRequest(transfer, t.Memo, t.References) {
+					return &models.SolanaPayment{Payer: t.From, Amount: t.Amount}, nil
+				}
+			}
+			return nil, nil
+		}, nil
+	}
+
+	mint, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: transfer.SPLToken})
+	if err != nil {
+		return nil, err
+	}
+	expected, ok := rawAmount(*transfer.Amount, mint.Decimals)
+	if !ok {
+		return nil, fmt.Errorf("%w: amount %s has more than the %d decimals of the mint", ErrPaymentMismatch, transfer.Amount, mint.Decimals)
+	}
+	destination, err := deriveATA(common.PublicKeyFromString(transfer.Recipient), common.PublicKeyFromString(mint.Address), common.PublicKeyFromString(mint.TokenProgram))
+	if err != nil {
+		return nil, err
+	}
+	return func(signature string) (*models.SolanaPayment, error) {
+		transfers, err := c.GetTransactionTransfersSPL(ctx, models.GetTransactionTransfersRequest{Signature: signature})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range transfers {
+			if t.Destination == destination.ToBase58() && t.Mint == mint.Address && t.Amount.Raw == expected && paysRequest(transfer, t.Memo, t.References) {
+				return &models.SolanaPayment{Payer: t.Authority, Amount: t.Amount}, nil
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// paysRequest reports whether a transfer carries the memo and every reference of the request
+func paysRequest(transfer models.SolanaPayTransfer, memo string, references []string) bool {
+	if transfer.Memo != "" && memo != transfer.Memo {
+		return false
+	}
+	for _, ref := range transfer.References {
+		if !slices.Contains(references, ref) {
+			return false
+		}
+	}
+	return true
+}
+
+// rawAmount scales a decimal amount to base units; false when it has more decimals than allowed
+func rawAmount(amount decimal.Decimal, decimals uint8) (uint64, bool) {
+	raw := amount.Shift(int32(decimals))
+	if !raw.Equal(raw.Truncate(0)) || raw.IsNegative() {
+		return 0, false
+	}
+	n := raw.BigInt()
+	if !n.IsUint64() {
+		return 0, false
+	}
+	return n.Uint64(), true
+}
@@ -0,0 +1,171 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+func TestSolanaPay_URLRoundTripAndStrictParsing(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	recipient := c.CreateAccount().PublicKey
+	mint := c.CreateAccount().PublicKey
+	ref := c.CreateAccount().PublicKey
+	amount := decimal.RequireFromString("12.5")
+
+	u, err := c.EncodeSolanaPayURL(models.SolanaPayTransfer{
+		Recipient: recipient, Amount: &amount, SPLToken: mint, References: []string{ref},
+		Label: "Coffee & Co", Message: "Order #42", Memo: "order:42",
+	})
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	want := "solana:" + recipient + "?amount=12.5&spl-token=" + mint + "&reference=" + ref + "&label=Coffee%20%26%20Co&message=Order%20%2342&memo=order%3A42"
+	if u != want {
+		t.Fatalf("unexpected url:\n got %s\nwant %s", u, want)
+	}
+	parsed, err := c.ParseSolanaPayURL(models.ParseSolanaPayURLRequest{URL: u})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if parsed.Recipient != recipient || !parsed.Amount.Equal(amount) || parsed.SPLToken != mint || len(parsed.References) != 1 ||
+		parsed.References[0] != ref || parsed.Label != "Coffee & Co" || parsed.Message != "Order #42" || parsed.Memo != "order:42" {
+		t.Fatalf("unexpected transfer: %+v", parsed)
+	}
+	if bare, err := c.ParseSolanaPayURL(models.ParseSolanaPayURLRequest{URL: "solana:" + recipient}); err != nil || bare.Amount != nil {
+		t.Fatalf("expected a bare recipient to parse, got %+v: %v", bare, err)
+	}
+
+	for name, raw := range map[string]string{
+		"scheme":           "bitcoin:" + recipient,
+		"transaction":      "solana:https://pay.example.com/tx",
+		"recipient":        "solana:not-a-key",
+		"negative amount":  "solana:" + recipient + "?amount=-1",
+		"exponent amount":  "solana:" + recipient + "?amount=1e3",
+		"leading zero":     "solana:" + recipient + "?amount=01",
+		"trailing dot":     "solana:" + recipient + "?amount=1.",
+		"lamport fraction": "solana:" + recipient + "?amount=0.0000000001",
+		"duplicate amount": "solana:" + recipient + "?amount=1&amount=2",
+		"duplicate label":  "solana:" + recipient + "?label=a&label=b",
+		"bad reference":    "solana:" + recipient + "?reference=xyz",
+		"twice reference":  "solana:" + recipient + "?reference=" + ref + "&reference=" + ref,
+		"bad token":        "solana:" + recipient + "?spl-token=xyz",
+	} {
+		if _, err := c.ParseSolanaPayURL(models.ParseSolanaPayURLRequest{URL: raw}); !errors.Is(err, sdk.ErrInvalidSolanaPayURL) {
+			t.Errorf("%s: expected ErrInvalidSolanaPayURL, got %v", name, err)
+		}
+	}
+}
+
+func TestSolanaPay_VerifyTransfer(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	merchant := c.CreateAccount()
+	customer := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: customer.PublicKey, Lamports: 1_000_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, customer), MintAuthority: customer.PublicKey, Decimals: 2})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	customerATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, customer), Owner: customer.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	merchantATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, customer), Owner: merchant.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, customer), Mint: mint, DestinationATA: customerATA, Amount: 10_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	amount := decimal.RequireFromString("12.5")
+	request := models.SolanaPayTransfer{Recipient: merchant.PublicKey, Amount: &amount, SPLToken: mint, References: []string{c.CreateAccount().PublicKey}, Memo: "order:42"}
+	if _, err := c.VerifySolanaPayTransfer(ctx, models.VerifySolanaPayTransferRequest{Transfer: request}); !errors.Is(err, sdk.ErrPaymentNotFound) {
+		t.Fatalf("expected no payment yet, got %v", err)
+	}
+
+	// anyone can put the public reference on a transaction that pays nothing before the customer pays
+	attacker := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: attacker.PublicKey, Lamports: 10_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	if _, err := c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: confirmed, From: mustSigner(t, attacker), ToPublicKey: merchant.PublicKey, Lamports: 1, References: request.References, Memo: request.Memo,
+	}); err != nil {
+		t.Fatalf("decoy failed: %v", err)
+	}
+	if _, err := c.VerifySolanaPayTransfer(ctx, models.VerifySolanaPayTransferRequest{Transfer: request}); !errors.Is(err, sdk.ErrPaymentMismatch) {
+		t.Fatalf("expected the decoy alone to mismatch, got %v", err)
+	}
+
+	// the wallet pays what it parsed from the QR code
+	u, err := c.EncodeSolanaPayURL(request)
+	if err != nil {
+		t.Fatalf("encode failed: %v", err)
+	}
+	scanned, err := c.ParseSolanaPayURL(models.ParseSolanaPayURLRequest{URL: u})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	sig, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions: confirmed, Authority: mustSigner(t, customer), SourceATA: customerATA, DestinationATA: merchantATA, Mint: scanned.SPLToken,
+		Amount: 1_250, Decimals: 2, Memo: scanned.Memo, References: scanned.References,
+	})
+	if err != nil {
+		t.Fatalf("payment failed: %v", err)
+	}
+	payment, err := c.VerifySolanaPayTransfer(ctx, models.VerifySolanaPayTransferRequest{Transfer: request})
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if payment.Signature != sig || payment.Payer != customer.PublicKey || payment.Amount.Raw != 1_250 || !payment.Amount.Value.Equal(amount) {
+		t.Fatalf("unexpected payment: %+v", payment)
+	}
+
+	more := decimal.RequireFromString("13")
+	if _, err := c.VerifySolanaPayTransfer(ctx, models.VerifySolanaPayTransferRequest{Transfer: models.SolanaPayTransfer{
+		Recipient: merchant.PublicKey, Amount: &more, SPLToken: mint, References: request.References,
+	}}); !errors.Is(err, sdk.ErrPaymentMismatch) {
+		t.Fatalf("expected a short payment to mismatch, got %v", err)
+	}
+	if _, err := c.VerifySolanaPayTransfer(ctx, models.VerifySolanaPayTransferRequest{Transfer: models.SolanaPayTransfer{
+		Recipient: customer.PublicKey, Amount: &amount, SPLToken: mint, References: request.References,
+	}}); !errors.Is(err, sdk.ErrPaymentMismatch) {
+		t.Fatalf("expected another recipient to mismatch, got %v", err)
+	}
+
+	// a native SOL request
+	sol := decimal.RequireFromString("0.25")
+	solRequest := models.SolanaPayTransfer{Recipient: merchant.PublicKey, Amount: &sol, References: []string{c.CreateAccount().PublicKey}}
+	sig, err = c.TransferSOL(ctx, models.TransferSOLRequest{
+		SendOptions: confirmed, From: mustSigner(t, customer), ToPublicKey: merchant.PublicKey, Lamports: 250_000_000, References: solRequest.References,
+	})
+	if err != nil {
+		t.Fatalf("sol payment failed: %v", err)
+	}
+	payment, err = c.VerifySolanaPayTransfer(ctx, models.VerifySolanaPayTransferRequest{Transfer: solRequest})
+	if err != nil {
+		t.Fatalf("verify sol failed: %v", err)
+	}
+	if payment.Signature != sig || payment.Payer != customer.PublicKey || payment.Amount.Raw != 250_000_000 {
+		t.Fatalf("unexpected sol payment: %+v", payment)
+	}
+	solRequest.Memo = "order:43"
+	if _, err := c.VerifySolanaPayTransfer(ctx, models.VerifySolanaPayTransferRequest{Transfer: solRequest}); !errors.Is(err, sdk.ErrPaymentMismatch) {
+		t.Fatalf("expected a missing memo to mismatch, got %v", err)
+	}
+}
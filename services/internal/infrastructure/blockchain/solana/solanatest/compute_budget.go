@@ -0,0 +1,141 @@
+package solanatest
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	json "github.com/goccy/go-json"
+)
+
+const (
+	defaultInstructionUnits = 200_000
+	maxTransactionUnits     = 1_400_000
+	microLamportsPerLamport = 1_000_000
+)
+
+// computeBudget is the unit limit and price requested by a transaction.
+type computeBudget struct {
+	limit uint64
+	price uint64
+}
+
+// priorityFee is the lamports charged on top of signature fees.
+func (b computeBudget) priorityFee() uint64 {
+	return (b.price*b.limit + microLamportsPerLamport - 1) / microLamportsPerLamport
+}
+
+type prioritizationSample struct {
+	slot     uint64
+	price    uint64
+	writable []common.PublicKey
+}
+
+func parseComputeBudget(msg types.Message) computeBudget {
+	var (
+		b        computeBudget
+		limitSet bool
+		others   uint64
+	)
+	for _, inst := range msg.Instructions {
+		if msg.Accounts[inst.ProgramIDIndex] != common.ComputeBudgetProgramID {
+			others++
+			continue
+		}
+		if len(inst.Data) == 0 {
+			continue
+		}
+		switch inst.Data[0] {
+		case 2: // SetComputeUnitLimit
+			if len(inst.Data) >= 5 {
+				b.limit = uint64(binary.LittleEndian.Uint32(inst.Data[1:5]))
+				limitSet = true
+			}
+		case 3: // SetComputeUnitPrice
+			if len(inst.Data) >= 9 {
+				b.price = binary.LittleEndian.Uint64(inst.Data[1:9])
+			}
+		}
+	}
+	if !limitSet {
+		b.limit = others * defaultInstructionUnits
+	}
+	if b.limit > maxTransactionUnits {
+		b.limit = maxTransactionUnits
+	}
+	return b
+}
+
+func processComputeBudget(inv *invocation) error {
+	if len(inv.data) == 0 {
+		return builtinErr("InvalidInstructionData")
+	}
+	switch inv.data[0] {
+	case 2:
+		if len(inv.data) < 5 {
+			return builtinErr("InvalidInstructionData")
+		}
+	case 3:
+		if len(inv.data) < 9 {
+			return builtinErr("InvalidInstructionData")
+		}
+	default:
+		return builtinErr("InvalidInstructionData")
+	}
+	return nil
+}
+
+// recordPrioritization must be called with mu held.
+func (s *Server) recordPrioritization(msg types.Message, price uint64) {
+	sample := prioritizationSample{slot: s.slot, price: price}
+	for i, key := range msg.Accounts {
+		if isWritable(msg, i) {
+			sample.writable = append(sample.writable, key)
+		}
+	}
+	s.prioritization = append(s.prioritization, sample)
+}
+
+// getRecentPrioritizationFees reports, per recent slot, the highest unit price paid by a
+// transaction that write-locked one of the given accounts.
+func (s *Server) getRecentPrioritizationFees(params []json.RawMessage) (any, *rpcError) {
+	var addrs []string
+	if len(params) > 0 {
+		if err := decodeParam(params, 0, &addrs); err != nil {
+			return nil, err
+		}
+	}
+	want := map[common.PublicKey]bool{}
+	for _, a := range addrs {
+		want[common.PublicKeyFromString(a)] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	perSlot := map[uint64]uint64{}
+	for _, sample := range s.prioritization {
+		if sample.slot+blockhashValidity < s.slot {
+			continue
+		}
+		match := len(want) == 0
+		for _, key := range sample.writable {
+			if want[key] {
+				match = true
+				break
+			}
+		}
+		if !match {
+			continue
+		}
+		if sample.price >= perSlot[sample.slot] {
+			perSlot[sample.slot] = sample.price
+		}
+	}
+	fees := make([]map[string]uint64, 0, len(perSlot))
+	for slot, price := range perSlot {
+		fees = append(fees, map[string]uint64{"slot": slot, "prioritizationFee": price})
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i]["slot"] < fees[j]["slot"] })
+	return fees, nil
+}
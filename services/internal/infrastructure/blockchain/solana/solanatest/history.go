@@ -0,0 +1,123 @@
+package solanatest
+
+import (
+	"slices"
+	"strings"
+
+	json "github.com/goccy/go-json"
+)
+
+// maxSignaturesLimit is the most signatures getSignaturesForAddress returns per call.
+const maxSignaturesLimit = 1000
+
+type signaturesForAddressConfig struct {
+	Limit      int    `json:"limit"`
+	Before     string `json:"before"`
+	Until      string `json:"until"`
+	Commitment string `json:"commitment"`
+}
+
+// getSignaturesForAddress lists the landed transactions referencing an address,
+// newest first.
+func (s *Server) getSignaturesForAddress(params []json.RawMessage) (any, *rpcError) {
+	addr, rpcErr := decodePublicKey(params, 0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var cfg signaturesForAddressConfig
+	if len(params) > 1 {
+		if err := decodeParam(params, 1, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Limit == 0 {
+		cfg.Limit = maxSignaturesLimit
+	}
+	if cfg.Limit < 0 || cfg.Limit > maxSignaturesLimit {
+		return nil, invalidParams("Invalid limit; max %d", maxSignaturesLimit)
+	}
+	// confirmation takes one slot, finalization finalizationDepth
+	depth := uint64(finalizationDepth)
+	switch cfg.Commitment {
+	case "", "finalized":
+	case "confirmed":
+		depth = 1
+	default:
+		return nil, invalidParams("Method does not support commitment below `confirmed`")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	listing := cfg.Before == ""
+	result := []map[string]any{}
+	for i := len(s.history) - 1; i >= 0 && len(result) < cfg.Limit; i-- {
+		rec := s.history[i]
+		if rec.signature == cfg.Until {
+			break
+		}
+		if !listing {
+			listing = rec.signature == cfg.Before
+			continue
+		}
+		if s.slot-rec.slot < depth || !slices.Contains(rec.keys, addr) {
+			continue
+		}
+		status := "confirmed"
+		if s.slot-rec.slot >= finalizationDepth {
+			status = "finalized"
+		}
+		result = append(result, map[string]any{
+			"signature":          rec.signature,
+			"slot":               rec.slot,
+			"blockTime":          rec.blockTime,
+			"err":                rec.meta.Err,
+			"memo":               rec.memo,
+			"confirmationStatus": status,
+		})
+	}
+	return result, nil
+}
+
+type tokenAccountsFilter struct {
+	Mint      string `json:"mint"`
+	ProgramID string `json:"programId"`
+}
+
+// getTokenAccountsByOwner lists the token accounts of a wallet, filtered by mint or
+// token program, in base64 encoding.
+func (s *Server) getTokenAccountsByOwner(params []json.RawMessage) (any, *rpcError) {
+	owner, rpcErr := decodePublicKey(params, 0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var filter tokenAccountsFilter
+	if err := decodeParam(params, 1, &filter); err != nil {
+		return nil, err
+	}
+	if (filter.Mint == "") == (filter.ProgramID == "") {
+		return nil, invalidParams("Invalid params: expected exactly one of mint or programId")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	type keyedAccount struct {
+		Pubkey  string           `json:"pubkey"`
+		Account *accountInfoJSON `json:"account"`
+	}
+	value := []keyedAccount{}
+	for key, acc := range s.accounts {
+		if !isTokenProgram(acc.Owner) || (filter.ProgramID != "" && acc.Owner.ToBase58() != filter.ProgramID) {
+			continue
+		}
+		ta, ok := unpackTokenAccount(acc.Data)
+		if !ok || ta.owner != owner || (filter.Mint != "" && ta.mint.ToBase58() != filter.Mint) {
+			continue
+		}
+		value = append(value, keyedAccount{Pubkey: key.ToBase58(), Account: toAccountInfoJSON(acc)})
+	}
+	// map iteration is random; keep responses stable
+	slices.SortFunc(value, func(a, b keyedAccount) int {
+		return strings.Compare(a.Pubkey, b.Pubkey)
+	})
+	return valueWithContext{Context: rpcContext{Slot: s.slot}, Value: value}, nil
+}
@@ -0,0 +1,232 @@
+package solanatest
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+const (
+	// lookupTableMetaSize is the size of the table header preceding the addresses.
+	lookupTableMetaSize = 56
+
+	// lookupTableMaxAddresses is the most addresses a table can hold.
+	lookupTableMaxAddresses = 256
+
+	// recentSlotWindow is how far back the slot a table is derived from may be.
+	recentSlotWindow = 512
+)
+
+type lookupTable struct {
+	deactivationSlot uint64
+	lastExtendedSlot uint64
+	// startIndex is the number of addresses that were in the table before lastExtendedSlot
+	startIndex uint8
+	authority  *common.PublicKey
+	addresses  []common.PublicKey
+}
+
+func unpackLookupTable(acc *Account) (lookupTable, bool) {
+	data := acc.Data
+	if acc.Owner != common.AddressLookupTableProgramID || len(data) < lookupTableMetaSize ||
+		(len(data)-lookupTableMetaSize)%32 != 0 || binary.LittleEndian.Uint32(data[0:4]) != 1 {
+		return lookupTable{}, false
+	}
+	t := lookupTable{
+		deactivationSlot: binary.LittleEndian.Uint64(data[4:12]),
+		lastExtendedSlot: binary.LittleEndian.Uint64(data[12:20]),
+		startIndex:       data[20],
+	}
+	if data[21] == 1 {
+		authority := common.PublicKeyFromBytes(data[22:54])
+		t.authority = &authority
+	}
+	for off := lookupTableMetaSize; off < len(data); off += 32 {
+		t.addresses = append(t.addresses, common.PublicKeyFromBytes(data[off:off+32]))
+	}
+	return t, true
+}
+
+func packLookupTable(t lookupTable) []byte {
+	data := make([]byte, lookupTableMetaSize, lookupTableMetaSize+32*len(t.addresses))
+	binary.LittleEndian.PutUint32(data[0:4], 1) // ProgramState::LookupTable
+	putUint64(data[4:12], t.deactivationSlot)
+	putUint64(data[12:20], t.lastExtendedSlot)
+	data[20] = t.startIndex
+	if t.authority != nil {
+		data[21] = 1
+		copy(data[22:54], t.authority.Bytes())
+	}
+	for _, addr := range t.addresses {
+		data = append(data, addr.Bytes()...)
+	}
+	return data
+}
+
+func processLookupTable(inv *invocation) error {
+	if len(inv.data) < 4 {
+		return builtinErr("InvalidInstructionData")
+	}
+	data := inv.data[4:]
+	switch binary.LittleEndian.Uint32(inv.data[:4]) {
+	case 0: // CreateLookupTable
+		if len(data) < 9 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return createLookupTable(inv, binary.LittleEndian.Uint64(data[:8]), data[8])
+	case 2: // ExtendLookupTable
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		n := binary.LittleEndian.Uint64(data[:8])
+		if n == 0 || uint64(len(data)-8) != 32*n {
+			return builtinErr("InvalidInstructionData")
+		}
+		var addresses []common.PublicKey
+		for off := 8; off < len(data); off += 32 {
+			addresses = append(addresses, common.PublicKeyFromBytes(data[off:off+32]))
+		}
+		return extendLookupTable(inv, addresses)
+	default:
+		return builtinErr("InvalidInstructionData")
+	}
+}
+
+func createLookupTable(inv *invocation, recentSlot uint64, bump uint8) error {
+	if err := inv.requireSigner(2); err != nil {
+		return err
+	}
+	authority, _ := inv.key(1)
+	if recentSlot > inv.slot || inv.slot-recentSlot > recentSlotWindow {
+		inv.log("%d is not a recent slot", recentSlot)
+		return builtinErr("InvalidInstructionData")
+	}
+	expected, err := common.CreateProgramAddress(
+		[][]byte{authority.Bytes(), binary.LittleEndian.AppendUint64(nil, recentSlot), {bump}},
+		common.AddressLookupTableProgramID,
+	)
+	if err != nil || expected != inv.keys[0] {
+		inv.log("Table address must match derived address: %s", expected.ToBase58())
+		return builtinErr("InvalidArgument")
+	}
+	table, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	if table.Owner == common.AddressLookupTableProgramID {
+		return builtinErr("AccountAlreadyInitialized")
+	}
+	if table.Lamports > 0 || len(table.Data) > 0 {
+		return customErr(systemErrAccountAlreadyInUse)
+	}
+	payer, err := inv.mutable(2)
+	if err != nil {
+		return err
+	}
+	rent := RentExemptMinimum(lookupTableMetaSize)
+	if payer.Lamports < rent {
+		return customErr(systemErrResultWithNegativeLamports)
+	}
+	payer.Lamports -= rent
+	table.Lamports += rent
+	table.Owner = common.AddressLookupTableProgramID
+	table.Data = packLookupTable(lookupTable{deactivationSlot: math.MaxUint64, authority: &authority})
+	return nil
+}
+
+func extendLookupTable(inv *invocation, addresses []common.PublicKey) error {
+	acc, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	t, ok := unpackLookupTable(acc)
+	if !ok {
+		return builtinErr("InvalidAccountOwner")
+	}
+	if t.authority == nil {
+		inv.log("Lookup table is frozen")
+		return builtinErr("Immutable")
+	}
+	if err := inv.requireSigner(1); err != nil {
+		return err
+	}
+	if inv.keys[1] != *t.authority {
+		return builtinErr("IncorrectAuthority")
+	}
+	if t.deactivationSlot != math.MaxUint64 {
+		inv.log("Deactivated tables cannot be extended")
+		return builtinErr("InvalidArgument")
+	}
+	if len(t.addresses)+len(addresses) > lookupTableMaxAddresses {
+		inv.log("Extended lookup table length %d would exceed max capacity of %d", len(t.addresses)+len(addresses), lookupTableMaxAddresses)
+		return builtinErr("InvalidInstructionData")
+	}
+	if t.lastExtendedSlot != inv.slot {
+		t.lastExtendedSlot = inv.slot
+		t.startIndex = uint8(len(t.addresses))
+	}
+	t.addresses = append(t.addresses, addresses...)
+	acc.Data = packLookupTable(t)
+
+	// the payer tops the table up to the rent-exempt minimum of its new size
+	if rent := RentExemptMinimum(uint64(len(acc.Data))); acc.Lamports < rent {
+		if err := inv.requireSigner(2); err != nil {
+			return err
+		}
+		payer, err := inv.mutable(2)
+		if err != nil {
+			return err
+		}
+		if payer.Lamports < rent-acc.Lamports {
+			return customErr(systemErrResultWithNegativeLamports)
+		}
+		payer.Lamports -= rent - acc.Lamports
+		acc.Lamports = rent
+	}
+	return nil
+}
+
+// loadAddresses resolves the accounts a v0 message loads from lookup tables, writable
+// ones first. Addresses appended in the current slot are not usable yet.
+// It must be called with mu held.
+func (s *Server) loadAddresses(msg types.Message) (loadedAddresses, []common.PublicKey, string) {
+	loaded := loadedAddresses{Writable: []string{}, Readonly: []string{}}
+	var writable, readonly []common.PublicKey
+	for _, lookup := range msg.AddressLookupTables {
+		acc := s.accounts[lookup.AccountKey]
+		if acc == nil {
+			return loaded, nil, "AddressLookupTableNotFound"
+		}
+		t, ok := unpackLookupTable(acc)
+		if !ok {
+			return loaded, nil, "InvalidAddressLookupTableOwner"
+		}
+		if t.deactivationSlot != math.MaxUint64 && s.slot-t.deactivationSlot > recentSlotWindow {
+			return loaded, nil, "AddressLookupTableNotFound"
+		}
+		active := len(t.addresses)
+		if t.lastExtendedSlot >= s.slot {
+			active = int(t.startIndex)
+		}
+		for _, list := range []struct {
+			indexes []uint8
+			keys    *[]common.PublicKey
+		}{{lookup.WritableIndexes, &writable}, {lookup.ReadonlyIndexes, &readonly}} {
+			for _, idx := range list.indexes {
+				if int(idx) >= active {
+					return loaded, nil, "InvalidAddressLookupTableIndex"
+				}
+				*list.keys = append(*list.keys, t.addresses[idx])
+			}
+		}
+	}
+	for _, key := range writable {
+		loaded.Writable = append(loaded.Writable, key.ToBase58())
+	}
+	for _, key := range readonly {
+		loaded.Readonly = append(loaded.Readonly, key.ToBase58())
+	}
+	return loaded, append(writable, readonly...), ""
+}
@@ -0,0 +1,51 @@
+package solanatest
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+)
+
+// MemoV1ProgramID is the original SPL Memo program, which predates signer checks.
+var MemoV1ProgramID = common.PublicKeyFromString("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
+// processMemo logs a UTF-8 memo. Every account given to the instruction must
+// have signed it; the original program does not check.
+func processMemo(inv *invocation) error {
+	if inv.program == common.MemoProgramID {
+		for i := range inv.keys {
+			if !inv.signers[i] {
+				inv.log("Missing required signature for %s", inv.keys[i].ToBase58())
+				return builtinErr("MissingRequiredSignature")
+			}
+		}
+	}
+	if !utf8.Valid(inv.data) {
+		inv.log("Invalid UTF-8")
+		return builtinErr("InvalidInstructionData")
+	}
+	inv.log("Memo (len %d): %q", len(inv.data), string(inv.data))
+	return nil
+}
+
+// transactionMemo formats the memos of the outer instructions as "[len] text",
+// joined by "; ", or returns nil when there are none.
+func transactionMemo(msg types.Message, keys []common.PublicKey) *string {
+	var memos []string
+	for _, inst := range msg.Instructions {
+		if inst.ProgramIDIndex >= len(keys) {
+			continue
+		}
+		if program := keys[inst.ProgramIDIndex]; program == common.MemoProgramID || program == MemoV1ProgramID {
+			memos = append(memos, fmt.Sprintf("[%d] %s", len(inst.Data), inst.Data))
+		}
+	}
+	if len(memos) == 0 {
+		return nil
+	}
+	memo := strings.Join(memos, "; ")
+	return &memo
+}
@@ -0,0 +1,247 @@
+package solanatest
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	json "github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+)
+
+type accountInfoJSON struct {
+	Lamports   uint64   `json:"lamports"`
+	Owner      string   `json:"owner"`
+	Executable bool     `json:"executable"`
+	RentEpoch  uint64   `json:"rentEpoch"`
+	Data       []string `json:"data"`
+	Space      int      `json:"space"`
+}
+
+func toAccountInfoJSON(acc *Account) *accountInfoJSON {
+	if acc == nil {
+		return nil
+	}
+	return &accountInfoJSON{
+		Lamports:   acc.Lamports,
+		Owner:      acc.Owner.ToBase58(),
+		Executable: acc.Executable,
+		RentEpoch:  18446744073709551615,
+		Data:       []string{base64.StdEncoding.EncodeToString(acc.Data), "base64"},
+		Space:      len(acc.Data),
+	}
+}
+
+func (s *Server) getAccountInfo(params []json.RawMessage) (any, *rpcError) {
+	pub, rpcErr := decodePublicKey(params, 0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return valueWithContext{Context: rpcContext{Slot: s.slot}, Value: toAccountInfoJSON(s.accounts[pub])}, nil
+}
+
+func (s *Server) getBalance(params []json.RawMessage) (any, *rpcError) {
+	pub, rpcErr := decodePublicKey(params, 0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var lamports uint64
+	if acc, ok := s.accounts[pub]; ok {
+		lamports = acc.Lamports
+	}
+	return valueWithContext{Context: rpcContext{Slot: s.slot}, Value: lamports}, nil
+}
+
+// getBlockHeight reports the slot as block height; the fake cluster never skips slots.
+func (s *Server) getBlockHeight(params []json.RawMessage) (any, *rpcError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.slot, nil
+}
+
+func (s *Server) getHealth(params []json.RawMessage) (any, *rpcError) {
+	return "ok", nil
+}
+
+func (s *Server) getLatestBlockhash(params []json.RawMessage) (any, *rpcError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return valueWithContext{
+		Context: rpcContext{Slot: s.slot},
+		Value: map[string]any{
+			"blockhash":            s.blockhash,
+			"lastValidBlockHeight": s.blockhashes[s.blockhash],
+		},
+	}, nil
+}
+
+func (s *Server) getMinimumBalanceForRentExemption(params []json.RawMessage) (any, *rpcError) {
+	var dataLen uint64
+	if err := decodeParam(params, 0, &dataLen); err != nil {
+		return nil, err
+	}
+	return RentExemptMinimum(dataLen), nil
+}
+
+// getSignatureStatuses reports commitment by slot depth. Each call produces a
+// new slot, so transactions progress towards finalization while clients poll.
+func (s *Server) getSignatureStatuses(params []json.RawMessage) (any, *rpcError) {
+	var sigs []string
+	if err := decodeParam(params, 0, &sigs); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advanceSlot()
+
+	statuses := make([]any, 0, len(sigs))
+	for _, sig := range sigs {
+		rec, ok := s.txs[sig]
+		if !ok {
+			statuses = append(statuses, nil)
+			continue
+		}
+		depth := s.slot - rec.slot
+		status := map[string]any{
+			"slot":   rec.slot,
+			"err":    rec.meta.Err,
+			"status": rec.meta.Status,
+		}
+		switch {
+		case depth >= finalizationDepth:
+			status["confirmations"] = nil
+			status["confirmationStatus"] = "finalized"
+		case depth >= 1:
+			status["confirmations"] = depth
+			status["confirmationStatus"] = "confirmed"
+		default:
+			status["confirmations"] = 0
+			status["confirmationStatus"] = "processed"
+		}
+		statuses = append(statuses, status)
+	}
+	return valueWithContext{Context: rpcContext{Slot: s.slot}, Value: statuses}, nil
+}
+
+func (s *Server) getSlot(params []json.RawMessage) (any, *rpcError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.slot, nil
+}
+
+func (s *Server) getTransaction(params []json.RawMessage) (any, *rpcError) {
+	var sig string
+	if err := decodeParam(params, 0, &sig); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.txs[sig]
+	if !ok {
+		return nil, nil
+	}
+	return rec.toJSON(), nil
+}
+
+func (s *Server) requestAirdrop(params []json.RawMessage) (any, *rpcError) {
+	to, rpcErr := decodePublicKey(params, 0)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	var lamports uint64
+	if err := decodeParam(params, 1, &lamports); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data := make([]byte, 12)
+	data[0] = 2 // System Transfer
+	putUint64(data[4:], lamports)
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        s.faucet.PublicKey,
+			RecentBlockhash: s.blockhash,
+			Instructions: []types.Instruction{{
+				ProgramID: common.SystemProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: s.faucet.PublicKey, IsSigner: true, IsWritable: true},
+					{PubKey: to, IsSigner: false, IsWritable: true},
+				},
+				Data: data,
+			}},
+		}),
+		Signers: []types.Account{s.faucet},
+	})
+	if err != nil {
+		return nil, &rpcError{Code: -32603, Message: err.Error()}
+	}
+	rec, rpcErr := s.submit(tx, false)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return rec.signature, nil
+}
+
+type sendTransactionConfig struct {
+	Encoding      string `json:"encoding"`
+	SkipPreflight bool   `json:"skipPreflight"`
+}
+
+func (s *Server) sendTransaction(params []json.RawMessage) (any, *rpcError) {
+	var raw string
+	if err := decodeParam(params, 0, &raw); err != nil {
+		return nil, err
+	}
+	var cfg sendTransactionConfig
+	if len(params) > 1 {
+		if err := decodeParam(params, 1, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	tx, rpcErr := decodeWireTransaction(raw, cfg.Encoding)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dropSends > 0 && len(tx.Signatures) > 0 {
+		s.dropSends--
+		return base58.Encode(tx.Signatures[0]), nil
+	}
+	rec, rpcErr := s.submit(tx, cfg.SkipPreflight)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	return rec.signature, nil
+}
+
+func decodeWireTransaction(raw, encoding string) (types.Transaction, *rpcError) {
+	var (
+		b   []byte
+		err error
+	)
+	if encoding == "base64" {
+		b, err = base64.StdEncoding.DecodeString(raw)
+	} else {
+		b, err = base58.Decode(raw)
+	}
+	if err != nil {
+		return types.Transaction{}, invalidParams("invalid transaction encoding: %v", err)
+	}
+	tx, err := types.TransactionDeserialize(b)
+	if err != nil {
+		return types.Transaction{}, invalidParams("failed to deserialize transaction: %v", err)
+	}
+	return tx, nil
+}
+
+func blockTime() int64 {
+	return time.Now().Unix()
+}
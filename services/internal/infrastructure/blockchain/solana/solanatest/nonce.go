@@ -0,0 +1,193 @@
+package solanatest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// nonceAccountSize is the size of a versioned nonce account.
+const nonceAccountSize = 80
+
+// System program nonce errors.
+const (
+	systemErrNonceBlockhashNotExpired      = 7
+	systemErrNonceUnexpectedBlockhashValue = 8
+)
+
+type nonceState struct {
+	authority common.PublicKey
+	nonce     string
+}
+
+func unpackNonce(data []byte) (nonceState, bool) {
+	if len(data) != nonceAccountSize || binary.LittleEndian.Uint32(data[4:8]) != 1 {
+		return nonceState{}, false
+	}
+	return nonceState{
+		authority: common.PublicKeyFromBytes(data[8:40]),
+		nonce:     base58.Encode(data[40:72]),
+	}, true
+}
+
+func packNonce(data []byte, n nonceState) {
+	binary.LittleEndian.PutUint32(data[0:4], 1) // Versions::Current
+	binary.LittleEndian.PutUint32(data[4:8], 1) // State::Initialized
+	copy(data[8:40], n.authority.Bytes())
+	b, _ := base58.Decode(n.nonce)
+	copy(data[40:72], b)
+	binary.LittleEndian.PutUint64(data[72:80], LamportsPerSignature)
+}
+
+// durableNonce derives the value a nonce account stores for a blockhash.
+func durableNonce(blockhash string) string {
+	b, _ := base58.Decode(blockhash)
+	h := sha256.Sum256(append([]byte("DURABLE_NONCE"), b...))
+	return base58.Encode(h[:])
+}
+
+// nonceAccount reports the nonce account a transaction is anchored to: the
+// first instruction must advance a nonce holding the message blockhash.
+// It must be called with mu held.
+func (s *Server) nonceAccount(msg types.Message) (common.PublicKey, bool) {
+	if len(msg.Instructions) == 0 {
+		return common.PublicKey{}, false
+	}
+	inst := msg.Instructions[0]
+	if msg.Accounts[inst.ProgramIDIndex] != common.SystemProgramID || len(inst.Data) < 4 ||
+		binary.LittleEndian.Uint32(inst.Data[:4]) != 4 || len(inst.Accounts) == 0 {
+		return common.PublicKey{}, false
+	}
+	key := msg.Accounts[inst.Accounts[0]]
+	acc := s.accounts[key]
+	if acc == nil || acc.Owner != common.SystemProgramID {
+		return common.PublicKey{}, false
+	}
+	n, ok := unpackNonce(acc.Data)
+	if !ok || n.nonce != msg.RecentBlockHash {
+		return common.PublicKey{}, false
+	}
+	return key, true
+}
+
+// nonceAccountFor loads the i-th instruction account as a writable nonce account.
+func (inv *invocation) nonceAccountFor(i int) (*Account, error) {
+	acc, err := inv.mutable(i)
+	if err != nil {
+		return nil, err
+	}
+	if acc.Owner != common.SystemProgramID || len(acc.Data) != nonceAccountSize {
+		return nil, builtinErr("InvalidAccountData")
+	}
+	return acc, nil
+}
+
+func systemAdvanceNonce(inv *invocation) error {
+	acc, err := inv.nonceAccountFor(0)
+	if err != nil {
+		return err
+	}
+	n, ok := unpackNonce(acc.Data)
+	if !ok {
+		inv.log("Advance nonce account: Account %s state is invalid", inv.keys[0].ToBase58())
+		return builtinErr("InvalidAccountData")
+	}
+	if err := inv.validateNonceAuthority(n.authority, 2); err != nil {
+		return err
+	}
+	next := durableNonce(inv.blockhash)
+	if n.nonce == next {
+		inv.log("Advance nonce account: nonce can only advance once per slot")
+		return customErr(systemErrNonceBlockhashNotExpired)
+	}
+	n.nonce = next
+	packNonce(acc.Data, n)
+	return nil
+}
+
+func systemWithdrawNonce(inv *invocation, lamports uint64) error {
+	acc, err := inv.nonceAccountFor(0)
+	if err != nil {
+		return err
+	}
+	to, err := inv.mutable(1)
+	if err != nil {
+		return err
+	}
+	authority := inv.keys[0]
+	n, initialized := unpackNonce(acc.Data)
+	if initialized {
+		authority = n.authority
+	}
+	if err := inv.validateNonceAuthority(authority, 4); err != nil {
+		return err
+	}
+	if lamports > acc.Lamports {
+		inv.log("Withdraw nonce account: insufficient lamports %d, need %d", acc.Lamports, lamports)
+		return builtinErr("InsufficientFunds")
+	}
+	if initialized {
+		if lamports == acc.Lamports {
+			if n.nonce == durableNonce(inv.blockhash) {
+				return customErr(systemErrNonceBlockhashNotExpired)
+			}
+			clear(acc.Data)
+		} else if acc.Lamports-lamports < RentExemptMinimum(nonceAccountSize) {
+			inv.log("Withdraw nonce account: insufficient lamports %d, need %d", acc.Lamports-lamports, RentExemptMinimum(nonceAccountSize))
+			return builtinErr("InsufficientFunds")
+		}
+	}
+	acc.Lamports -= lamports
+	to.Lamports += lamports
+	return nil
+}
+
+func systemInitializeNonce(inv *invocation, authority common.PublicKey) error {
+	acc, err := inv.nonceAccountFor(0)
+	if err != nil {
+		return err
+	}
+	if _, ok := unpackNonce(acc.Data); ok {
+		inv.log("Initialize nonce account: Account %s state is invalid", inv.keys[0].ToBase58())
+		return builtinErr("InvalidAccountData")
+	}
+	if acc.Lamports < RentExemptMinimum(nonceAccountSize) {
+		return builtinErr("InsufficientFunds")
+	}
+	packNonce(acc.Data, nonceState{authority: authority, nonce: durableNonce(inv.blockhash)})
+	return nil
+}
+
+func systemAuthorizeNonce(inv *invocation, newAuthority common.PublicKey) error {
+	acc, err := inv.nonceAccountFor(0)
+	if err != nil {
+		return err
+	}
+	n, ok := unpackNonce(acc.Data)
+	if !ok {
+		return builtinErr("InvalidAccountData")
+	}
+	if err := inv.validateNonceAuthority(n.authority, 1); err != nil {
+		return err
+	}
+	n.authority = newAuthority
+	packNonce(acc.Data, n)
+	return nil
+}
+
+// validateNonceAuthority checks that the i-th instruction account is the
+// nonce authority and signed the transaction.
+func (inv *invocation) validateNonceAuthority(authority common.PublicKey, i int) error {
+	key, err := inv.key(i)
+	if err != nil {
+		return err
+	}
+	if key != authority || !inv.signers[i] {
+		inv.log("Nonce authority %s did not sign", authority.ToBase58())
+		return builtinErr("MissingRequiredSignature")
+	}
+	return nil
+}
@@ -0,0 +1,476 @@
+package solanatest
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	"github.com/shopspring/decimal"
+)
+
+// programError is an instruction error in the shape the cluster reports it:
+// either a builtin name such as "InvalidAccountData" or {"Custom": code}.
+type programError struct {
+	value any
+}
+
+func (e *programError) Error() string {
+	switch v := e.value.(type) {
+	case string:
+		return v
+	case map[string]uint32:
+		return fmt.Sprintf("custom program error: 0x%x", v["Custom"])
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func builtinErr(name string) error { return &programError{value: name} }
+
+func customErr(code uint32) error { return &programError{value: map[string]uint32{"Custom": code}} }
+
+// processor executes one instruction of a program.
+type processor func(inv *invocation) error
+
+// invocation is a single instruction being executed against a bank.
+type invocation struct {
+	bank     *bank
+	program  common.PublicKey
+	keys     []common.PublicKey
+	signers  []bool
+	writable []bool
+	data     []byte
+	logs     []string
+	slot     uint64
+	// blockhash is the cluster blockhash the instruction executes under
+	blockhash string
+}
+
+func (inv *invocation) log(format string, args ...any) {
+	inv.logs = append(inv.logs, "Program log: "+fmt.Sprintf(format, args...))
+}
+
+func (inv *invocation) key(i int) (common.PublicKey, error) {
+	if i >= len(inv.keys) {
+		return common.PublicKey{}, builtinErr("NotEnoughAccountKeys")
+	}
+	return inv.keys[i], nil
+}
+
+// account returns a read-only view of the i-th instruction account.
+func (inv *invocation) account(i int) (*Account, error) {
+	key, err := inv.key(i)
+	if err != nil {
+		return nil, err
+	}
+	return inv.bank.get(key), nil
+}
+
+// mutable returns the i-th instruction account for modification.
+func (inv *invocation) mutable(i int) (*Account, error) {
+	acc, err := inv.account(i)
+	if err != nil {
+		return nil, err
+	}
+	if !inv.writable[i] {
+		return nil, builtinErr("ReadonlyDataModified")
+	}
+	return acc, nil
+}
+
+func (inv *invocation) requireSigner(i int) error {
+	if _, err := inv.key(i); err != nil {
+		return err
+	}
+	if !inv.signers[i] {
+		return builtinErr("MissingRequiredSignature")
+	}
+	return nil
+}
+
+// bank is a copy-on-write view over the cluster accounts.
+type bank struct {
+	base  map[common.PublicKey]*Account
+	dirty map[common.PublicKey]*Account
+}
+
+func newBank(base map[common.PublicKey]*Account) *bank {
+	return &bank{base: base, dirty: map[common.PublicKey]*Account{}}
+}
+
+func (b *bank) get(key common.PublicKey) *Account {
+	if acc, ok := b.dirty[key]; ok {
+		return acc
+	}
+	acc, ok := b.base[key]
+	if ok {
+		acc = acc.clone()
+	} else {
+		acc = &Account{Owner: common.SystemProgramID}
+	}
+	b.dirty[key] = acc
+	return acc
+}
+
+func (b *bank) peek(key common.PublicKey) *Account {
+	if acc, ok := b.dirty[key]; ok {
+		if acc.Lamports == 0 {
+			return nil
+		}
+		return acc
+	}
+	return b.base[key]
+}
+
+// commit writes changes back; accounts left without lamports are purged.
+func (b *bank) commit() {
+	for key, acc := range b.dirty {
+		if acc.Lamports == 0 {
+			delete(b.base, key)
+			continue
+		}
+		b.base[key] = acc
+	}
+}
+
+type tokenBalance struct {
+	AccountIndex  int            `json:"accountIndex"`
+	Mint          string         `json:"mint"`
+	Owner         string         `json:"owner"`
+	ProgramID     string         `json:"programId"`
+	UITokenAmount map[string]any `json:"uiTokenAmount"`
+}
+
+type loadedAddresses struct {
+	Writable []string `json:"writable"`
+	Readonly []string `json:"readonly"`
+}
+
+type txMeta struct {
+	Err                  any             `json:"err"`
+	Status               map[string]any  `json:"status"`
+	Fee                  uint64          `json:"fee"`
+	PreBalances          []uint64        `json:"preBalances"`
+	PostBalances         []uint64        `json:"postBalances"`
+	PreTokenBalances     []tokenBalance  `json:"preTokenBalances"`
+	PostTokenBalances    []tokenBalance  `json:"postTokenBalances"`
+	InnerInstructions    []any           `json:"innerInstructions"`
+	LogMessages          []string        `json:"logMessages"`
+	LoadedAddresses      loadedAddresses `json:"loadedAddresses"`
+	Rewards              []any           `json:"rewards"`
+	ComputeUnitsConsumed uint64          `json:"computeUnitsConsumed"`
+}
+
+type txRecord struct {
+	signature string
+	// version is "legacy" or 0
+	version any
+	slot    uint64
+	// keys are the accounts the transaction references, loaded ones included
+	keys      []common.PublicKey
+	blockTime int64
+	raw       []byte
+	meta      txMeta
+	// memo lists the memos of the transaction as getSignaturesForAddress reports them
+	memo *string
+}
+
+func (r *txRecord) toJSON() map[string]any {
+	return map[string]any{
+		"slot":        r.slot,
+		"blockTime":   r.blockTime,
+		"meta":        r.meta,
+		"transaction": []string{base64.StdEncoding.EncodeToString(r.raw), "base64"},
+		"version":     r.version,
+	}
+}
+
+// execution is the outcome of running a transaction against a bank.
+type execution struct {
+	keys     []common.PublicKey
+	loaded   loadedAddresses
+	fee      uint64
+	budget   computeBudget
+	err      any
+	rejected bool
+	logs     []string
+	units    uint64
+	bank     *bank
+	pre      []uint64
+	preToken []tokenBalance
+}
+
+var instructionUnits = map[common.PublicKey]uint64{
+	common.ComputeBudgetProgramID:             150,
+	common.SystemProgramID:                    150,
+	common.TokenProgramID:                     4_500,
+	common.Token2022ProgramID:                 6_000,
+	common.SPLAssociatedTokenAccountProgramID: 20_000,
+	common.AddressLookupTableProgramID:        1_500,
+	common.StakeProgramID:                     750,
+}
+
+// execute must be called with mu held. It never mutates cluster state.
+func (s *Server) execute(tx types.Transaction, verify bool) (*execution, *rpcError) {
+	msg := tx.Message
+	msgBytes, err := msg.Serialize()
+	if err != nil {
+		return nil, invalidParams("failed to serialize message: %v", err)
+	}
+	if verify {
+		if len(tx.Signatures) != int(msg.Header.NumRequireSignatures) {
+			return nil, &rpcError{Code: -32003, Message: "Transaction signature verification failure"}
+		}
+		for i, sig := range tx.Signatures {
+			if !ed25519.Verify(msg.Accounts[i].Bytes(), msgBytes, sig) {
+				return nil, &rpcError{Code: -32003, Message: "Transaction signature verification failure"}
+			}
+		}
+	}
+
+	// a v0 message lists its static keys, then the writable and readonly addresses it loads
+	loaded, lookups, loadErr := s.loadAddresses(msg)
+	keys := append(append([]common.PublicKey(nil), msg.Accounts...), lookups...)
+	budget := parseComputeBudget(msg)
+	ex := &execution{
+		keys:   keys,
+		loaded: loaded,
+		fee:    uint64(msg.Header.NumRequireSignatures)*LamportsPerSignature + budget.priorityFee(),
+		budget: budget,
+	}
+	if loadErr != "" {
+		ex.err, ex.rejected = loadErr, true
+		return ex, nil
+	}
+	for _, key := range keys {
+		var lamports uint64
+		if acc := s.accounts[key]; acc != nil {
+			lamports = acc.Lamports
+		}
+		ex.pre = append(ex.pre, lamports)
+	}
+	ex.preToken = tokenBalances(keys, func(k common.PublicKey) *Account { return s.accounts[k] })
+
+	nonce, durable := s.nonceAccount(msg)
+	if !durable && !s.blockhashValid(msg.RecentBlockHash) {
+		ex.err, ex.rejected = "BlockhashNotFound", true
+		return ex, nil
+	}
+	payer := s.accounts[keys[0]]
+	if payer == nil {
+		ex.err, ex.rejected = "AccountNotFound", true
+		return ex, nil
+	}
+	if payer.Lamports < ex.fee {
+		ex.err, ex.rejected = "InsufficientFundsForFee", true
+		return ex, nil
+	}
+
+	ex.bank = newBank(s.accounts)
+	ex.bank.get(keys[0]).Lamports -= ex.fee
+
+	for i, inst := range msg.Instructions {
+		program := keys[inst.ProgramIDIndex]
+		ex.logs = append(ex.logs, fmt.Sprintf("Program %s invoke [1]", program.ToBase58()))
+		proc, ok := s.programs[program]
+		if !ok {
+			ex.logs = append(ex.logs, fmt.Sprintf("Program %s failed: unsupported program id", program.ToBase58()))
+			ex.err = map[string]any{"InstructionError": []any{i, "UnsupportedProgramId"}}
+			break
+		}
+		inv := &invocation{bank: ex.bank, program: program, data: inst.Data, slot: s.slot, blockhash: s.blockhash}
+		for _, idx := range inst.Accounts {
+			inv.keys = append(inv.keys, keys[idx])
+			inv.signers = append(inv.signers, idx < int(msg.Header.NumRequireSignatures))
+			inv.writable = append(inv.writable, isWritable(msg, idx) || isLoadedWritable(msg, loaded, idx))
+		}
+		err := proc(inv)
+		ex.logs = append(ex.logs, inv.logs...)
+		units := instructionUnits[program]
+		remaining := budget.limit - min(ex.units, budget.limit)
+		ex.units += min(units, remaining)
+		ex.logs = append(ex.logs, fmt.Sprintf("Program %s consumed %d of %d compute units", program.ToBase58(), min(units, remaining), remaining))
+		if err == nil && units > remaining {
+			err = builtinErr("ComputationalBudgetExceeded")
+		}
+		if err != nil {
+			ex.logs = append(ex.logs, fmt.Sprintf("Program %s failed: %v", program.ToBase58(), err))
+			var value any = err.Error()
+			if pe, ok := err.(*programError); ok {
+				value = pe.value
+			}
+			ex.err = map[string]any{"InstructionError": []any{i, value}}
+			break
+		}
+		ex.logs = append(ex.logs, fmt.Sprintf("Program %s success", program.ToBase58()))
+	}
+
+	if ex.err != nil {
+		ex.bank = newBank(s.accounts)
+		ex.bank.get(keys[0]).Lamports -= ex.fee
+		// a failed durable transaction still consumes its nonce
+		if durable {
+			acc := ex.bank.get(nonce)
+			if n, ok := unpackNonce(acc.Data); ok {
+				n.nonce = durableNonce(s.blockhash)
+				packNonce(acc.Data, n)
+			}
+		}
+	}
+	return ex, nil
+}
+
+func isWritable(msg types.Message, idx int) bool {
+	signed := int(msg.Header.NumRequireSignatures)
+	if idx < signed {
+		return idx < signed-int(msg.Header.NumReadonlySignedAccounts)
+	}
+	return idx < len(msg.Accounts)-int(msg.Header.NumReadonlyUnsignedAccounts)
+}
+
+// isLoadedWritable reports whether idx refers to an address loaded as writable from a lookup table.
+func isLoadedWritable(msg types.Message, loaded loadedAddresses, idx int) bool {
+	return idx >= len(msg.Accounts) && idx < len(msg.Accounts)+len(loaded.Writable)
+}
+
+// submit must be called with mu held. It executes the transaction and, when it
+// lands, commits its effects in a new slot.
+func (s *Server) submit(tx types.Transaction, skipPreflight bool) (*txRecord, *rpcError) {
+	if len(tx.Signatures) == 0 {
+		return nil, invalidParams("transaction has no signatures")
+	}
+	sig := base58.Encode(tx.Signatures[0])
+	if rec, ok := s.txs[sig]; ok {
+		if skipPreflight {
+			return rec, nil
+		}
+		return nil, simulationFailed("AlreadyProcessed", nil, 0)
+	}
+
+	ex, rpcErr := s.execute(tx, true)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	if ex.rejected || (ex.err != nil && !skipPreflight) {
+		return nil, simulationFailed(ex.err, ex.logs, ex.units)
+	}
+
+	raw, err := tx.Serialize()
+	if err != nil {
+		return nil, invalidParams("failed to serialize transaction: %v", err)
+	}
+	ex.bank.commit()
+	s.advanceSlot()
+	s.recordPrioritization(tx.Message, ex.budget.price)
+
+	rec := &txRecord{
+		signature: sig,
+		version:   "legacy",
+		slot:      s.slot,
+		keys:      ex.keys,
+		blockTime: blockTime(),
+		raw:       raw,
+		memo:      transactionMemo(tx.Message, ex.keys),
+		meta: txMeta{
+			Err:                  ex.err,
+			Status:               map[string]any{"Ok": nil},
+			Fee:                  ex.fee,
+			PreBalances:          ex.pre,
+			PreTokenBalances:     ex.preToken,
+			InnerInstructions:    []any{},
+			LogMessages:          ex.logs,
+			LoadedAddresses:      ex.loaded,
+			Rewards:              []any{},
+			ComputeUnitsConsumed: ex.units,
+		},
+	}
+	if tx.Message.Version == types.MessageVersionV0 {
+		rec.version = 0
+	}
+	if ex.err != nil {
+		rec.meta.Status = map[string]any{"Err": ex.err}
+	}
+	for _, key := range ex.keys {
+		var lamports uint64
+		if acc := s.accounts[key]; acc != nil {
+			lamports = acc.Lamports
+		}
+		rec.meta.PostBalances = append(rec.meta.PostBalances, lamports)
+	}
+	rec.meta.PostTokenBalances = tokenBalances(ex.keys, func(k common.PublicKey) *Account { return s.accounts[k] })
+	s.txs[sig] = rec
+	s.history = append(s.history, rec)
+	return rec, nil
+}
+
+func simulationFailed(txErr any, logs []string, units uint64) *rpcError {
+	if logs == nil {
+		logs = []string{}
+	}
+	return &rpcError{
+		Code:    -32002,
+		Message: "Transaction simulation failed: " + describeTxError(txErr),
+		Data: map[string]any{
+			"err":           txErr,
+			"logs":          logs,
+			"accounts":      nil,
+			"unitsConsumed": units,
+		},
+	}
+}
+
+func describeTxError(txErr any) string {
+	m, ok := txErr.(map[string]any)
+	if !ok {
+		return fmt.Sprintf("%v", txErr)
+	}
+	ie, ok := m["InstructionError"].([]any)
+	if !ok || len(ie) != 2 {
+		return fmt.Sprintf("%v", txErr)
+	}
+	return fmt.Sprintf("Error processing Instruction %v: %v", ie[0], (&programError{value: ie[1]}).Error())
+}
+
+func tokenBalances(keys []common.PublicKey, lookup func(common.PublicKey) *Account) []tokenBalance {
+	balances := []tokenBalance{}
+	for i, key := range keys {
+		acc := lookup(key)
+		if acc == nil || !isTokenProgram(acc.Owner) {
+			continue
+		}
+		ta, ok := unpackTokenAccount(acc.Data)
+		if !ok {
+			continue
+		}
+		var decimals uint8
+		if mintAcc := lookup(ta.mint); mintAcc != nil {
+			if m, ok := unpackMint(mintAcc.Data); ok {
+				decimals = m.decimals
+			}
+		}
+		ui := decimal.NewFromBigInt(new(big.Int).SetUint64(ta.amount), -int32(decimals))
+		uiFloat, _ := ui.Float64()
+		balances = append(balances, tokenBalance{
+			AccountIndex: i,
+			Mint:         ta.mint.ToBase58(),
+			Owner:        ta.owner.ToBase58(),
+			ProgramID:    acc.Owner.ToBase58(),
+			UITokenAmount: map[string]any{
+				"amount":         fmt.Sprintf("%d", ta.amount),
+				"decimals":       decimals,
+				"uiAmount":       uiFloat,
+				"uiAmountString": ui.String(),
+			},
+		})
+	}
+	return balances
+}
+
+func putUint64(b []byte, v uint64) {
+	binary.LittleEndian.PutUint64(b, v)
+}
@@ -0,0 +1,326 @@
+// Package solanatest provides an in-process Solana JSON-RPC cluster for tests.
+//
+// The server keeps accounts in memory and executes System, SPL Token, Token-2022
+// (including transfer fees), Associated Token Account, ComputeBudget, Address
+// Lookup Table and Stake instructions, including v0 transactions that load accounts from
+// lookup tables, so client flows can be exercised with sdk.NewClient(server.URL)
+// without touching a public cluster.
+package solanatest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	json "github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+)
+
+const (
+	// LamportsPerSignature is the base fee charged per transaction signature.
+	LamportsPerSignature = 5000
+
+	// blockhashValidity is the number of slots a blockhash stays usable.
+	blockhashValidity = 150
+
+	// finalizationDepth is the number of slots after which a transaction is finalized.
+	finalizationDepth = 32
+
+	faucetLamports = 500_000_000 * 1_000_000_000
+)
+
+// Account is an account stored by the fake cluster.
+type Account struct {
+	Lamports   uint64
+	Owner      common.PublicKey
+	Data       []byte
+	Executable bool
+}
+
+func (a *Account) clone() *Account {
+	c := *a
+	c.Data = append([]byte(nil), a.Data...)
+	return &c
+}
+
+// Server is a fake Solana cluster served over HTTP JSON-RPC.
+type Server struct {
+	URL string
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	accounts map[common.PublicKey]*Account
+	txs      map[string]*txRecord
+	// history lists landed transactions oldest first
+	history     []*txRecord
+	slot        uint64
+	blockhash   string
+	blockhashes map[string]uint64
+	faucet      types.Account
+	methods     map[string]rpcHandler
+	programs    map[common.PublicKey]processor
+
+	prioritization []prioritizationSample
+
+	// rewards are the stake rewards paid for each completed epoch
+	rewards map[uint64]map[common.PublicKey]inflationReward
+
+	// dropSends and loseResponses simulate an unreliable network for sendTransaction
+	dropSends     int
+	loseResponses int
+}
+
+// NewServer starts a fake cluster. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{
+		accounts:    map[common.PublicKey]*Account{},
+		txs:         map[string]*txRecord{},
+		blockhashes: map[string]uint64{},
+		faucet:      types.NewAccount(),
+		rewards:     map[uint64]map[common.PublicKey]inflationReward{},
+	}
+	s.accounts[s.faucet.PublicKey] = &Account{Lamports: faucetLamports, Owner: common.SystemProgramID}
+	s.accounts[common.SysVarStakeHistoryPubkey] = &Account{
+		Lamports: RentExemptMinimum(stakeHistorySize),
+		Owner:    common.SysVarPubkey,
+		Data:     packStakeHistory(nil),
+	}
+	s.methods = map[string]rpcHandler{
+		"getAccountInfo":                    s.getAccountInfo,
+		"getBalance":                        s.getBalance,
+		"getBlockHeight":                    s.getBlockHeight,
+		"getEpochInfo":                      s.getEpochInfo,
+		"getFeeForMessage":                  s.getFeeForMessage,
+		"getHealth":                         s.getHealth,
+		"getInflationReward":                s.getInflationReward,
+		"getLatestBlockhash":                s.getLatestBlockhash,
+		"getMinimumBalanceForRentExemption": s.getMinimumBalanceForRentExemption,
+		"getMultipleAccounts":               s.getMultipleAccounts,
+		"getRecentPrioritizationFees":       s.getRecentPrioritizationFees,
+		"getSignatureStatuses":              s.getSignatureStatuses,
+		"getSignaturesForAddress":           s.getSignaturesForAddress,
+		"getSlot":                           s.getSlot,
+		"getTokenAccountsByOwner":           s.getTokenAccountsByOwner,
+		"getTransaction":                    s.getTransaction,
+		"requestAirdrop":                    s.requestAirdrop,
+		"sendTransaction":                   s.sendTransaction,
+		"simulateTransaction":               s.simulateTransaction,
+	}
+	s.programs = map[common.PublicKey]processor{
+		common.ComputeBudgetProgramID:             processComputeBudget,
+		common.SystemProgramID:                    processSystem,
+		common.TokenProgramID:                     processToken,
+		common.Token2022ProgramID:                 processToken,
+		common.SPLAssociatedTokenAccountProgramID: processAssociatedTokenAccount,
+		common.AddressLookupTableProgramID:        processLookupTable,
+		TokenMetadataProgramID:                    processTokenMetadata,
+		common.MemoProgramID:                      processMemo,
+		MemoV1ProgramID:                           processMemo,
+		common.StakeProgramID:                     processStake,
+	}
+	s.advanceSlot()
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// SetAccount stores an account, replacing any existing one.
+func (s *Server) SetAccount(pub string, acc Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[common.PublicKeyFromString(pub)] = acc.clone()
+}
+
+// GetAccount returns a copy of a stored account.
+func (s *Server) GetAccount(pub string) (Account, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	acc, ok := s.accounts[common.PublicKeyFromString(pub)]
+	if !ok {
+		return Account{}, false
+	}
+	return *acc.clone(), true
+}
+
+// AdvanceSlots moves the cluster forward by n slots, expiring old blockhashes.
+func (s *Server) AdvanceSlots(n uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := uint64(0); i < n; i++ {
+		s.advanceSlot()
+	}
+}
+
+// DropTransactions makes the next n sendTransaction calls answer with the signature
+// without the transaction ever landing, as when a leader drops it.
+func (s *Server) DropTransactions(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropSends = n
+}
+
+// LoseResponses makes the next n successful sendTransaction calls fail with a gateway
+// timeout after the transaction landed.
+func (s *Server) LoseResponses(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loseResponses = n
+}
+
+// LatestBlockhash returns the blockhash of the current slot, for transactions built by hand.
+func (s *Server) LatestBlockhash() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blockhash
+}
+
+// Slot returns the current slot.
+func (s *Server) Slot() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.slot
+}
+
+// advanceSlot must be called with mu held.
+func (s *Server) advanceSlot() {
+	s.slot++
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], s.slot)
+	h := sha256.Sum256(append([]byte("solanatest blockhash"), seed[:]...))
+	s.blockhash = base58.Encode(h[:])
+	s.blockhashes[s.blockhash] = s.slot + blockhashValidity
+}
+
+// blockhashValid must be called with mu held.
+func (s *Server) blockhashValid(hash string) bool {
+	last, ok := s.blockhashes[hash]
+	return ok && last >= s.slot
+}
+
+// RentExemptMinimum mirrors the cluster rent formula for an account of dataLen bytes.
+func RentExemptMinimum(dataLen uint64) uint64 {
+	return (128 + dataLen) * 3480 * 2
+}
+
+type rpcRequest struct {
+	JsonRpc string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcResponse struct {
+	JsonRpc string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+func invalidParams(format string, args ...any) *rpcError {
+	return &rpcError{Code: -32602, Message: fmt.Sprintf(format, args...)}
+}
+
+type rpcHandler func(params []json.RawMessage) (any, *rpcError)
+
+type rpcContext struct {
+	Slot uint64 `json:"slot"`
+}
+
+type valueWithContext struct {
+	Context rpcContext `json:"context"`
+	Value   any        `json:"value"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req rpcRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, rpcResponse{JsonRpc: "2.0", Error: &rpcError{Code: -32700, Message: "Parse error"}})
+		return
+	}
+	res := rpcResponse{JsonRpc: "2.0", ID: req.ID}
+	handler, ok := s.methods[req.Method]
+	if !ok {
+		res.Error = &rpcError{Code: -32601, Message: "Method not found"}
+		writeJSON(w, res)
+		return
+	}
+	result, rpcErr := handler(req.Params)
+	if req.Method == "sendTransaction" && rpcErr == nil && s.loseResponse() {
+		http.Error(w, "gateway timeout", http.StatusGatewayTimeout)
+		return
+	}
+	if rpcErr != nil {
+		res.Error = rpcErr
+	} else {
+		res.Result = result
+	}
+	writeJSON(w, res)
+}
+
+func (s *Server) loseResponse() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loseResponses == 0 {
+		return false
+	}
+	s.loseResponses--
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func decodeParam(params []json.RawMessage, i int, v any) *rpcError {
+	if len(params) <= i {
+		return invalidParams("missing parameter %d", i)
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return invalidParams("invalid parameter %d: %v", i, err)
+	}
+	return nil
+}
+
+func decodePublicKey(params []json.RawMessage, i int) (common.PublicKey, *rpcError) {
+	var s string
+	if err := decodeParam(params, i, &s); err != nil {
+		return common.PublicKey{}, err
+	}
+	return parsePublicKey(s)
+}
+
+func parsePublicKey(s string) (common.PublicKey, *rpcError) {
+	b, err := base58.Decode(s)
+	if err != nil || len(b) != common.PublicKeyLength {
+		return common.PublicKey{}, invalidParams("Invalid param: Invalid")
+	}
+	return common.PublicKeyFromBytes(b), nil
+}
@@ -0,0 +1,115 @@
+package solanatest
+
+import (
+	"encoding/base64"
+
+	"github.com/blocto/solana-go-sdk/types"
+	json "github.com/goccy/go-json"
+)
+
+type simulateTransactionConfig struct {
+	Encoding               string `json:"encoding"`
+	SigVerify              bool   `json:"sigVerify"`
+	ReplaceRecentBlockhash bool   `json:"replaceRecentBlockhash"`
+	Accounts               *struct {
+		Addresses []string `json:"addresses"`
+	} `json:"accounts"`
+}
+
+// simulateTransaction executes a transaction without committing it and reports
+// its logs, units and, on request, the accounts as they would be afterwards.
+func (s *Server) simulateTransaction(params []json.RawMessage) (any, *rpcError) {
+	var raw string
+	if err := decodeParam(params, 0, &raw); err != nil {
+		return nil, err
+	}
+	var cfg simulateTransactionConfig
+	if len(params) > 1 {
+		if err := decodeParam(params, 1, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SigVerify && cfg.ReplaceRecentBlockhash {
+		return nil, invalidParams("sigVerify may not be used with replaceRecentBlockhash")
+	}
+	tx, rpcErr := decodeWireTransaction(raw, cfg.Encoding)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cfg.ReplaceRecentBlockhash {
+		tx.Message.RecentBlockHash = s.blockhash
+	}
+	ex, rpcErr := s.execute(tx, cfg.SigVerify)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	value := map[string]any{
+		"err":           ex.err,
+		"logs":          ex.logs,
+		"accounts":      nil,
+		"unitsConsumed": ex.units,
+		"returnData":    nil,
+	}
+	if ex.rejected {
+		// the transaction never reached execution
+		value["logs"] = []string{}
+	} else if cfg.Accounts != nil && ex.err == nil {
+		accounts := make([]*accountInfoJSON, 0, len(cfg.Accounts.Addresses))
+		for _, addr := range cfg.Accounts.Addresses {
+			key, err := parsePublicKey(addr)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, toAccountInfoJSON(ex.bank.peek(key)))
+		}
+		value["accounts"] = accounts
+	}
+	return valueWithContext{Context: rpcContext{Slot: s.slot}, Value: value}, nil
+}
+
+// getFeeForMessage quotes signature and priority fees; the value is null once the
+// message blockhash has expired.
+func (s *Server) getFeeForMessage(params []json.RawMessage) (any, *rpcError) {
+	var raw string
+	if err := decodeParam(params, 0, &raw); err != nil {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalidParams("invalid message encoding: %v", err)
+	}
+	msg, err := types.MessageDeserialize(b)
+	if err != nil {
+		return nil, invalidParams("failed to deserialize message: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, durable := s.nonceAccount(msg); !durable && !s.blockhashValid(msg.RecentBlockHash) {
+		return valueWithContext{Context: rpcContext{Slot: s.slot}, Value: nil}, nil
+	}
+	fee := uint64(msg.Header.NumRequireSignatures)*LamportsPerSignature + parseComputeBudget(msg).priorityFee()
+	return valueWithContext{Context: rpcContext{Slot: s.slot}, Value: fee}, nil
+}
+
+func (s *Server) getMultipleAccounts(params []json.RawMessage) (any, *rpcError) {
+	var addrs []string
+	if err := decodeParam(params, 0, &addrs); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	accounts := make([]*accountInfoJSON, 0, len(addrs))
+	for _, addr := range addrs {
+		key, err := parsePublicKey(addr)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, toAccountInfoJSON(s.accounts[key]))
+	}
+	return valueWithContext{Context: rpcContext{Slot: s.slot}, Value: accounts}, nil
+}
@@ -0,0 +1,724 @@
+package solanatest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	json "github.com/goccy/go-json"
+)
+
+// A stake account holds a StakeStateV2: a u32 tag, the Meta (rent-exempt reserve,
+// staker, withdrawer and lockup) and, once delegated, the Delegation.
+const (
+	stakeAccountSize = 200
+
+	stakeStateUninitialized = 0
+	stakeStateInitialized   = 1
+	stakeStateStake         = 2
+)
+
+// voteAccountSize is the size of a vote account; only its commission is read.
+const voteAccountSize = 3762
+
+// Stake program custom errors.
+const (
+	stakeErrLockupInForce          = 1
+	stakeErrAlreadyDeactivated     = 2
+	stakeErrTooSoonToRedelegate    = 3
+	stakeErrInsufficientStake      = 4
+	stakeErrMergeTransientStake    = 5
+	stakeErrMergeMismatch          = 6
+	stakeErrInsufficientDelegation = 12
+)
+
+const (
+	// StakeRewardBasisPoints is the reward an effective stake earns per epoch, before the
+	// commission of its validator.
+	StakeRewardBasisPoints = 10
+
+	// bootstrapStake is the effective stake of the cluster's own validator. It lets
+	// delegations warm up and cool down within an epoch, as they do on a large cluster.
+	bootstrapStake = 400_000_000 * 1_000_000_000
+
+	// warmupCooldownRate is the share of the effective cluster stake that may activate
+	// or deactivate per epoch.
+	warmupCooldownRate = 0.09
+
+	stakeHistoryCapacity = 512
+	stakeHistorySize     = 8 + stakeHistoryCapacity*32
+
+	// noEpoch marks a delegation that is not deactivating.
+	noEpoch = math.MaxUint64
+)
+
+type stakeLockup struct {
+	unixTimestamp int64
+	epoch         uint64
+	custodian     common.PublicKey
+}
+
+type stakeState struct {
+	tag        uint32
+	reserve    uint64
+	staker     common.PublicKey
+	withdrawer common.PublicKey
+	lockup     stakeLockup
+	// the delegation is set when tag is stakeStateStake
+	voter             common.PublicKey
+	stake             uint64
+	activationEpoch   uint64
+	deactivationEpoch uint64
+}
+
+func unpackStake(data []byte) (stakeState, bool) {
+	if len(data) != stakeAccountSize {
+		return stakeState{}, false
+	}
+	st := stakeState{tag: binary.LittleEndian.Uint32(data[0:4])}
+	switch st.tag {
+	case stakeStateUninitialized:
+		return st, true
+	case stakeStateInitialized, stakeStateStake:
+	default:
+		return stakeState{}, false
+	}
+	st.reserve = binary.LittleEndian.Uint64(data[4:12])
+	st.staker = common.PublicKeyFromBytes(data[12:44])
+	st.withdrawer = common.PublicKeyFromBytes(data[44:76])
+	st.lockup = stakeLockup{
+		unixTimestamp: int64(binary.LittleEndian.Uint64(data[76:84])),
+		epoch:         binary.LittleEndian.Uint64(data[84:92]),
+		custodian:     common.PublicKeyFromBytes(data[92:124]),
+	}
+	if st.tag == stakeStateStake {
+		st.voter = common.PublicKeyFromBytes(data[124:156])
+		st.stake = binary.LittleEndian.Uint64(data[156:164])
+		st.activationEpoch = binary.LittleEndian.Uint64(data[164:172])
+		st.deactivationEpoch = binary.LittleEndian.Uint64(data[172:180])
+	}
+	return st, true
+}
+
+func packStake(data []byte, st stakeState) {
+	clear(data)
+	binary.LittleEndian.PutUint32(data[0:4], st.tag)
+	if st.tag == stakeStateUninitialized {
+		return
+	}
+	putUint64(data[4:12], st.reserve)
+	copy(data[12:44], st.staker.Bytes())
+	copy(data[44:76], st.withdrawer.Bytes())
+	putUint64(data[76:84], uint64(st.lockup.unixTimestamp))
+	putUint64(data[84:92], st.lockup.epoch)
+	copy(data[92:124], st.lockup.custodian.Bytes())
+	if st.tag == stakeStateStake {
+		copy(data[124:156], st.voter.Bytes())
+		putUint64(data[156:164], st.stake)
+		putUint64(data[164:172], st.activationEpoch)
+		putUint64(data[172:180], st.deactivationEpoch)
+		// the deprecated warmup_cooldown_rate f64 keeps its historical value
+		putUint64(data[180:188], math.Float64bits(0.25))
+	}
+}
+
+type stakeHistoryEntry struct {
+	epoch        uint64
+	effective    uint64
+	activating   uint64
+	deactivating uint64
+}
+
+// stakeHistory lists the cluster stake of past epochs, newest first.
+type stakeHistory []stakeHistoryEntry
+
+func (h stakeHistory) get(epoch uint64) (stakeHistoryEntry, bool) {
+	for _, e := range h {
+		if e.epoch == epoch {
+			return e, true
+		}
+	}
+	return stakeHistoryEntry{}, false
+}
+
+func unpackStakeHistory(data []byte) stakeHistory {
+	if len(data) < 8 {
+		return nil
+	}
+	n := binary.LittleEndian.Uint64(data[0:8])
+	var h stakeHistory
+	for i := uint64(0); i < n && 8+(i+1)*32 <= uint64(len(data)); i++ {
+		b := data[8+i*32:]
+		h = append(h, stakeHistoryEntry{
+			epoch:        binary.LittleEndian.Uint64(b[0:8]),
+			effective:    binary.LittleEndian.Uint64(b[8:16]),
+			activating:   binary.LittleEndian.Uint64(b[16:24]),
+			deactivating: binary.LittleEndian.Uint64(b[24:32]),
+		})
+	}
+	return h
+}
+
+func packStakeHistory(h stakeHistory) []byte {
+	data := make([]byte, stakeHistorySize)
+	putUint64(data[0:8], uint64(len(h)))
+	for i, e := range h {
+		b := data[8+i*32:]
+		putUint64(b[0:8], e.epoch)
+		putUint64(b[8:16], e.effective)
+		putUint64(b[16:24], e.activating)
+		putUint64(b[24:32], e.deactivating)
+	}
+	return data
+}
+
+// status returns the effective, activating and deactivating stake of a delegation in an
+// epoch, warming up and cooling down against the cluster stake as the stake program does.
+func (st stakeState) status(epoch uint64, history stakeHistory) (effective, activating, deactivating uint64) {
+	effective, activating = st.effectiveAndActivating(epoch, history)
+	switch {
+	case epoch < st.deactivationEpoch:
+		return effective, activating, 0
+	case epoch == st.deactivationEpoch:
+		return effective, 0, effective
+	}
+	prev, ok := history.get(st.deactivationEpoch)
+	if !ok {
+		return 0, 0, 0
+	}
+	for current := st.deactivationEpoch + 1; prev.deactivating > 0; current++ {
+		weight := float64(effective) / float64(prev.deactivating)
+		cooled := max(uint64(weight*float64(prev.effective)*warmupCooldownRate), 1)
+		if cooled >= effective {
+			return 0, 0, 0
+		}
+		effective -= cooled
+		if current >= epoch {
+			break
+		}
+		if prev, ok = history.get(current); !ok {
+			return 0, 0, 0
+		}
+	}
+	return effective, 0, effective
+}
+
+func (st stakeState) effectiveAndActivating(epoch uint64, history stakeHistory) (uint64, uint64) {
+	switch {
+	case st.activationEpoch == st.deactivationEpoch || epoch < st.activationEpoch:
+		return 0, 0
+	case epoch == st.activationEpoch:
+		return 0, st.stake
+	}
+	prev, ok := history.get(st.activationEpoch)
+	if !ok {
+		return st.stake, 0
+	}
+	var effective uint64
+	for current := st.activationEpoch + 1; prev.activating > 0; current++ {
+		weight := float64(st.stake-effective) / float64(prev.activating)
+		effective += max(uint64(weight*float64(prev.effective)*warmupCooldownRate), 1)
+		if effective >= st.stake {
+			return st.stake, 0
+		}
+		if current >= epoch || current >= st.deactivationEpoch {
+			break
+		}
+		if prev, ok = history.get(current); !ok {
+			break
+		}
+	}
+	return effective, st.stake - effective
+}
+
+// inflationReward is an entry of getInflationReward.
+type inflationReward struct {
+	Epoch         uint64 `json:"epoch"`
+	EffectiveSlot uint64 `json:"effectiveSlot"`
+	Amount        uint64 `json:"amount"`
+	PostBalance   uint64 `json:"postBalance"`
+	Commission    uint8  `json:"commission"`
+}
+
+// CreateVoteAccount stores a vote account stakes can be delegated to and returns its
+// address. The commission, in percent, is taken from the rewards of its delegations.
+func (s *Server) CreateVoteAccount(commission uint8) string {
+	vote := types.NewAccount()
+	data := make([]byte, voteAccountSize)
+	binary.LittleEndian.PutUint32(data[0:4], 2) // VoteStateVersions::Current
+	copy(data[4:36], vote.PublicKey.Bytes())    // node
+	copy(data[36:68], vote.PublicKey.Bytes())   // authorized withdrawer
+	data[68] = commission
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[vote.PublicKey] = &Account{Lamports: RentExemptMinimum(voteAccountSize), Owner: common.VoteProgramID, Data: data}
+	return vote.PublicKey.ToBase58()
+}
+
+// AdvanceEpochs moves the cluster to the first slot of the epoch n epochs ahead. At each
+// boundary it records the stake history of the ending epoch and pays the stake rewards
+// earned in it, which getInflationReward then reports.
+func (s *Server) AdvanceEpochs(n uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := uint64(0); i < n; i++ {
+		s.endEpoch()
+		s.slot = (epochOf(s.slot)+1)*SlotsPerEpoch - 1
+		s.advanceSlot()
+	}
+}
+
+// endEpoch must be called with mu held.
+func (s *Server) endEpoch() {
+	epoch := epochOf(s.slot)
+	sysvar := s.accounts[common.SysVarStakeHistoryPubkey]
+	history := unpackStakeHistory(sysvar.Data)
+
+	type earner struct {
+		key       common.PublicKey
+		acc       *Account
+		st        stakeState
+		effective uint64
+	}
+	var earners []earner
+	entry := stakeHistoryEntry{epoch: epoch, effective: bootstrapStake}
+	for key, acc := range s.accounts {
+		if acc.Owner != common.StakeProgramID {
+			continue
+		}
+		st, ok := unpackStake(acc.Data)
+		if !ok || st.tag != stakeStateStake {
+			continue
+		}
+		effective, activating, deactivating := st.status(epoch, history)
+		entry.effective += effective
+		entry.activating += activating
+		entry.deactivating += deactivating
+		if effective > 0 {
+			earners = append(earners, earner{key: key, acc: acc, st: st, effective: effective})
+		}
+	}
+	history = append(stakeHistory{entry}, history...)
+	if len(history) > stakeHistoryCapacity {
+		history = history[:stakeHistoryCapacity]
+	}
+	sysvar.Data = packStakeHistory(history)
+
+	rewards := map[common.PublicKey]inflationReward{}
+	for _, e := range earners {
+		reward := e.effective * StakeRewardBasisPoints / 10_000
+		var commission uint8
+		vote := s.accounts[e.st.voter]
+		if vote != nil && vote.Owner == common.VoteProgramID && len(vote.Data) == voteAccountSize {
+			commission = vote.Data[68]
+		}
+		voterShare := reward * uint64(commission) / 100
+		reward -= voterShare
+		if vote != nil {
+			vote.Lamports += voterShare
+		}
+		if reward == 0 {
+			continue
+		}
+		e.acc.Lamports += reward
+		e.st.stake += reward
+		packStake(e.acc.Data, e.st)
+		rewards[e.key] = inflationReward{
+			Epoch:         epoch,
+			EffectiveSlot: (epoch + 1) * SlotsPerEpoch,
+			Amount:        reward,
+			PostBalance:   e.acc.Lamports,
+			Commission:    commission,
+		}
+	}
+	s.rewards[epoch] = rewards
+}
+
+type inflationRewardConfig struct {
+	Epoch *uint64 `json:"epoch"`
+}
+
+// getInflationReward reports the stake rewards of an epoch, the last completed one by default.
+func (s *Server) getInflationReward(params []json.RawMessage) (any, *rpcError) {
+	var addresses []string
+	if err := decodeParam(params, 0, &addresses); err != nil {
+		return nil, err
+	}
+	var cfg inflationRewardConfig
+	if len(params) > 1 {
+		if err := decodeParam(params, 1, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := epochOf(s.slot)
+	epoch := current - 1
+	if cfg.Epoch != nil {
+		epoch = *cfg.Epoch
+	}
+	if current == 0 || epoch >= current {
+		return nil, &rpcError{Code: -32004, Message: fmt.Sprintf("Block not available for slot %d", epoch*SlotsPerEpoch)}
+	}
+	result := make([]any, len(addresses))
+	for i, address := range addresses {
+		key, rpcErr := parsePublicKey(address)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		if r, ok := s.rewards[epoch][key]; ok {
+			result[i] = r
+		}
+	}
+	return result, nil
+}
+
+func processStake(inv *invocation) error {
+	if len(inv.data) < 4 {
+		return builtinErr("InvalidInstructionData")
+	}
+	data := inv.data[4:]
+	epoch := epochOf(inv.slot)
+	switch binary.LittleEndian.Uint32(inv.data[:4]) {
+	case 0: // Initialize
+		if len(data) < 32+32+8+8+32 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return stakeInitialize(inv, stakeState{
+			tag:        stakeStateInitialized,
+			staker:     common.PublicKeyFromBytes(data[0:32]),
+			withdrawer: common.PublicKeyFromBytes(data[32:64]),
+			lockup: stakeLockup{
+				unixTimestamp: int64(binary.LittleEndian.Uint64(data[64:72])),
+				epoch:         binary.LittleEndian.Uint64(data[72:80]),
+				custodian:     common.PublicKeyFromBytes(data[80:112]),
+			},
+		})
+	case 2: // DelegateStake
+		return stakeDelegate(inv, epoch)
+	case 3: // Split
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return stakeSplit(inv, binary.LittleEndian.Uint64(data[:8]), epoch)
+	case 4: // Withdraw
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return stakeWithdraw(inv, binary.LittleEndian.Uint64(data[:8]), epoch)
+	case 5: // Deactivate
+		return stakeDeactivate(inv, epoch)
+	case 7: // Merge
+		return stakeMerge(inv, epoch)
+	default:
+		return builtinErr("InvalidInstructionData")
+	}
+}
+
+// stakeAccount loads the i-th instruction account as a writable stake account.
+func (inv *invocation) stakeAccount(i int) (*Account, stakeState, error) {
+	acc, err := inv.mutable(i)
+	if err != nil {
+		return nil, stakeState{}, err
+	}
+	if acc.Owner != common.StakeProgramID {
+		return nil, stakeState{}, builtinErr("InvalidAccountOwner")
+	}
+	st, ok := unpackStake(acc.Data)
+	if !ok {
+		return nil, stakeState{}, builtinErr("InvalidAccountData")
+	}
+	return acc, st, nil
+}
+
+// requireAuthority checks that authority signed as any account of the instruction.
+func (inv *invocation) requireAuthority(authority common.PublicKey) error {
+	for i, key := range inv.keys {
+		if key == authority && inv.signers[i] {
+			return nil
+		}
+	}
+	inv.log("Stake authority %s did not sign", authority.ToBase58())
+	return builtinErr("MissingRequiredSignature")
+}
+
+// stakeHistory reads the stake history sysvar.
+func (inv *invocation) stakeHistory() stakeHistory {
+	return unpackStakeHistory(inv.bank.get(common.SysVarStakeHistoryPubkey).Data)
+}
+
+func stakeInitialize(inv *invocation, st stakeState) error {
+	acc, current, err := inv.stakeAccount(0)
+	if err != nil {
+		return err
+	}
+	if current.tag != stakeStateUninitialized {
+		return builtinErr("InvalidAccountData")
+	}
+	st.reserve = RentExemptMinimum(stakeAccountSize)
+	if acc.Lamports < st.reserve {
+		return builtinErr("InsufficientFunds")
+	}
+	packStake(acc.Data, st)
+	return nil
+}
+
+func stakeDelegate(inv *invocation, epoch uint64) error {
+	acc, st, err := inv.stakeAccount(0)
+	if err != nil {
+		return err
+	}
+	vote, err := inv.account(1)
+	if err != nil {
+		return err
+	}
+	if vote.Owner != common.VoteProgramID {
+		return builtinErr("IncorrectProgramId")
+	}
+	voter := inv.keys[1]
+	if st.tag == stakeStateUninitialized {
+		return builtinErr("InvalidAccountData")
+	}
+	if err := inv.requireAuthority(st.staker); err != nil {
+		return err
+	}
+	if st.tag == stakeStateStake {
+		effective, _, _ := st.status(epoch, inv.stakeHistory())
+		if effective != 0 {
+			// the only change allowed while stake is effective is rescinding a deactivation
+			// of this epoch
+			if st.voter != voter || st.deactivationEpoch != epoch {
+				return customErr(stakeErrTooSoonToRedelegate)
+			}
+			st.deactivationEpoch = noEpoch
+			packStake(acc.Data, st)
+			return nil
+		}
+	}
+	stake := acc.Lamports - st.reserve
+	if acc.Lamports < st.reserve || stake == 0 {
+		return customErr(stakeErrInsufficientDelegation)
+	}
+	st.tag = stakeStateStake
+	st.voter = voter
+	st.stake = stake
+	st.activationEpoch = epoch
+	st.deactivationEpoch = noEpoch
+	packStake(acc.Data, st)
+	return nil
+}
+
+func stakeDeactivate(inv *invocation, epoch uint64) error {
+	acc, st, err := inv.stakeAccount(0)
+	if err != nil {
+		return err
+	}
+	if st.tag != stakeStateStake {
+		return builtinErr("InvalidAccountData")
+	}
+	if err := inv.requireAuthority(st.staker); err != nil {
+		return err
+	}
+	if st.deactivationEpoch != noEpoch {
+		return customErr(stakeErrAlreadyDeactivated)
+	}
+	st.deactivationEpoch = epoch
+	packStake(acc.Data, st)
+	return nil
+}
+
+func stakeWithdraw(inv *invocation, lamports uint64, epoch uint64) error {
+	acc, st, err := inv.stakeAccount(0)
+	if err != nil {
+		return err
+	}
+	to, err := inv.mutable(1)
+	if err != nil {
+		return err
+	}
+	var reserve, staked uint64
+	switch st.tag {
+	case stakeStateUninitialized:
+		if err := inv.requireSigner(0); err != nil {
+			return err
+		}
+	case stakeStateStake:
+		staked = st.stake
+		if epoch >= st.deactivationEpoch {
+			staked, _, _ = st.status(epoch, inv.stakeHistory())
+		}
+		fallthrough
+	default:
+		if err := inv.requireAuthority(st.withdrawer); err != nil {
+			return err
+		}
+		if st.lockupInForce(epoch) && inv.requireAuthority(st.lockup.custodian) != nil {
+			inv.log("Withdraw: lockup is in force until epoch %d", st.lockup.epoch)
+			return customErr(stakeErrLockupInForce)
+		}
+		reserve = st.reserve + staked
+	}
+	switch {
+	case lamports > acc.Lamports:
+		return builtinErr("InsufficientFunds")
+	case lamports == acc.Lamports:
+		if staked > 0 {
+			inv.log("Withdraw: %d lamports are still staked", staked)
+			return builtinErr("InsufficientFunds")
+		}
+		clear(acc.Data)
+	case acc.Lamports-lamports < reserve:
+		inv.log("Withdraw: insufficient lamports %d, need %d", acc.Lamports-lamports, reserve)
+		return builtinErr("InsufficientFunds")
+	}
+	acc.Lamports -= lamports
+	to.Lamports += lamports
+	return nil
+}
+
+func (st stakeState) lockupInForce(epoch uint64) bool {
+	return st.lockup.unixTimestamp > time.Now().Unix() || st.lockup.epoch > epoch
+}
+
+// stakeSplit moves lamports, and for a delegation as much stake, into an uninitialized stake
+// account funded with its rent-exempt reserve.
+func stakeSplit(inv *invocation, lamports uint64, epoch uint64) error {
+	source, st, err := inv.stakeAccount(0)
+	if err != nil {
+		return err
+	}
+	split, splitState, err := inv.stakeAccount(1)
+	if err != nil {
+		return err
+	}
+	if inv.keys[0] == inv.keys[1] {
+		return builtinErr("InvalidArgument")
+	}
+	if splitState.tag != stakeStateUninitialized {
+		return builtinErr("InvalidAccountData")
+	}
+	if st.tag == stakeStateUninitialized {
+		return builtinErr("InvalidAccountData")
+	}
+	if err := inv.requireAuthority(st.staker); err != nil {
+		return err
+	}
+	if lamports == 0 || lamports > source.Lamports {
+		return builtinErr("InsufficientFunds")
+	}
+	splitReserve := RentExemptMinimum(stakeAccountSize)
+	if split.Lamports+lamports < splitReserve {
+		inv.log("Split: destination would hold %d lamports, need %d", split.Lamports+lamports, splitReserve)
+		return builtinErr("InsufficientFunds")
+	}
+
+	splitState = st
+	splitState.reserve = splitReserve
+	if st.tag == stakeStateStake {
+		if split.Lamports < splitReserve {
+			inv.log("Split: destination must hold its rent-exempt reserve")
+			return builtinErr("InsufficientFunds")
+		}
+		if lamports >= st.stake {
+			inv.log("Split: %d lamports is not less than the stake %d", lamports, st.stake)
+			return customErr(stakeErrInsufficientStake)
+		}
+		st.stake -= lamports
+		splitState.stake = lamports
+		packStake(source.Data, st)
+	} else if lamports < source.Lamports && source.Lamports-lamports < st.reserve {
+		return builtinErr("InsufficientFunds")
+	}
+	if lamports == source.Lamports {
+		clear(source.Data)
+	}
+	packStake(split.Data, splitState)
+	source.Lamports -= lamports
+	split.Lamports += lamports
+	return nil
+}
+
+type mergeKind int
+
+const (
+	mergeInactive mergeKind = iota
+	mergeActivationEpoch
+	mergeFullyActive
+)
+
+func (st stakeState) mergeKind(epoch uint64, history stakeHistory) (mergeKind, error) {
+	if st.tag == stakeStateInitialized {
+		return mergeInactive, nil
+	}
+	if st.tag != stakeStateStake {
+		return 0, builtinErr("InvalidAccountData")
+	}
+	effective, activating, deactivating := st.status(epoch, history)
+	switch {
+	case effective == 0 && activating == 0 && deactivating == 0:
+		return mergeInactive, nil
+	case effective == 0:
+		return mergeActivationEpoch, nil
+	case activating == 0 && deactivating == 0:
+		return mergeFullyActive, nil
+	default:
+		return 0, customErr(stakeErrMergeTransientStake)
+	}
+}
+
+// stakeMerge drains the source stake account into the destination; both must share their
+// authorities and lockup and be in compatible states.
+func stakeMerge(inv *invocation, epoch uint64) error {
+	dest, st, err := inv.stakeAccount(0)
+	if err != nil {
+		return err
+	}
+	source, sourceState, err := inv.stakeAccount(1)
+	if err != nil {
+		return err
+	}
+	if inv.keys[0] == inv.keys[1] {
+		return builtinErr("InvalidArgument")
+	}
+	if st.tag == stakeStateUninitialized {
+		return builtinErr("InvalidAccountData")
+	}
+	if err := inv.requireAuthority(st.staker); err != nil {
+		return err
+	}
+	if st.staker != sourceState.staker || st.withdrawer != sourceState.withdrawer || st.lockup != sourceState.lockup {
+		inv.log("Merge: authorities or lockups differ")
+		return customErr(stakeErrMergeMismatch)
+	}
+	history := inv.stakeHistory()
+	destKind, err := st.mergeKind(epoch, history)
+	if err != nil {
+		return err
+	}
+	sourceKind, err := sourceState.mergeKind(epoch, history)
+	if err != nil {
+		return err
+	}
+	sameDelegation := st.voter == sourceState.voter && st.deactivationEpoch == sourceState.deactivationEpoch
+	switch {
+	case destKind == mergeInactive && sourceKind != mergeFullyActive:
+	case destKind == mergeActivationEpoch && sourceKind == mergeInactive:
+		st.stake += source.Lamports
+	case destKind == mergeActivationEpoch && sourceKind == mergeActivationEpoch && sameDelegation:
+		st.stake += sourceState.reserve + sourceState.stake
+	case destKind == mergeFullyActive && sourceKind == mergeFullyActive && sameDelegation:
+		st.stake += sourceState.stake
+	default:
+		inv.log("Merge: stake states or delegations differ")
+		return customErr(stakeErrMergeMismatch)
+	}
+	packStake(dest.Data, st)
+	clear(source.Data)
+	dest.Lamports += source.Lamports
+	source.Lamports = 0
+	return nil
+}
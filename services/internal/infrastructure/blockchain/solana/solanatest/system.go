@@ -0,0 +1,212 @@
+package solanatest
+
+import (
+	"encoding/binary"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+// System program custom errors.
+const (
+	systemErrAccountAlreadyInUse        = 0
+	systemErrResultWithNegativeLamports = 1
+	systemErrMaxSeedLengthExceeded      = 4
+	systemErrAddressWithSeedMismatch    = 5
+)
+
+// maxSeedLength is the longest seed an address can be derived with.
+const maxSeedLength = 32
+
+func processSystem(inv *invocation) error {
+	if len(inv.data) < 4 {
+		return builtinErr("InvalidInstructionData")
+	}
+	data := inv.data[4:]
+	switch binary.LittleEndian.Uint32(inv.data[:4]) {
+	case 0: // CreateAccount
+		if len(data) < 8+8+32 {
+			return builtinErr("InvalidInstructionData")
+		}
+		lamports := binary.LittleEndian.Uint64(data[0:8])
+		space := binary.LittleEndian.Uint64(data[8:16])
+		owner := common.PublicKeyFromBytes(data[16:48])
+		return systemCreateAccount(inv, lamports, space, owner)
+	case 1: // Assign
+		if len(data) < 32 {
+			return builtinErr("InvalidInstructionData")
+		}
+		if err := inv.requireSigner(0); err != nil {
+			return err
+		}
+		acc, err := inv.mutable(0)
+		if err != nil {
+			return err
+		}
+		if acc.Owner != common.SystemProgramID {
+			return builtinErr("ModifiedProgramId")
+		}
+		acc.Owner = common.PublicKeyFromBytes(data[:32])
+		return nil
+	case 2: // Transfer
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return systemTransfer(inv, binary.LittleEndian.Uint64(data[:8]))
+	case 3: // CreateAccountWithSeed
+		return systemCreateAccountWithSeed(inv, data)
+	case 4: // AdvanceNonceAccount
+		return systemAdvanceNonce(inv)
+	case 5: // WithdrawNonceAccount
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return systemWithdrawNonce(inv, binary.LittleEndian.Uint64(data[:8]))
+	case 6: // InitializeNonceAccount
+		if len(data) < 32 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return systemInitializeNonce(inv, common.PublicKeyFromBytes(data[:32]))
+	case 7: // AuthorizeNonceAccount
+		if len(data) < 32 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return systemAuthorizeNonce(inv, common.PublicKeyFromBytes(data[:32]))
+	case 8: // Allocate
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		if err := inv.requireSigner(0); err != nil {
+			return err
+		}
+		acc, err := inv.mutable(0)
+		if err != nil {
+			return err
+		}
+		if len(acc.Data) != 0 || acc.Owner != common.SystemProgramID {
+			return customErr(systemErrAccountAlreadyInUse)
+		}
+		acc.Data = make([]byte, binary.LittleEndian.Uint64(data[:8]))
+		return nil
+	default:
+		return builtinErr("InvalidInstructionData")
+	}
+}
+
+func systemCreateAccount(inv *invocation, lamports, space uint64, owner common.PublicKey) error {
+	if err := inv.requireSigner(0); err != nil {
+		return err
+	}
+	if err := inv.requireSigner(1); err != nil {
+		return err
+	}
+	from, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	to, err := inv.mutable(1)
+	if err != nil {
+		return err
+	}
+	if to.Lamports > 0 || len(to.Data) > 0 || to.Owner != common.SystemProgramID {
+		inv.log("Create Account: account %s already in use", inv.keys[1].ToBase58())
+		return customErr(systemErrAccountAlreadyInUse)
+	}
+	if from.Lamports < lamports {
+		inv.log("Transfer: insufficient lamports %d, need %d", from.Lamports, lamports)
+		return customErr(systemErrResultWithNegativeLamports)
+	}
+	from.Lamports -= lamports
+	to.Lamports += lamports
+	to.Data = make([]byte, space)
+	to.Owner = owner
+	return nil
+}
+
+// systemCreateAccountWithSeed creates an account at the address derived from a base key, a
+// seed and the owner; the base signs in place of the new account.
+func systemCreateAccountWithSeed(inv *invocation, data []byte) error {
+	// base pubkey, seed as u64 length and bytes, lamports u64, space u64, owner pubkey
+	if len(data) < 32+8 {
+		return builtinErr("InvalidInstructionData")
+	}
+	base := common.PublicKeyFromBytes(data[0:32])
+	n := binary.LittleEndian.Uint64(data[32:40])
+	if uint64(len(data)-40) < n+8+8+32 {
+		return builtinErr("InvalidInstructionData")
+	}
+	seed := string(data[40 : 40+n])
+	rest := data[40+n:]
+	lamports := binary.LittleEndian.Uint64(rest[0:8])
+	space := binary.LittleEndian.Uint64(rest[8:16])
+	owner := common.PublicKeyFromBytes(rest[16:48])
+	if len(seed) > maxSeedLength {
+		return customErr(systemErrMaxSeedLengthExceeded)
+	}
+
+	if err := inv.requireSigner(0); err != nil {
+		return err
+	}
+	to, err := inv.key(1)
+	if err != nil {
+		return err
+	}
+	if common.CreateWithSeed(base, seed, owner) != to {
+		inv.log("Create: address %s does not match derived address", to.ToBase58())
+		return customErr(systemErrAddressWithSeedMismatch)
+	}
+	baseSigned := false
+	for i, key := range inv.keys {
+		if key == base && inv.signers[i] {
+			baseSigned = true
+		}
+	}
+	if !baseSigned {
+		return builtinErr("MissingRequiredSignature")
+	}
+	from, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	acc, err := inv.mutable(1)
+	if err != nil {
+		return err
+	}
+	if acc.Lamports > 0 || len(acc.Data) > 0 || acc.Owner != common.SystemProgramID {
+		inv.log("Create Account: account %s already in use", to.ToBase58())
+		return customErr(systemErrAccountAlreadyInUse)
+	}
+	if from.Lamports < lamports {
+		inv.log("Transfer: insufficient lamports %d, need %d", from.Lamports, lamports)
+		return customErr(systemErrResultWithNegativeLamports)
+	}
+	from.Lamports -= lamports
+	acc.Lamports += lamports
+	acc.Data = make([]byte, space)
+	acc.Owner = owner
+	return nil
+}
+
+func systemTransfer(inv *invocation, lamports uint64) error {
+	if err := inv.requireSigner(0); err != nil {
+		return err
+	}
+	from, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	to, err := inv.mutable(1)
+	if err != nil {
+		return err
+	}
+	if len(from.Data) > 0 || from.Owner != common.SystemProgramID {
+		inv.log("Transfer: `from` must not carry data")
+		return builtinErr("InvalidArgument")
+	}
+	if from.Lamports < lamports {
+		inv.log("Transfer: insufficient lamports %d, need %d", from.Lamports, lamports)
+		return customErr(systemErrResultWithNegativeLamports)
+	}
+	from.Lamports -= lamports
+	to.Lamports += lamports
+	return nil
+}
@@ -0,0 +1,910 @@
+package solanatest
+
+import (
+	"encoding/binary"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+const (
+	mintSize         = 82
+	tokenAccountSize = 165
+	multisigSize     = 355
+
+	multisigMaxSigners = 11
+)
+
+// SPL Token custom errors.
+const (
+	tokenErrInsufficientFunds    = 1
+	tokenErrInvalidMint          = 2
+	tokenErrMintMismatch         = 3
+	tokenErrOwnerMismatch        = 4
+	tokenErrFixedSupply          = 5
+	tokenErrAlreadyInUse         = 6
+	tokenErrInvalidSignerCount   = 7
+	tokenErrInvalidRequired      = 8
+	tokenErrNonNativeHasBalance  = 11
+	tokenErrInvalidInstruction   = 12
+	tokenErrInvalidState         = 13
+	tokenErrOverflow             = 14
+	tokenErrAuthorityType        = 15
+	tokenErrMintCannotFreeze     = 16
+	tokenErrAccountFrozen        = 17
+	tokenErrMintDecimalsMismatch = 18
+)
+
+const (
+	accountStateUninitialized = 0
+	accountStateInitialized   = 1
+	accountStateFrozen        = 2
+)
+
+func isTokenProgram(program common.PublicKey) bool {
+	return program == common.TokenProgramID || program == common.Token2022ProgramID
+}
+
+type mintState struct {
+	mintAuthority   *common.PublicKey
+	supply          uint64
+	decimals        uint8
+	initialized     bool
+	freezeAuthority *common.PublicKey
+}
+
+type tokenAccountState struct {
+	mint            common.PublicKey
+	owner           common.PublicKey
+	amount          uint64
+	delegate        *common.PublicKey
+	state           uint8
+	isNative        *uint64
+	delegatedAmount uint64
+	closeAuthority  *common.PublicKey
+}
+
+func readOptionKey(b []byte) *common.PublicKey {
+	if binary.LittleEndian.Uint32(b[:4]) == 0 {
+		return nil
+	}
+	k := common.PublicKeyFromBytes(b[4:36])
+	return &k
+}
+
+func writeOptionKey(b []byte, k *common.PublicKey) {
+	for i := range b[:36] {
+		b[i] = 0
+	}
+	if k != nil {
+		b[0] = 1
+		copy(b[4:36], k.Bytes())
+	}
+}
+
+// hasAccountType reports whether data is a base layout of baseSize bytes or an
+// extended Token-2022 account of the given type.
+func hasAccountType(data []byte, baseSize int, accountType byte) bool {
+	return len(data) == baseSize || (len(data) > accountTypeOffset && data[accountTypeOffset] == accountType)
+}
+
+func unpackMint(data []byte) (mintState, bool) {
+	if len(data) < mintSize || data[45] == 0 || !hasAccountType(data, mintSize, accountTypeMint) {
+		return mintState{}, false
+	}
+	return mintState{
+		mintAuthority:   readOptionKey(data[0:36]),
+		supply:          binary.LittleEndian.Uint64(data[36:44]),
+		decimals:        data[44],
+		initialized:     true,
+		freezeAuthority: readOptionKey(data[46:82]),
+	}, true
+}
+
+func packMint(data []byte, m mintState) {
+	writeOptionKey(data[0:36], m.mintAuthority)
+	binary.LittleEndian.PutUint64(data[36:44], m.supply)
+	data[44] = m.decimals
+	data[45] = 0
+	if m.initialized {
+		data[45] = 1
+	}
+	writeOptionKey(data[46:82], m.freezeAuthority)
+}
+
+func unpackTokenAccount(data []byte) (tokenAccountState, bool) {
+	if len(data) < tokenAccountSize || data[108] == accountStateUninitialized || !hasAccountType(data, tokenAccountSize, accountTypeAccount) {
+		return tokenAccountState{}, false
+	}
+	ta := tokenAccountState{
+		mint:            common.PublicKeyFromBytes(data[0:32]),
+		owner:           common.PublicKeyFromBytes(data[32:64]),
+		amount:          binary.LittleEndian.Uint64(data[64:72]),
+		delegate:        readOptionKey(data[72:108]),
+		state:           data[108],
+		delegatedAmount: binary.LittleEndian.Uint64(data[121:129]),
+		closeAuthority:  readOptionKey(data[129:165]),
+	}
+	if binary.LittleEndian.Uint32(data[109:113]) == 1 {
+		v := binary.LittleEndian.Uint64(data[113:121])
+		ta.isNative = &v
+	}
+	return ta, true
+}
+
+func packTokenAccount(data []byte, ta tokenAccountState) {
+	copy(data[0:32], ta.mint.Bytes())
+	copy(data[32:64], ta.owner.Bytes())
+	binary.LittleEndian.PutUint64(data[64:72], ta.amount)
+	writeOptionKey(data[72:108], ta.delegate)
+	data[108] = ta.state
+	for i := 109; i < 121; i++ {
+		data[i] = 0
+	}
+	if ta.isNative != nil {
+		data[109] = 1
+		binary.LittleEndian.PutUint64(data[113:121], *ta.isNative)
+	}
+	binary.LittleEndian.PutUint64(data[121:129], ta.delegatedAmount)
+	writeOptionKey(data[129:165], ta.closeAuthority)
+}
+
+// loadMint reads the i-th instruction account as an initialized mint.
+func (inv *invocation) loadMint(i int) (*Account, mintState, error) {
+	acc, err := inv.account(i)
+	if err != nil {
+		return nil, mintState{}, err
+	}
+	if acc.Owner != inv.program {
+		return nil, mintState{}, builtinErr("IncorrectProgramId")
+	}
+	m, ok := unpackMint(acc.Data)
+	if !ok {
+		return nil, mintState{}, builtinErr("UninitializedAccount")
+	}
+	return acc, m, nil
+}
+
+// loadTokenAccount reads the i-th instruction account as an initialized token account.
+func (inv *invocation) loadTokenAccount(i int) (*Account, tokenAccountState, error) {
+	acc, err := inv.account(i)
+	if err != nil {
+		return nil, tokenAccountState{}, err
+	}
+	if acc.Owner != inv.program {
+		return nil, tokenAccountState{}, builtinErr("IncorrectProgramId")
+	}
+	ta, ok := unpackTokenAccount(acc.Data)
+	if !ok {
+		return nil, tokenAccountState{}, builtinErr("UninitializedAccount")
+	}
+	return acc, ta, nil
+}
+
+// validateOwner checks that the i-th instruction account is the expected
+// authority and signed the transaction. A multisig authority signs through
+// the accounts following it; each listed member counts once towards m.
+func (inv *invocation) validateOwner(expected common.PublicKey, i int) error {
+	key, err := inv.key(i)
+	if err != nil {
+		return err
+	}
+	if key != expected {
+		return customErr(tokenErrOwnerMismatch)
+	}
+	acc, err := inv.account(i)
+	if err != nil {
+		return err
+	}
+	if acc.Owner != inv.program || len(acc.Data) != multisigSize {
+		return inv.requireSigner(i)
+	}
+	ms, ok := unpackMultisig(acc.Data)
+	if !ok {
+		return builtinErr("UninitializedAccount")
+	}
+	var matched [multisigMaxSigners]bool
+	approvals := 0
+	for j := i + 1; j < len(inv.keys); j++ {
+		for pos, member := range ms.signers {
+			if member != inv.keys[j] || matched[pos] {
+				continue
+			}
+			if !inv.signers[j] {
+				return builtinErr("MissingRequiredSignature")
+			}
+			matched[pos] = true
+			approvals++
+		}
+	}
+	if approvals < int(ms.m) {
+		inv.log("Error: %d of %d required multisig signers", approvals, ms.m)
+		return builtinErr("MissingRequiredSignature")
+	}
+	return nil
+}
+
+// validateSpender checks the authority debiting amount from ta: its owner, or
+// its delegate within the delegated amount, which the debit then consumes.
+func (inv *invocation) validateSpender(ta *tokenAccountState, i int, amount uint64) error {
+	key, err := inv.key(i)
+	if err != nil {
+		return err
+	}
+	if ta.delegate == nil || key != *ta.delegate {
+		return inv.validateOwner(ta.owner, i)
+	}
+	if err := inv.validateOwner(*ta.delegate, i); err != nil {
+		return err
+	}
+	if ta.delegatedAmount < amount {
+		inv.log("Error: insufficient delegated amount")
+		return customErr(tokenErrInsufficientFunds)
+	}
+	ta.delegatedAmount -= amount
+	if ta.delegatedAmount == 0 {
+		ta.delegate = nil
+	}
+	return nil
+}
+
+type multisigState struct {
+	m       uint8
+	signers []common.PublicKey
+}
+
+func unpackMultisig(data []byte) (multisigState, bool) {
+	if len(data) != multisigSize || data[2] == 0 {
+		return multisigState{}, false
+	}
+	n := int(data[1])
+	if n > multisigMaxSigners {
+		return multisigState{}, false
+	}
+	ms := multisigState{m: data[0]}
+	for k := 0; k < n; k++ {
+		ms.signers = append(ms.signers, common.PublicKeyFromBytes(data[3+32*k:35+32*k]))
+	}
+	return ms, true
+}
+
+// tokenInitializeMultisig records the signer accounts from firstSigner on as
+// the members of an m-of-n multisig.
+func tokenInitializeMultisig(inv *invocation, data []byte, firstSigner int) error {
+	if len(data) < 1 {
+		return builtinErr("InvalidInstructionData")
+	}
+	acc, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	if acc.Owner != inv.program {
+		return builtinErr("IncorrectProgramId")
+	}
+	if len(acc.Data) != multisigSize {
+		return builtinErr("InvalidAccountData")
+	}
+	if acc.Data[2] != 0 {
+		return customErr(tokenErrAlreadyInUse)
+	}
+	if firstSigner > len(inv.keys) {
+		return builtinErr("NotEnoughAccountKeys")
+	}
+	members := inv.keys[firstSigner:]
+	m := data[0]
+	if len(members) < 1 || len(members) > multisigMaxSigners {
+		return customErr(tokenErrInvalidSignerCount)
+	}
+	if m < 1 || int(m) > len(members) {
+		return customErr(tokenErrInvalidRequired)
+	}
+	acc.Data[0] = m
+	acc.Data[1] = byte(len(members))
+	acc.Data[2] = 1
+	for k, member := range members {
+		copy(acc.Data[3+32*k:35+32*k], member.Bytes())
+	}
+	return nil
+}
+
+func processToken(inv *invocation) error {
+	if len(inv.data) == 0 {
+		return builtinErr("InvalidInstructionData")
+	}
+	data := inv.data[1:]
+	switch inv.data[0] {
+	case 0:
+		inv.log("Instruction: InitializeMint")
+		return tokenInitializeMint(inv, data)
+	case 20:
+		inv.log("Instruction: InitializeMint2")
+		return tokenInitializeMint(inv, data)
+	case 1:
+		inv.log("Instruction: InitializeAccount")
+		owner, err := inv.key(2)
+		if err != nil {
+			return err
+		}
+		return tokenInitializeAccount(inv, owner)
+	case 16, 18:
+		if inv.data[0] == 16 {
+			inv.log("Instruction: InitializeAccount2")
+		} else {
+			inv.log("Instruction: InitializeAccount3")
+		}
+		if len(data) < 32 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return tokenInitializeAccount(inv, common.PublicKeyFromBytes(data[:32]))
+	case 3:
+		inv.log("Instruction: Transfer")
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return tokenTransfer(inv, 0, -1, 1, 2, binary.LittleEndian.Uint64(data[:8]), nil, nil)
+	case 12:
+		inv.log("Instruction: TransferChecked")
+		if len(data) < 9 {
+			return builtinErr("InvalidInstructionData")
+		}
+		decimals := data[8]
+		return tokenTransfer(inv, 0, 1, 2, 3, binary.LittleEndian.Uint64(data[:8]), &decimals, nil)
+	case 7:
+		inv.log("Instruction: MintTo")
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return tokenMintTo(inv, binary.LittleEndian.Uint64(data[:8]), nil)
+	case 14:
+		inv.log("Instruction: MintToChecked")
+		if len(data) < 9 {
+			return builtinErr("InvalidInstructionData")
+		}
+		decimals := data[8]
+		return tokenMintTo(inv, binary.LittleEndian.Uint64(data[:8]), &decimals)
+	case 2:
+		inv.log("Instruction: InitializeMultisig")
+		return tokenInitializeMultisig(inv, data, 2)
+	case 19:
+		inv.log("Instruction: InitializeMultisig2")
+		return tokenInitializeMultisig(inv, data, 1)
+	case 4:
+		inv.log("Instruction: Approve")
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return tokenApprove(inv, -1, 1, 2, binary.LittleEndian.Uint64(data[:8]), nil)
+	case 13:
+		inv.log("Instruction: ApproveChecked")
+		if len(data) < 9 {
+			return builtinErr("InvalidInstructionData")
+		}
+		decimals := data[8]
+		return tokenApprove(inv, 1, 2, 3, binary.LittleEndian.Uint64(data[:8]), &decimals)
+	case 5:
+		inv.log("Instruction: Revoke")
+		return tokenRevoke(inv)
+	case 8:
+		inv.log("Instruction: Burn")
+		if len(data) < 8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		return tokenBurn(inv, binary.LittleEndian.Uint64(data[:8]), nil)
+	case 15:
+		inv.log("Instruction: BurnChecked")
+		if len(data) < 9 {
+			return builtinErr("InvalidInstructionData")
+		}
+		decimals := data[8]
+		return tokenBurn(inv, binary.LittleEndian.Uint64(data[:8]), &decimals)
+	case 9:
+		inv.log("Instruction: CloseAccount")
+		return tokenCloseAccount(inv)
+	case 10:
+		inv.log("Instruction: FreezeAccount")
+		return tokenSetFrozen(inv, true)
+	case 11:
+		inv.log("Instruction: ThawAccount")
+		return tokenSetFrozen(inv, false)
+	case 6:
+		inv.log("Instruction: SetAuthority")
+		return tokenSetAuthority(inv, data)
+	case 26:
+		return processTransferFeeExtension(inv, data)
+	default:
+		return builtinErr("InvalidInstructionData")
+	}
+}
+
+func tokenInitializeMint(inv *invocation, data []byte) error {
+	if len(data) < 1+32+1 {
+		return builtinErr("InvalidInstructionData")
+	}
+	acc, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	if acc.Owner != inv.program {
+		return builtinErr("IncorrectProgramId")
+	}
+	if len(acc.Data) < mintSize {
+		return builtinErr("InvalidAccountData")
+	}
+	if _, ok := unpackMint(acc.Data); ok {
+		return customErr(tokenErrAlreadyInUse)
+	}
+	authority := common.PublicKeyFromBytes(data[1:33])
+	m := mintState{mintAuthority: &authority, decimals: data[0], initialized: true}
+	if data[33] == 1 {
+		if len(data) < 1+32+1+32 {
+			return builtinErr("InvalidInstructionData")
+		}
+		freeze := common.PublicKeyFromBytes(data[34:66])
+		m.freezeAuthority = &freeze
+	}
+	packMint(acc.Data, m)
+	if len(acc.Data) > mintSize {
+		acc.Data[accountTypeOffset] = accountTypeMint
+	}
+	return nil
+}
+
+func tokenInitializeAccount(inv *invocation, owner common.PublicKey) error {
+	acc, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	if acc.Owner != inv.program {
+		return builtinErr("IncorrectProgramId")
+	}
+	if len(acc.Data) < tokenAccountSize {
+		return builtinErr("InvalidAccountData")
+	}
+	if _, ok := unpackTokenAccount(acc.Data); ok {
+		return customErr(tokenErrAlreadyInUse)
+	}
+	mintAcc, err := inv.account(1)
+	if err != nil {
+		return err
+	}
+	if _, ok := unpackMint(mintAcc.Data); !ok || mintAcc.Owner != inv.program {
+		return customErr(tokenErrInvalidMint)
+	}
+	packTokenAccount(acc.Data, tokenAccountState{
+		mint:  inv.keys[1],
+		owner: owner,
+		state: accountStateInitialized,
+	})
+	return nil
+}
+
+// tokenTransfer moves tokens between accounts. mintIdx is -1 for the unchecked variant.
+// A Token-2022 transfer fee is withheld in the destination; expectedFee, when set, must match it.
+func tokenTransfer(inv *invocation, srcIdx, mintIdx, dstIdx, authIdx int, amount uint64, decimals *uint8, expectedFee *uint64) error {
+	srcAcc, src, err := inv.loadTokenAccount(srcIdx)
+	if err != nil {
+		return err
+	}
+	dstAcc, dst, err := inv.loadTokenAccount(dstIdx)
+	if err != nil {
+		return err
+	}
+	if !inv.writable[srcIdx] || !inv.writable[dstIdx] {
+		return builtinErr("ReadonlyDataModified")
+	}
+	if src.state == accountStateFrozen || dst.state == accountStateFrozen {
+		return customErr(tokenErrAccountFrozen)
+	}
+	if src.mint != dst.mint {
+		return customErr(tokenErrMintMismatch)
+	}
+	var fee uint64
+	if mintIdx >= 0 {
+		if inv.keys[mintIdx] != src.mint {
+			return customErr(tokenErrMintMismatch)
+		}
+		mintAcc, m, err := inv.loadMint(mintIdx)
+		if err != nil {
+			return err
+		}
+		if decimals != nil && *decimals != m.decimals {
+			return customErr(tokenErrMintDecimalsMismatch)
+		}
+		if schedule, ok := mintTransferFee(mintAcc.Data, epochOf(inv.slot)); ok {
+			fee = schedule.fee(amount)
+		}
+	} else if findExtension(srcAcc.Data, extensionTransferFeeAmount) != nil {
+		return customErr(tokenErrMintRequiredForTransfer)
+	}
+	if expectedFee != nil && *expectedFee != fee {
+		return customErr(tokenErrFeeMismatch)
+	}
+	if src.amount < amount {
+		inv.log("Error: insufficient funds")
+		return customErr(tokenErrInsufficientFunds)
+	}
+	if err := inv.validateSpender(&src, authIdx, amount); err != nil {
+		return err
+	}
+	if inv.keys[srcIdx] == inv.keys[dstIdx] {
+		return nil
+	}
+	src.amount -= amount
+	dst.amount += amount - fee
+	packTokenAccount(srcAcc.Data, src)
+	packTokenAccount(dstAcc.Data, dst)
+	if fee > 0 {
+		withheld := findExtension(dstAcc.Data, extensionTransferFeeAmount)
+		if len(withheld) < transferFeeAmountSize {
+			return builtinErr("InvalidAccountData")
+		}
+		binary.LittleEndian.PutUint64(withheld, binary.LittleEndian.Uint64(withheld)+fee)
+	}
+	return nil
+}
+
+func tokenMintTo(inv *invocation, amount uint64, decimals *uint8) error {
+	mintAcc, m, err := inv.loadMint(0)
+	if err != nil {
+		return err
+	}
+	dstAcc, dst, err := inv.loadTokenAccount(1)
+	if err != nil {
+		return err
+	}
+	if !inv.writable[0] || !inv.writable[1] {
+		return builtinErr("ReadonlyDataModified")
+	}
+	if dst.state == accountStateFrozen {
+		return customErr(tokenErrAccountFrozen)
+	}
+	if dst.mint != inv.keys[0] {
+		return customErr(tokenErrMintMismatch)
+	}
+	if decimals != nil && *decimals != m.decimals {
+		return customErr(tokenErrMintDecimalsMismatch)
+	}
+	if m.mintAuthority == nil {
+		return customErr(tokenErrFixedSupply)
+	}
+	if err := inv.validateOwner(*m.mintAuthority, 2); err != nil {
+		return err
+	}
+	if m.supply+amount < m.supply {
+		return customErr(tokenErrOverflow)
+	}
+	m.supply += amount
+	dst.amount += amount
+	packMint(mintAcc.Data, m)
+	packTokenAccount(dstAcc.Data, dst)
+	return nil
+}
+
+func tokenBurn(inv *invocation, amount uint64, decimals *uint8) error {
+	srcAcc, src, err := inv.loadTokenAccount(0)
+	if err != nil {
+		return err
+	}
+	mintAcc, m, err := inv.loadMint(1)
+	if err != nil {
+		return err
+	}
+	if !inv.writable[0] || !inv.writable[1] {
+		return builtinErr("ReadonlyDataModified")
+	}
+	if src.state == accountStateFrozen {
+		return customErr(tokenErrAccountFrozen)
+	}
+	if src.mint != inv.keys[1] {
+		return customErr(tokenErrMintMismatch)
+	}
+	if decimals != nil && *decimals != m.decimals {
+		return customErr(tokenErrMintDecimalsMismatch)
+	}
+	if src.amount < amount {
+		inv.log("Error: insufficient funds")
+		return customErr(tokenErrInsufficientFunds)
+	}
+	if err := inv.validateSpender(&src, 2, amount); err != nil {
+		return err
+	}
+	src.amount -= amount
+	m.supply -= amount
+	packTokenAccount(srcAcc.Data, src)
+	packMint(mintAcc.Data, m)
+	return nil
+}
+
+// tokenApprove lets the delegate spend up to amount from the source account,
+// replacing any earlier approval. mintIdx is -1 for the unchecked variant.
+func tokenApprove(inv *invocation, mintIdx, delegateIdx, ownerIdx int, amount uint64, decimals *uint8) error {
+	acc, ta, err := inv.loadTokenAccount(0)
+	if err != nil {
+		return err
+	}
+	if !inv.writable[0] {
+		return builtinErr("ReadonlyDataModified")
+	}
+	if ta.state == accountStateFrozen {
+		return customErr(tokenErrAccountFrozen)
+	}
+	if mintIdx >= 0 {
+		if inv.keys[mintIdx] != ta.mint {
+			return customErr(tokenErrMintMismatch)
+		}
+		_, m, err := inv.loadMint(mintIdx)
+		if err != nil {
+			return err
+		}
+		if decimals != nil && *decimals != m.decimals {
+			return customErr(tokenErrMintDecimalsMismatch)
+		}
+	}
+	delegate, err := inv.key(delegateIdx)
+	if err != nil {
+		return err
+	}
+	if err := inv.validateOwner(ta.owner, ownerIdx); err != nil {
+		return err
+	}
+	ta.delegate = &delegate
+	ta.delegatedAmount = amount
+	packTokenAccount(acc.Data, ta)
+	return nil
+}
+
+func tokenRevoke(inv *invocation) error {
+	acc, ta, err := inv.loadTokenAccount(0)
+	if err != nil {
+		return err
+	}
+	if !inv.writable[0] {
+		return builtinErr("ReadonlyDataModified")
+	}
+	if ta.state == accountStateFrozen {
+		return customErr(tokenErrAccountFrozen)
+	}
+	if err := inv.validateOwner(ta.owner, 1); err != nil {
+		return err
+	}
+	ta.delegate = nil
+	ta.delegatedAmount = 0
+	packTokenAccount(acc.Data, ta)
+	return nil
+}
+
+// tokenCloseAccount moves the lamports of an empty token account to the
+// destination and hands the account back to the System program.
+func tokenCloseAccount(inv *invocation) error {
+	acc, ta, err := inv.loadTokenAccount(0)
+	if err != nil {
+		return err
+	}
+	if inv.keys[0] == inv.keys[1] {
+		return builtinErr("InvalidAccountData")
+	}
+	if ta.isNative == nil && ta.amount != 0 {
+		inv.log("Error: Non-native account can only be closed if its balance is zero")
+		return customErr(tokenErrNonNativeHasBalance)
+	}
+	authority := ta.owner
+	if ta.closeAuthority != nil {
+		authority = *ta.closeAuthority
+	}
+	if err := inv.validateOwner(authority, 2); err != nil {
+		return err
+	}
+	if _, err := inv.mutable(0); err != nil {
+		return err
+	}
+	dst, err := inv.mutable(1)
+	if err != nil {
+		return err
+	}
+	dst.Lamports += acc.Lamports
+	acc.Lamports = 0
+	acc.Owner = common.SystemProgramID
+	acc.Data = nil
+	return nil
+}
+
+// tokenSetFrozen freezes or thaws a token account with the freeze authority of its mint.
+func tokenSetFrozen(inv *invocation, freeze bool) error {
+	acc, ta, err := inv.loadTokenAccount(0)
+	if err != nil {
+		return err
+	}
+	if !inv.writable[0] {
+		return builtinErr("ReadonlyDataModified")
+	}
+	if ta.isNative != nil {
+		return builtinErr("InvalidAccountData")
+	}
+	if ta.mint != inv.keys[1] {
+		return customErr(tokenErrMintMismatch)
+	}
+	if (ta.state == accountStateFrozen) == freeze {
+		return customErr(tokenErrInvalidState)
+	}
+	_, m, err := inv.loadMint(1)
+	if err != nil {
+		return err
+	}
+	if m.freezeAuthority == nil {
+		return customErr(tokenErrMintCannotFreeze)
+	}
+	if err := inv.validateOwner(*m.freezeAuthority, 2); err != nil {
+		return err
+	}
+	ta.state = accountStateInitialized
+	if freeze {
+		ta.state = accountStateFrozen
+	}
+	packTokenAccount(acc.Data, ta)
+	return nil
+}
+
+// SetAuthority authority types.
+const (
+	authorityMintTokens    = 0
+	authorityFreezeAccount = 1
+	authorityAccountOwner  = 2
+	authorityCloseAccount  = 3
+)
+
+func tokenSetAuthority(inv *invocation, data []byte) error {
+	if len(data) < 2 || (data[1] == 1 && len(data) < 2+32) {
+		return builtinErr("InvalidInstructionData")
+	}
+	var newAuthority *common.PublicKey
+	if data[1] == 1 {
+		k := common.PublicKeyFromBytes(data[2:34])
+		newAuthority = &k
+	}
+	acc, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	if acc.Owner != inv.program {
+		return builtinErr("IncorrectProgramId")
+	}
+
+	if ta, ok := unpackTokenAccount(acc.Data); ok {
+		if ta.state == accountStateFrozen {
+			return customErr(tokenErrAccountFrozen)
+		}
+		switch data[0] {
+		case authorityAccountOwner:
+			if err := inv.validateOwner(ta.owner, 1); err != nil {
+				return err
+			}
+			if newAuthority == nil {
+				return customErr(tokenErrInvalidInstruction)
+			}
+			ta.owner = *newAuthority
+			// a new owner does not inherit the approvals of the previous one
+			ta.delegate = nil
+			ta.delegatedAmount = 0
+		case authorityCloseAccount:
+			authority := ta.owner
+			if ta.closeAuthority != nil {
+				authority = *ta.closeAuthority
+			}
+			if err := inv.validateOwner(authority, 1); err != nil {
+				return err
+			}
+			ta.closeAuthority = newAuthority
+		default:
+			return customErr(tokenErrAuthorityType)
+		}
+		packTokenAccount(acc.Data, ta)
+		return nil
+	}
+
+	m, ok := unpackMint(acc.Data)
+	if !ok {
+		return builtinErr("InvalidAccountData")
+	}
+	switch data[0] {
+	case authorityMintTokens:
+		if m.mintAuthority == nil {
+			return customErr(tokenErrFixedSupply)
+		}
+		if err := inv.validateOwner(*m.mintAuthority, 1); err != nil {
+			return err
+		}
+		m.mintAuthority = newAuthority
+	case authorityFreezeAccount:
+		if m.freezeAuthority == nil {
+			return customErr(tokenErrMintCannotFreeze)
+		}
+		if err := inv.validateOwner(*m.freezeAuthority, 1); err != nil {
+			return err
+		}
+		m.freezeAuthority = newAuthority
+	default:
+		return customErr(tokenErrAuthorityType)
+	}
+	packMint(acc.Data, m)
+	return nil
+}
+
+func processAssociatedTokenAccount(inv *invocation) error {
+	idempotent := false
+	if len(inv.data) > 0 {
+		switch inv.data[0] {
+		case 0:
+		case 1:
+			idempotent = true
+		default:
+			return builtinErr("InvalidInstructionData")
+		}
+	}
+	if idempotent {
+		inv.log("CreateIdempotent")
+	} else {
+		inv.log("Create")
+	}
+	if len(inv.keys) < 6 {
+		return builtinErr("NotEnoughAccountKeys")
+	}
+	wallet, mint, tokenProgram := inv.keys[2], inv.keys[3], inv.keys[5]
+	expected, _, err := common.FindProgramAddress(
+		[][]byte{wallet.Bytes(), tokenProgram.Bytes(), mint.Bytes()},
+		common.SPLAssociatedTokenAccountProgramID,
+	)
+	if err != nil || expected != inv.keys[1] {
+		inv.log("Error: Associated address does not match seed derivation")
+		return builtinErr("InvalidSeeds")
+	}
+
+	ata, err := inv.mutable(1)
+	if err != nil {
+		return err
+	}
+	if ata.Lamports > 0 || ata.Owner != common.SystemProgramID {
+		if idempotent && ata.Owner == tokenProgram {
+			if ta, ok := unpackTokenAccount(ata.Data); ok && ta.owner == wallet && ta.mint == mint {
+				return nil
+			}
+			return builtinErr("IllegalOwner")
+		}
+		return customErr(systemErrAccountAlreadyInUse)
+	}
+
+	mintAcc, err := inv.account(3)
+	if err != nil {
+		return err
+	}
+	if mintAcc.Owner != tokenProgram || !isTokenProgram(tokenProgram) {
+		return builtinErr("IncorrectProgramId")
+	}
+	if _, ok := unpackMint(mintAcc.Data); !ok {
+		return builtinErr("InvalidAccountData")
+	}
+
+	if err := inv.requireSigner(0); err != nil {
+		return err
+	}
+	payer, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	size := tokenAccountSizeFor(tokenProgram, mintAcc.Data)
+	rent := RentExemptMinimum(uint64(size))
+	if payer.Lamports < rent {
+		return customErr(systemErrResultWithNegativeLamports)
+	}
+	payer.Lamports -= rent
+	ata.Lamports += rent
+	ata.Owner = tokenProgram
+	ata.Data = make([]byte, size)
+	if err := initAccountExtensions(ata.Data, mintAcc.Data); err != nil {
+		return err
+	}
+	packTokenAccount(ata.Data, tokenAccountState{
+		mint:  mint,
+		owner: wallet,
+		state: accountStateInitialized,
+	})
+	return nil
+}
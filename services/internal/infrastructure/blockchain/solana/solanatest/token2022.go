@@ -0,0 +1,258 @@
+package solanatest
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/blocto/solana-go-sdk/common"
+	json "github.com/goccy/go-json"
+)
+
+// SlotsPerEpoch is the fixed epoch length of the fake cluster.
+const SlotsPerEpoch = 432_000
+
+// Token-2022 stores extensions after the base state padded to the token
+// account size, an account type byte and a list of type-length-value entries.
+const (
+	accountTypeOffset = tokenAccountSize
+	extensionsOffset  = accountTypeOffset + 1
+	tlvHeaderSize     = 4
+
+	accountTypeMint    = 1
+	accountTypeAccount = 2
+
+	extensionTransferFeeConfig = 1
+	extensionTransferFeeAmount = 2
+	extensionImmutableOwner    = 7
+
+	transferFeeConfigSize = 108
+	transferFeeAmountSize = 8
+)
+
+// Token-2022 custom errors.
+const (
+	tokenErrExtensionAlreadyInitialized = 22
+	tokenErrTransferFeeExceedsMaximum   = 30
+	tokenErrMintRequiredForTransfer     = 31
+	tokenErrFeeMismatch                 = 32
+)
+
+const maxFeeBasisPoints = 10_000
+
+// MintExtensionsSize is the account size of a Token-2022 mint carrying a TransferFeeConfig.
+const MintExtensionsSize = extensionsOffset + tlvHeaderSize + transferFeeConfigSize
+
+func epochOf(slot uint64) uint64 {
+	return slot / SlotsPerEpoch
+}
+
+// findExtension returns the value of an extension entry, or nil when absent.
+func findExtension(data []byte, extType uint16) []byte {
+	if len(data) <= extensionsOffset {
+		return nil
+	}
+	for off := extensionsOffset; off+tlvHeaderSize <= len(data); {
+		t := binary.LittleEndian.Uint16(data[off:])
+		l := int(binary.LittleEndian.Uint16(data[off+2:]))
+		if t == 0 {
+			return nil
+		}
+		off += tlvHeaderSize
+		if off+l > len(data) {
+			return nil
+		}
+		if t == extType {
+			return data[off : off+l]
+		}
+		off += l
+	}
+	return nil
+}
+
+// initExtension claims the first free TLV slot for an extension of size bytes.
+func initExtension(data []byte, accountType byte, extType uint16, size int) ([]byte, error) {
+	if len(data) <= extensionsOffset {
+		return nil, builtinErr("InvalidAccountData")
+	}
+	if findExtension(data, extType) != nil {
+		return nil, customErr(tokenErrExtensionAlreadyInitialized)
+	}
+	off := extensionsOffset
+	for off+tlvHeaderSize <= len(data) && binary.LittleEndian.Uint16(data[off:]) != 0 {
+		off += tlvHeaderSize + int(binary.LittleEndian.Uint16(data[off+2:]))
+	}
+	if off+tlvHeaderSize+size > len(data) {
+		return nil, builtinErr("InvalidAccountData")
+	}
+	data[accountTypeOffset] = accountType
+	binary.LittleEndian.PutUint16(data[off:], extType)
+	binary.LittleEndian.PutUint16(data[off+2:], uint16(size))
+	return data[off+tlvHeaderSize : off+tlvHeaderSize+size], nil
+}
+
+// transferFee is one epoch-scoped fee schedule of a TransferFeeConfig.
+type transferFee struct {
+	epoch       uint64
+	maximumFee  uint64
+	basisPoints uint16
+}
+
+func readTransferFee(b []byte) transferFee {
+	return transferFee{
+		epoch:       binary.LittleEndian.Uint64(b[0:8]),
+		maximumFee:  binary.LittleEndian.Uint64(b[8:16]),
+		basisPoints: binary.LittleEndian.Uint16(b[16:18]),
+	}
+}
+
+func writeTransferFee(b []byte, f transferFee) {
+	binary.LittleEndian.PutUint64(b[0:8], f.epoch)
+	binary.LittleEndian.PutUint64(b[8:16], f.maximumFee)
+	binary.LittleEndian.PutUint16(b[16:18], f.basisPoints)
+}
+
+func (f transferFee) fee(amount uint64) uint64 {
+	if f.basisPoints == 0 || amount == 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(amount, uint64(f.basisPoints))
+	lo, carry := bits.Add64(lo, maxFeeBasisPoints-1, 0)
+	hi += carry
+	if hi >= maxFeeBasisPoints {
+		return f.maximumFee
+	}
+	q, _ := bits.Div64(hi, lo, maxFeeBasisPoints)
+	return min(q, f.maximumFee)
+}
+
+// mintTransferFee returns the fee schedule of a mint in effect at epoch, if any.
+func mintTransferFee(data []byte, epoch uint64) (transferFee, bool) {
+	ext := findExtension(data, extensionTransferFeeConfig)
+	if len(ext) < transferFeeConfigSize {
+		return transferFee{}, false
+	}
+	newer := readTransferFee(ext[90:108])
+	if epoch >= newer.epoch {
+		return newer, true
+	}
+	return readTransferFee(ext[72:90]), true
+}
+
+// tokenAccountSizeFor returns the size of a token account for mintData under program.
+func tokenAccountSizeFor(program common.PublicKey, mintData []byte) int {
+	if program != common.Token2022ProgramID {
+		return tokenAccountSize
+	}
+	size := extensionsOffset + tlvHeaderSize // ImmutableOwner
+	if findExtension(mintData, extensionTransferFeeConfig) != nil {
+		size += tlvHeaderSize + transferFeeAmountSize
+	}
+	return size
+}
+
+// initAccountExtensions writes the extensions an associated account of mintData requires.
+func initAccountExtensions(data, mintData []byte) error {
+	if len(data) <= tokenAccountSize {
+		return nil
+	}
+	if _, err := initExtension(data, accountTypeAccount, extensionImmutableOwner, 0); err != nil {
+		return err
+	}
+	if findExtension(mintData, extensionTransferFeeConfig) != nil {
+		if _, err := initExtension(data, accountTypeAccount, extensionTransferFeeAmount, transferFeeAmountSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func processTransferFeeExtension(inv *invocation, data []byte) error {
+	if inv.program != common.Token2022ProgramID || len(data) == 0 {
+		return builtinErr("InvalidInstructionData")
+	}
+	switch data[0] {
+	case 0:
+		inv.log("TransferFeeInstruction: InitializeTransferFeeConfig")
+		return tokenInitializeTransferFeeConfig(inv, data[1:])
+	case 1:
+		inv.log("TransferFeeInstruction: TransferCheckedWithFee")
+		if len(data) < 1+8+1+8 {
+			return builtinErr("InvalidInstructionData")
+		}
+		decimals := data[9]
+		fee := binary.LittleEndian.Uint64(data[10:18])
+		return tokenTransfer(inv, 0, 1, 2, 3, binary.LittleEndian.Uint64(data[1:9]), &decimals, &fee)
+	default:
+		return builtinErr("InvalidInstructionData")
+	}
+}
+
+// readPubkeyOption decodes an instruction-encoded Option<Pubkey>: a tag byte
+// followed by the key when present.
+func readPubkeyOption(data []byte) (*common.PublicKey, []byte, bool) {
+	if len(data) == 0 {
+		return nil, nil, false
+	}
+	if data[0] == 0 {
+		return nil, data[1:], true
+	}
+	if len(data) < 33 {
+		return nil, nil, false
+	}
+	k := common.PublicKeyFromBytes(data[1:33])
+	return &k, data[33:], true
+}
+
+func tokenInitializeTransferFeeConfig(inv *invocation, data []byte) error {
+	configAuthority, rest, ok := readPubkeyOption(data)
+	if !ok {
+		return builtinErr("InvalidInstructionData")
+	}
+	withdrawAuthority, rest, ok := readPubkeyOption(rest)
+	if !ok || len(rest) < 2+8 {
+		return builtinErr("InvalidInstructionData")
+	}
+	basisPoints := binary.LittleEndian.Uint16(rest[0:2])
+	maximumFee := binary.LittleEndian.Uint64(rest[2:10])
+	if basisPoints > maxFeeBasisPoints {
+		return customErr(tokenErrTransferFeeExceedsMaximum)
+	}
+
+	acc, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	if acc.Owner != inv.program {
+		return builtinErr("IncorrectProgramId")
+	}
+	if _, ok := unpackMint(acc.Data); ok {
+		return customErr(tokenErrAlreadyInUse)
+	}
+	ext, err := initExtension(acc.Data, accountTypeMint, extensionTransferFeeConfig, transferFeeConfigSize)
+	if err != nil {
+		return err
+	}
+	if configAuthority != nil {
+		copy(ext[0:32], configAuthority.Bytes())
+	}
+	if withdrawAuthority != nil {
+		copy(ext[32:64], withdrawAuthority.Bytes())
+	}
+	schedule := transferFee{epoch: epochOf(inv.slot), maximumFee: maximumFee, basisPoints: basisPoints}
+	writeTransferFee(ext[72:90], schedule)
+	writeTransferFee(ext[90:108], schedule)
+	return nil
+}
+
+func (s *Server) getEpochInfo(params []json.RawMessage) (any, *rpcError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return map[string]any{
+		"absoluteSlot":     s.slot,
+		"blockHeight":      s.slot,
+		"epoch":            epochOf(s.slot),
+		"slotIndex":        s.slot % SlotsPerEpoch,
+		"slotsInEpoch":     SlotsPerEpoch,
+		"transactionCount": len(s.txs),
+	}, nil
+}
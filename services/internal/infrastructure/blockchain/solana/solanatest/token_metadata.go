@@ -0,0 +1,636 @@
+package solanatest
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/blocto/solana-go-sdk/common"
+)
+
+// TokenMetadataProgramID is the Metaplex Token Metadata program.
+var TokenMetadataProgramID = common.PublicKeyFromString("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
+
+const (
+	// metadataSize is the size Metaplex allocates for every metadata account.
+	metadataSize = 679
+
+	// masterEditionSize is the size of a master edition account.
+	masterEditionSize = 282
+
+	metadataKeyV1      = 4
+	masterEditionKeyV2 = 6
+
+	metadataMaxNameLength   = 32
+	metadataMaxSymbolLength = 10
+	metadataMaxURILength    = 200
+	metadataMaxCreators     = 5
+)
+
+// Token Metadata custom errors.
+const (
+	metadataErrInstructionUnpack        = 0
+	metadataErrAlreadyInitialized       = 3
+	metadataErrUninitialized            = 4
+	metadataErrInvalidMetadataKey       = 5
+	metadataErrInvalidEditionKey        = 6
+	metadataErrUpdateAuthorityIncorrect = 7
+	metadataErrUpdateAuthorityNotSigner = 8
+	metadataErrNotMintAuthority         = 9
+	metadataErrInvalidMintAuthority     = 10
+	metadataErrNameTooLong              = 11
+	metadataErrSymbolTooLong            = 12
+	metadataErrURITooLong               = 13
+	metadataErrMintMismatch             = 15
+	metadataErrEditionsExactlyOneToken  = 16
+	metadataErrEditionDecimalsNotZero   = 24
+)
+
+// Token standards set by CreateMetadataAccountV3 and CreateMasterEditionV3.
+const (
+	tokenStandardNonFungible   = 0
+	tokenStandardFungibleAsset = 1
+	tokenStandardFungible      = 2
+)
+
+var errShortData = errors.New("unexpected end of data")
+
+type creator struct {
+	address  common.PublicKey
+	verified bool
+	share    uint8
+}
+
+type collection struct {
+	verified bool
+	key      common.PublicKey
+}
+
+type uses struct {
+	method    uint8
+	remaining uint64
+	total     uint64
+}
+
+// dataV2 is the metadata payload of Metaplex instructions.
+type dataV2 struct {
+	name                 string
+	symbol               string
+	uri                  string
+	sellerFeeBasisPoints uint16
+	creators             []creator
+	collection           *collection
+	uses                 *uses
+}
+
+type metadata struct {
+	updateAuthority     common.PublicKey
+	mint                common.PublicKey
+	data                dataV2
+	primarySaleHappened bool
+	isMutable           bool
+	editionNonce        *uint8
+	tokenStandard       *uint8
+}
+
+// borshReader decodes Borsh fields in order; the first short read is kept in err.
+type borshReader struct {
+	data []byte
+	off  int
+	err  error
+}
+
+func (r *borshReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data)-r.off < n {
+		r.err = errShortData
+		return nil
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+func (r *borshReader) u8() uint8 {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *borshReader) flag() bool { return r.u8() == 1 }
+
+func (r *borshReader) u16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *borshReader) u32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *borshReader) u64() uint64 {
+	if b := r.next(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *borshReader) key() common.PublicKey {
+	if b := r.next(32); b != nil {
+		return common.PublicKeyFromBytes(b)
+	}
+	return common.PublicKey{}
+}
+
+func (r *borshReader) str() string {
+	return string(r.next(int(r.u32())))
+}
+
+// metadataData reads the fields DataV2 shares with the metadata account, up to the creators.
+func (r *borshReader) metadataData() dataV2 {
+	d := dataV2{name: r.str(), symbol: r.str(), uri: r.str(), sellerFeeBasisPoints: r.u16()}
+	if r.flag() {
+		n := int(r.u32())
+		for i := 0; i < n && r.err == nil; i++ {
+			d.creators = append(d.creators, creator{address: r.key(), verified: r.flag(), share: r.u8()})
+		}
+		if d.creators == nil {
+			d.creators = []creator{}
+		}
+	}
+	return d
+}
+
+func (r *borshReader) collection() *collection {
+	if !r.flag() {
+		return nil
+	}
+	return &collection{verified: r.flag(), key: r.key()}
+}
+
+func (r *borshReader) uses() *uses {
+	if !r.flag() {
+		return nil
+	}
+	return &uses{method: r.u8(), remaining: r.u64(), total: r.u64()}
+}
+
+func (r *borshReader) dataV2() dataV2 {
+	d := r.metadataData()
+	d.collection = r.collection()
+	d.uses = r.uses()
+	return d
+}
+
+func appendString(b []byte, s string, padded int) []byte {
+	for len(s) < padded {
+		s += "\x00"
+	}
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func appendFlag(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+func unpackMetadata(acc *Account) (metadata, bool) {
+	if acc.Owner != TokenMetadataProgramID || len(acc.Data) == 0 || acc.Data[0] != metadataKeyV1 {
+		return metadata{}, false
+	}
+	r := &borshReader{data: acc.Data, off: 1}
+	m := metadata{updateAuthority: r.key(), mint: r.key(), data: r.metadataData()}
+	m.primarySaleHappened = r.flag()
+	m.isMutable = r.flag()
+	if r.flag() {
+		nonce := r.u8()
+		m.editionNonce = &nonce
+	}
+	if r.flag() {
+		standard := r.u8()
+		m.tokenStandard = &standard
+	}
+	// collection and uses follow the token standard in the account, unlike in DataV2
+	m.data.collection = r.collection()
+	m.data.uses = r.uses()
+	return m, r.err == nil
+}
+
+// packMetadata lays out an account the way Metaplex does, padding the strings to their
+// maximum length and the account to metadataSize.
+func packMetadata(m metadata) []byte {
+	b := make([]byte, 0, metadataSize)
+	b = append(b, metadataKeyV1)
+	b = append(b, m.updateAuthority.Bytes()...)
+	b = append(b, m.mint.Bytes()...)
+	b = appendString(b, m.data.name, metadataMaxNameLength)
+	b = appendString(b, m.data.symbol, metadataMaxSymbolLength)
+	b = appendString(b, m.data.uri, metadataMaxURILength)
+	b = binary.LittleEndian.AppendUint16(b, m.data.sellerFeeBasisPoints)
+	b = appendFlag(b, m.data.creators != nil)
+	if m.data.creators != nil {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(m.data.creators)))
+		for _, c := range m.data.creators {
+			b = append(b, c.address.Bytes()...)
+			b = appendFlag(b, c.verified)
+			b = append(b, c.share)
+		}
+	}
+	b = appendFlag(b, m.primarySaleHappened)
+	b = appendFlag(b, m.isMutable)
+	b = appendFlag(b, m.editionNonce != nil)
+	if m.editionNonce != nil {
+		b = append(b, *m.editionNonce)
+	}
+	b = appendFlag(b, m.tokenStandard != nil)
+	if m.tokenStandard != nil {
+		b = append(b, *m.tokenStandard)
+	}
+	b = appendFlag(b, m.data.collection != nil)
+	if c := m.data.collection; c != nil {
+		b = appendFlag(b, c.verified)
+		b = append(b, c.key.Bytes()...)
+	}
+	b = appendFlag(b, m.data.uses != nil)
+	if u := m.data.uses; u != nil {
+		b = append(b, u.method)
+		b = binary.LittleEndian.AppendUint64(b, u.remaining)
+		b = binary.LittleEndian.AppendUint64(b, u.total)
+	}
+	return append(b, make([]byte, metadataSize-len(b))...)
+}
+
+func processTokenMetadata(inv *invocation) error {
+	if len(inv.data) == 0 {
+		return customErr(metadataErrInstructionUnpack)
+	}
+	r := &borshReader{data: inv.data, off: 1}
+	switch inv.data[0] {
+	case 33: // CreateMetadataAccountV3
+		inv.log("IX: Create Metadata Accounts v3")
+		data := r.dataV2()
+		isMutable := r.flag()
+		if r.flag() {
+			// collection details mark sized collections, which the fake does not track
+			r.u8()
+			r.u64()
+		}
+		if r.err != nil {
+			return customErr(metadataErrInstructionUnpack)
+		}
+		return createMetadata(inv, data, isMutable)
+	case 15: // UpdateMetadataAccountV2
+		inv.log("IX: Update Metadata Accounts v2")
+		var data *dataV2
+		if r.flag() {
+			d := r.dataV2()
+			data = &d
+		}
+		var newAuthority *common.PublicKey
+		if r.flag() {
+			k := r.key()
+			newAuthority = &k
+		}
+		var primarySale, isMutable *bool
+		if r.flag() {
+			v := r.flag()
+			primarySale = &v
+		}
+		if r.flag() {
+			v := r.flag()
+			isMutable = &v
+		}
+		if r.err != nil {
+			return customErr(metadataErrInstructionUnpack)
+		}
+		return updateMetadata(inv, data, newAuthority, primarySale, isMutable)
+	case 17: // CreateMasterEditionV3
+		inv.log("IX: Create Master Edition v3")
+		var maxSupply *uint64
+		if r.flag() {
+			v := r.u64()
+			maxSupply = &v
+		}
+		if r.err != nil {
+			return customErr(metadataErrInstructionUnpack)
+		}
+		return createMasterEdition(inv, maxSupply)
+	default:
+		return customErr(metadataErrInstructionUnpack)
+	}
+}
+
+func metadataAddress(mint common.PublicKey) (common.PublicKey, error) {
+	pda, _, err := common.FindProgramAddress(
+		[][]byte{[]byte("metadata"), TokenMetadataProgramID.Bytes(), mint.Bytes()},
+		TokenMetadataProgramID,
+	)
+	return pda, err
+}
+
+func editionAddress(mint common.PublicKey) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress(
+		[][]byte{[]byte("metadata"), TokenMetadataProgramID.Bytes(), mint.Bytes(), []byte("edition")},
+		TokenMetadataProgramID,
+	)
+}
+
+// validateData applies the Metaplex limits on names, shares and royalties.
+func validateData(inv *invocation, d dataV2) error {
+	switch {
+	case len(d.name) > metadataMaxNameLength:
+		return customErr(metadataErrNameTooLong)
+	case len(d.symbol) > metadataMaxSymbolLength:
+		return customErr(metadataErrSymbolTooLong)
+	case len(d.uri) > metadataMaxURILength:
+		return customErr(metadataErrURITooLong)
+	case d.sellerFeeBasisPoints > 10_000:
+		inv.log("Basis points cannot be more than 10000")
+		return builtinErr("InvalidArgument")
+	}
+	if d.creators == nil {
+		return nil
+	}
+	if len(d.creators) == 0 || len(d.creators) > metadataMaxCreators {
+		inv.log("Creators must be between 1 and %d", metadataMaxCreators)
+		return builtinErr("InvalidArgument")
+	}
+	total := 0
+	seen := map[common.PublicKey]bool{}
+	for _, c := range d.creators {
+		if seen[c.address] {
+			inv.log("Duplicate creator address")
+			return builtinErr("InvalidArgument")
+		}
+		seen[c.address] = true
+		total += int(c.share)
+	}
+	if total != 100 {
+		inv.log("Share total must equal 100 for creator array")
+		return builtinErr("InvalidArgument")
+	}
+	return nil
+}
+
+// checkCreatorVerification only lets the signing update authority change its own verification.
+func checkCreatorVerification(inv *invocation, before []creator, after []creator, authority common.PublicKey, signed bool) error {
+	verified := map[common.PublicKey]bool{}
+	for _, c := range before {
+		verified[c.address] = c.verified
+	}
+	for _, c := range after {
+		if c.verified != verified[c.address] && (c.address != authority || !signed) {
+			inv.log("Cannot change the verification of another creator")
+			return builtinErr("InvalidArgument")
+		}
+	}
+	return nil
+}
+
+func createMetadata(inv *invocation, data dataV2, isMutable bool) error {
+	if len(inv.keys) < 5 {
+		return builtinErr("NotEnoughAccountKeys")
+	}
+	mintKey, mintAuthority, updateAuthority := inv.keys[1], inv.keys[2], inv.keys[4]
+	expected, err := metadataAddress(mintKey)
+	if err != nil || expected != inv.keys[0] {
+		return customErr(metadataErrInvalidMetadataKey)
+	}
+	meta, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	if meta.Owner == TokenMetadataProgramID {
+		return customErr(metadataErrAlreadyInitialized)
+	}
+	if meta.Lamports > 0 || len(meta.Data) > 0 {
+		return customErr(systemErrAccountAlreadyInUse)
+	}
+
+	mintAcc, err := inv.account(1)
+	if err != nil {
+		return err
+	}
+	if !isTokenProgram(mintAcc.Owner) {
+		return builtinErr("IncorrectProgramId")
+	}
+	mint, ok := unpackMint(mintAcc.Data)
+	if !ok {
+		return builtinErr("UninitializedAccount")
+	}
+	if mint.mintAuthority == nil || *mint.mintAuthority != mintAuthority {
+		return customErr(metadataErrInvalidMintAuthority)
+	}
+	if !inv.signers[2] {
+		return customErr(metadataErrNotMintAuthority)
+	}
+	if err := validateData(inv, data); err != nil {
+		return err
+	}
+	if err := checkCreatorVerification(inv, nil, data.creators, updateAuthority, inv.signers[4]); err != nil {
+		return err
+	}
+	if data.collection != nil && data.collection.verified {
+		inv.log("Collections are verified with VerifyCollection")
+		return builtinErr("InvalidArgument")
+	}
+
+	if err := inv.requireSigner(3); err != nil {
+		return err
+	}
+	payer, err := inv.mutable(3)
+	if err != nil {
+		return err
+	}
+	rent := RentExemptMinimum(metadataSize)
+	if payer.Lamports < rent {
+		return customErr(systemErrResultWithNegativeLamports)
+	}
+	payer.Lamports -= rent
+	meta.Lamports += rent
+	meta.Owner = TokenMetadataProgramID
+
+	_, nonce, err := editionAddress(mintKey)
+	if err != nil {
+		return builtinErr("InvalidSeeds")
+	}
+	standard := uint8(tokenStandardFungible)
+	if mint.decimals == 0 {
+		standard = tokenStandardFungibleAsset
+	}
+	editionNonce := uint8(nonce)
+	meta.Data = packMetadata(metadata{
+		updateAuthority: updateAuthority,
+		mint:            mintKey,
+		data:            data,
+		isMutable:       isMutable,
+		editionNonce:    &editionNonce,
+		tokenStandard:   &standard,
+	})
+	return nil
+}
+
+func updateMetadata(inv *invocation, data *dataV2, newAuthority *common.PublicKey, primarySale, isMutable *bool) error {
+	acc, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	m, ok := unpackMetadata(acc)
+	if !ok {
+		return customErr(metadataErrUninitialized)
+	}
+	authority, err := inv.key(1)
+	if err != nil {
+		return err
+	}
+	if authority != m.updateAuthority {
+		return customErr(metadataErrUpdateAuthorityIncorrect)
+	}
+	if !inv.signers[1] {
+		return customErr(metadataErrUpdateAuthorityNotSigner)
+	}
+
+	if data != nil {
+		if !m.isMutable {
+			inv.log("Data is immutable")
+			return builtinErr("InvalidArgument")
+		}
+		if err := validateData(inv, *data); err != nil {
+			return err
+		}
+		if err := checkCreatorVerification(inv, m.data.creators, data.creators, authority, true); err != nil {
+			return err
+		}
+		if c := data.collection; c != nil && c.verified &&
+			(m.data.collection == nil || !m.data.collection.verified || m.data.collection.key != c.key) {
+			inv.log("Collections are verified with VerifyCollection")
+			return builtinErr("InvalidArgument")
+		}
+		m.data = *data
+	}
+	if newAuthority != nil {
+		m.updateAuthority = *newAuthority
+	}
+	if primarySale != nil {
+		if !*primarySale && m.primarySaleHappened {
+			inv.log("Primary sale can only be flipped to true")
+			return builtinErr("InvalidArgument")
+		}
+		m.primarySaleHappened = *primarySale
+	}
+	if isMutable != nil {
+		if *isMutable && !m.isMutable {
+			inv.log("Is mutable can only be flipped to false")
+			return builtinErr("InvalidArgument")
+		}
+		m.isMutable = *isMutable
+	}
+	acc.Data = packMetadata(m)
+	return nil
+}
+
+// createMasterEdition turns a mint with a single token into a master edition. The program
+// takes over the mint and freeze authorities, as Metaplex does through the token program.
+func createMasterEdition(inv *invocation, maxSupply *uint64) error {
+	if len(inv.keys) < 6 {
+		return builtinErr("NotEnoughAccountKeys")
+	}
+	editionKey, mintKey, updateAuthority, mintAuthority := inv.keys[0], inv.keys[1], inv.keys[2], inv.keys[3]
+	expected, _, err := editionAddress(mintKey)
+	if err != nil || expected != editionKey {
+		return customErr(metadataErrInvalidEditionKey)
+	}
+	edition, err := inv.mutable(0)
+	if err != nil {
+		return err
+	}
+	if edition.Owner == TokenMetadataProgramID {
+		return customErr(metadataErrAlreadyInitialized)
+	}
+	if edition.Lamports > 0 || len(edition.Data) > 0 {
+		return customErr(systemErrAccountAlreadyInUse)
+	}
+
+	metaAcc, err := inv.mutable(5)
+	if err != nil {
+		return err
+	}
+	meta, ok := unpackMetadata(metaAcc)
+	if !ok {
+		return customErr(metadataErrUninitialized)
+	}
+	if meta.mint != mintKey {
+		return customErr(metadataErrMintMismatch)
+	}
+	if meta.updateAuthority != updateAuthority {
+		return customErr(metadataErrUpdateAuthorityIncorrect)
+	}
+	if !inv.signers[2] {
+		return customErr(metadataErrUpdateAuthorityNotSigner)
+	}
+
+	mintAcc, err := inv.mutable(1)
+	if err != nil {
+		return err
+	}
+	if !isTokenProgram(mintAcc.Owner) {
+		return builtinErr("IncorrectProgramId")
+	}
+	mint, ok := unpackMint(mintAcc.Data)
+	if !ok {
+		return builtinErr("UninitializedAccount")
+	}
+	if mint.mintAuthority == nil || *mint.mintAuthority != mintAuthority {
+		return customErr(metadataErrInvalidMintAuthority)
+	}
+	if !inv.signers[3] {
+		return customErr(metadataErrNotMintAuthority)
+	}
+	if mint.decimals != 0 {
+		return customErr(metadataErrEditionDecimalsNotZero)
+	}
+	if mint.supply != 1 {
+		return customErr(metadataErrEditionsExactlyOneToken)
+	}
+
+	if err := inv.requireSigner(4); err != nil {
+		return err
+	}
+	payer, err := inv.mutable(4)
+	if err != nil {
+		return err
+	}
+	rent := RentExemptMinimum(masterEditionSize)
+	if payer.Lamports < rent {
+		return customErr(systemErrResultWithNegativeLamports)
+	}
+	payer.Lamports -= rent
+	edition.Lamports += rent
+	edition.Owner = TokenMetadataProgramID
+	edition.Data = make([]byte, masterEditionSize)
+	edition.Data[0] = masterEditionKeyV2
+	if maxSupply != nil {
+		edition.Data[9] = 1
+		putUint64(edition.Data[10:18], *maxSupply)
+	}
+
+	mint.mintAuthority = &editionKey
+	if mint.freezeAuthority != nil {
+		mint.freezeAuthority = &editionKey
+	}
+	packMint(mintAcc.Data, mint)
+	standard := uint8(tokenStandardNonFungible)
+	meta.tokenStandard = &standard
+	metaAcc.Data = packMetadata(meta)
+	return nil
+}
@@ -0,0 +1,600 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	json "github.com/goccy/go-json"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/signer"
+)
+
+// A stake account holds a StakeStateV2: a u32 tag, the meta (rent-exempt reserve u64, staker,
+// withdrawer, lockup i64 timestamp, u64 epoch and custodian) and, when delegated, the vote
+// account, stake u64, activation and deactivation epochs u64
+const (
+	stakeAccountSize = 200
+
+	stakeStateUninitialized = 0
+	stakeStateInitialized   = 1
+	stakeStateStake         = 2
+)
+
+// Stake program instructions, a u32 LE index
+const (
+	stakeInstructionInitialize = 0
+	stakeInstructionDelegate   = 2
+	stakeInstructionSplit      = 3
+	stakeInstructionWithdraw   = 4
+	stakeInstructionDeactivate = 5
+	stakeInstructionMerge      = 7
+)
+
+const (
+	// maxSeedLength bounds the seed of CreateAccountWithSeed
+	maxSeedLength = 32
+
+	// warmupCooldownRate is the share of the cluster's effective stake that may activate or
+	// deactivate per epoch
+	warmupCooldownRate = 0.09
+
+	// noEpoch is the deactivation epoch of a delegation that is not deactivating
+	noEpoch = math.MaxUint64
+)
+
+// CreateStakeAccount creates and initializes a stake account holding the requested lamports
+// above its rent-exempt reserve; returns its address and the signature
+func (c *Client) CreateStakeAccount(ctx context.Context, req models.CreateStakeAccountRequest) (string, string, error) {
+	op, address, err := c.createStakeAccount(ctx, req)
+	if err != nil {
+		return "", "", err
+	}
+	sig, err := c.run(ctx, op)
+	if err != nil {
+		return "", "", err
+	}
+	return address, sig, nil
+}
+
+func (c *Client) createStakeAccount(ctx context.Context, req models.CreateStakeAccountRequest) (operation, string, error) {
+	payer, err := signerPublicKey(req.Payer)
+	if err != nil {
+		return operation{}, "", err
+	}
+	staker, withdrawer := payer, payer
+	if req.Staker != "" {
+		if staker, err = publicKey(req.Staker); err != nil {
+			return operation{}, "", err
+		}
+	}
+	if req.Withdrawer != "" {
+		if withdrawer, err = publicKey(req.Withdrawer); err != nil {
+			return operation{}, "", err
+		}
+	}
+
+	// Initialize: 0 (u32 LE), staker, withdrawer, lockup i64 timestamp, u64 epoch, custodian
+	data := binary.LittleEndian.AppendUint32(nil, stakeInstructionInitialize)
+	data = append(data, staker.Bytes()...)
+	data = append(data, withdrawer.Bytes()...)
+	var custodian common.PublicKey
+	var until int64
+	var epoch uint64
+	if l := req.Lockup; l != nil {
+		if !l.Until.IsZero() {
+			until = l.Until.Unix()
+		}
+		epoch = l.Epoch
+		if l.Custodian != "" {
+			if custodian, err = publicKey(l.Custodian); err != nil {
+				return operation{}, "", err
+			}
+		}
+	}
+	data = binary.LittleEndian.AppendUint64(data, uint64(until))
+	data = binary.LittleEndian.AppendUint64(data, epoch)
+	data = append(data, custodian.Bytes()...)
+
+	rent, err := c.GetMinimumBalanceForRentExemption(ctx, models.RentRequest{DataLen: stakeAccountSize})
+	if err != nil {
+		return operation{}, "", err
+	}
+	create, address, generated, err := createStakeAccountInstruction(payer, req.Seed, rent+req.Lamports)
+	if err != nil {
+		return operation{}, "", err
+	}
+	initialize := types.Instruction{
+		ProgramID: common.StakeProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: address, IsSigner: false, IsWritable: true},
+			{PubKey: common.SysVarRentPubkey, IsSigner: false, IsWritable: false},
+		},
+		Data: data,
+	}
+	return operation{
+		opts:         req.SendOptions,
+		feePayer:     req.Payer,
+		instructions: []types.Instruction{create, initialize},
+		generated:    generated,
+	}, address.ToBase58(), nil
+}
+
+// createStakeAccountInstruction allocates a stake account funded by payer: at the address derived
+// from payer and seed, or at a generated keypair when seed is empty
+func createStakeAccountInstruction(payer common.PublicKey, seed string, lamports uint64) (types.Instruction, common.PublicKey, []models.Signer, error) {
+	if seed == "" {
+		account := signer.NewKeypair()
+		address := common.PublicKeyFromString(account.PublicKey())
+		// CreateAccount: 0 (u32 LE), lamports u64, space u64, owner pubkey
+		data := []byte{0, 0, 0, 0}
+		data = binary.LittleEndian.AppendUint64(data, lamports)
+		data = binary.LittleEndian.AppendUint64(data, stakeAccountSize)
+		data = append(data, common.StakeProgramID.Bytes()...)
+		return types.Instruction{
+			ProgramID: common.SystemProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: payer, IsSigner: true, IsWritable: true},
+				{PubKey: address, IsSigner: true, IsWritable: true},
+			},
+			Data: data,
+		}, address, []models.Signer{account}, nil
+	}
+	if len(seed) > maxSeedLength {
+		return types.Instruction{}, common.PublicKey{}, nil, fmt.Errorf("seed is %d bytes, at most %d are allowed", len(seed), maxSeedLength)
+	}
+	address := common.CreateWithSeed(payer, seed, common.StakeProgramID)
+	// CreateAccountWithSeed: 3 (u32 LE), base pubkey, seed as u64 length and bytes, lamports u64,
+	// space u64, owner pubkey; the payer is the base and signs for the new address
+	data := []byte{3, 0, 0, 0}
+	data = append(data, payer.Bytes()...)
+	data = binary.LittleEndian.AppendUint64(data, uint64(len(seed)))
+	data = append(data, seed...)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	data = binary.LittleEndian.AppendUint64(data, stakeAccountSize)
+	data = append(data, common.StakeProgramID.Bytes()...)
+	return types.Instruction{
+		ProgramID: common.SystemProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: payer, IsSigner: true, IsWritable: true},
+			{PubKey: address, IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}, address, nil, nil
+}
+
+// DelegateStake delegates the stake of an account to a validator's vote account. The stake
+// activates at the next epoch boundary.
+func (c *Client) DelegateStake(ctx context.Context, req models.DelegateStakeRequest) (string, error) {
+	op, err := c.delegateStake(req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) delegateStake(req models.DelegateStakeRequest) (operation, error) {
+	staker, err := signerPublicKey(req.Staker)
+	if err != nil {
+		return operation{}, err
+	}
+	stake, err := publicKey(req.StakeAccount)
+	if err != nil {
+		return operation{}, err
+	}
+	vote, err := publicKey(req.VoteAccount)
+	if err != nil {
+		return operation{}, err
+	}
+	inst := types.Instruction{
+		ProgramID: common.StakeProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: stake, IsSigner: false, IsWritable: true},
+			{PubKey: vote, IsSigner: false, IsWritable: false},
+			{PubKey: common.SysVarClockPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: common.SysVarStakeHistoryPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: common.StakeConfigPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: staker, IsSigner: true, IsWritable: false},
+		},
+		Data: binary.LittleEndian.AppendUint32(nil, stakeInstructionDelegate),
+	}
+	return operation{opts: req.SendOptions, feePayer: req.Staker, instructions: []types.Instruction{inst}}, nil
+}
+
+// DeactivateStake starts cooling down a delegation; its lamports can be withdrawn once it is inactive
+func (c *Client) DeactivateStake(ctx context.Context, req models.DeactivateStakeRequest) (string, error) {
+	op, err := c.deactivateStake(req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) deactivateStake(req models.DeactivateStakeRequest) (operation, error) {
+	staker, err := signerPublicKey(req.Staker)
+	if err != nil {
+		return operation{}, err
+	}
+	stake, err := publicKey(req.StakeAccount)
+	if err != nil {
+		return operation{}, err
+	}
+	inst := types.Instruction{
+		ProgramID: common.StakeProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: stake, IsSigner: false, IsWritable: true},
+			{PubKey: common.SysVarClockPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: staker, IsSigner: true, IsWritable: false},
+		},
+		Data: binary.LittleEndian.AppendUint32(nil, stakeInstructionDeactivate),
+	}
+	return operation{opts: req.SendOptions, feePayer: req.Staker, instructions: []types.Instruction{inst}}, nil
+}
+
+// WithdrawStake moves lamports that are neither staked nor the rent-exempt reserve out of a
+// stake account, or the whole balance of an inactive one
+func (c *Client) WithdrawStake(ctx context.Context, req models.WithdrawStakeRequest) (string, error) {
+	op, err := c.withdrawStake(req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) withdrawStake(req models.WithdrawStakeRequest) (operation, error) {
+	withdrawer, err := signerPublicKey(req.Withdrawer)
+	if err != nil {
+		return operation{}, err
+	}
+	stake, err := publicKey(req.StakeAccount)
+	if err != nil {
+		return operation{}, err
+	}
+	to, err := publicKey(req.ToPublicKey)
+	if err != nil {
+		return operation{}, err
+	}
+
+	// Withdraw: 4 (u32 LE), lamports u64
+	inst := types.Instruction{
+		ProgramID: common.StakeProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: stake, IsSigner: false, IsWritable: true},
+			{PubKey: to, IsSigner: false, IsWritable: true},
+			{PubKey: common.SysVarClockPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: common.SysVarStakeHistoryPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: withdrawer, IsSigner: true, IsWritable: false},
+		},
+		Data: binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint32(nil, stakeInstructionWithdraw), req.Lamports),
+	}
+	op := operation{opts: req.SendOptions, feePayer: req.Withdrawer}
+	if req.Custodian != nil {
+		custodian, err := signerPublicKey(req.Custodian)
+		if err != nil {
+			return operation{}, err
+		}
+		inst.Accounts = append(inst.Accounts, types.AccountMeta{PubKey: custodian, IsSigner: true, IsWritable: false})
+		op.signers = []models.Signer{req.Custodian}
+	}
+	op.instructions = []types.Instruction{inst}
+	return op, nil
+}
+
+// SplitStake moves part of a delegation into a new stake account with the same authorities,
+// lockup and activation; returns the new address and the signature
+func (c *Client) SplitStake(ctx context.Context, req models.SplitStakeRequest) (string, string, error) {
+	op, address, err := c.splitStake(ctx, req)
+	if err != nil {
+		return "", "", err
+	}
+	sig, err := c.run(ctx, op)
+	if err != nil {
+		return "", "", err
+	}
+	return address, sig, nil
+}
+
+func (c *Client) splitStake(ctx context.Context, req models.SplitStakeRequest) (operation, string, error) {
+	staker, err := signerPublicKey(req.Staker)
+	if err != nil {
+		return operation{}, "", err
+	}
+	stake, err := publicKey(req.StakeAccount)
+	if err != nil {
+		return operation{}, "", err
+	}
+	// the new account must hold its own reserve before the split, so that all moved lamports are stake
+	rent, err := c.GetMinimumBalanceForRentExemption(ctx, models.RentRequest{DataLen: stakeAccountSize})
+	if err != nil {
+		return operation{}, "", err
+	}
+	create, address, generated, err := createStakeAccountInstruction(staker, req.Seed, rent)
+	if err != nil {
+		return operation{}, "", err
+	}
+
+	// Split: 3 (u32 LE), lamports u64
+	split := types.Instruction{
+		ProgramID: common.StakeProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: stake, IsSigner: false, IsWritable: true},
+			{PubKey: address, IsSigner: false, IsWritable: true},
+			{PubKey: staker, IsSigner: true, IsWritable: false},
+		},
+		Data: binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint32(nil, stakeInstructionSplit), req.Lamports),
+	}
+	return operation{
+		opts:         req.SendOptions,
+		feePayer:     req.Staker,
+		instructions: []types.Instruction{create, split},
+		generated:    generated,
+	}, address.ToBase58(), nil
+}
+
+// MergeStake drains a stake account into another and closes it
+func (c *Client) MergeStake(ctx context.Context, req models.MergeStakeRequest) (string, error) {
+	op, err := c.mergeStake(req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) mergeStake(req models.MergeStakeRequest) (operation, error) {
+	staker, err := signerPublicKey(req.Staker)
+	if err != nil {
+		return operation{}, err
+	}
+	destination, err := publicKey(req.Destination)
+	if err != nil {
+		return operation{}, err
+	}
+	source, err := publicKey(req.Source)
+	if err != nil {
+		return operation{}, err
+	}
+	if destination == source {
+		return operation{}, fmt.Errorf("cannot merge stake account %s into itself", req.Source)
+	}
+	inst := types.Instruction{
+		ProgramID: common.StakeProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: destination, IsSigner: false, IsWritable: true},
+			{PubKey: source, IsSigner: false, IsWritable: true},
+			{PubKey: common.SysVarClockPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: common.SysVarStakeHistoryPubkey, IsSigner: false, IsWritable: false},
+			{PubKey: staker, IsSigner: true, IsWritable: false},
+		},
+		Data: binary.LittleEndian.AppendUint32(nil, stakeInstructionMerge),
+	}
+	return operation{opts: req.SendOptions, feePayer: req.Staker, instructions: []types.Instruction{inst}}, nil
+}
+
+// GetStakeAccount returns the authorities, lockup and delegation of a stake account
+func (c *Client) GetStakeAccount(ctx context.Context, req models.GetStakeAccountRequest) (*models.StakeAccount, error) {
+	if _, err := publicKey(req.Address); err != nil {
+		return nil, err
+	}
+	acc, err := c.c.GetAccountInfo(ctx, req.Address)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	if acc.Lamports == 0 {
+		return nil, accountError(req.Address, ErrAccountNotFound)
+	}
+	if acc.Owner != common.StakeProgramID || len(acc.Data) != stakeAccountSize {
+		return nil, accountError(req.Address, fmt.Errorf("%w: not a stake account", ErrInvalidAccountData))
+	}
+	return parseStakeAccount(req.Address, acc.Lamports, acc.Data)
+}
+
+func parseStakeAccount(address string, lamports uint64, data []byte) (*models.StakeAccount, error) {
+	sa := &models.StakeAccount{Address: address, Lamports: lamports}
+	switch binary.LittleEndian.Uint32(data[0:4]) {
+	case stakeStateUninitialized:
+		sa.State = models.StakeStateUninitialized
+		return sa, nil
+	case stakeStateInitialized:
+		sa.State = models.StakeStateInitialized
+	case stakeStateStake:
+		sa.State = models.StakeStateDelegated
+	default:
+		return nil, accountError(address, fmt.Errorf("%w: unsupported stake account state", ErrInvalidAccountData))
+	}
+	sa.RentExemptReserve = binary.LittleEndian.Uint64(data[4:12])
+	sa.Staker = base58.Encode(data[12:44])
+	sa.Withdrawer = base58.Encode(data[44:76])
+	if until := int64(binary.LittleEndian.Uint64(data[76:84])); until != 0 {
+		sa.Lockup.Until = time.Unix(until, 0)
+	}
+	sa.Lockup.Epoch = binary.LittleEndian.Uint64(data[84:92])
+	if custodian := common.PublicKeyFromBytes(data[92:124]); custodian != (common.PublicKey{}) {
+		sa.Lockup.Custodian = custodian.ToBase58()
+	}
+	if sa.State == models.StakeStateDelegated {
+		sa.Delegation = &models.StakeDelegation{
+			VoteAccount:     base58.Encode(data[124:156]),
+			Stake:           binary.LittleEndian.Uint64(data[156:164]),
+			ActivationEpoch: binary.LittleEndian.Uint64(data[164:172]),
+		}
+		if deactivation := binary.LittleEndian.Uint64(data[172:180]); deactivation != noEpoch {
+			sa.Delegation.DeactivationEpoch = &deactivation
+		}
+	}
+	return sa, nil
+}
+
+// GetStakeActivation reports how much of a stake account is active in the current epoch. Nodes
+// no longer serve getStakeActivation, so it is computed from the stake history sysvar.
+func (c *Client) GetStakeActivation(ctx context.Context, req models.GetStakeActivationRequest) (*models.StakeActivation, error) {
+	sa, err := c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: req.Address})
+	if err != nil {
+		return nil, err
+	}
+	info, err := c.c.GetEpochInfo(ctx)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	activation := &models.StakeActivation{Address: req.Address, Epoch: info.Epoch, State: models.StakeInactive}
+	var effective uint64
+	if d := sa.Delegation; d != nil {
+		sysvar, err := c.c.GetAccountInfo(ctx, common.SysVarStakeHistoryPubkey.ToBase58())
+		if err != nil {
+			return nil, rpcError(err)
+		}
+		effective, activation.Activating, activation.Deactivating = delegationStatus(*d, info.Epoch, parseStakeHistory(sysvar.Data))
+		switch {
+		case activation.Deactivating > 0:
+			activation.State = models.StakeDeactivating
+		case activation.Activating > 0:
+			activation.State = models.StakeActivating
+		case effective > 0:
+			activation.State = models.StakeActive
+		}
+	}
+	activation.Active = effective
+	if sa.Lamports > sa.RentExemptReserve+effective {
+		activation.Inactive = sa.Lamports - sa.RentExemptReserve - effective
+	}
+	return activation, nil
+}
+
+// stakeHistoryEntry is the cluster's effective, activating and deactivating stake in an epoch
+type stakeHistoryEntry struct {
+	effective    uint64
+	activating   uint64
+	deactivating uint64
+}
+
+// parseStakeHistory reads the stake history sysvar: a u64 count of entries, each an epoch and
+// its effective, activating and deactivating stake as u64
+func parseStakeHistory(data []byte) map[uint64]stakeHistoryEntry {
+	history := map[uint64]stakeHistoryEntry{}
+	if len(data) < 8 {
+		return history
+	}
+	n := binary.LittleEndian.Uint64(data[0:8])
+	for i := uint64(0); i < n && 8+(i+1)*32 <= uint64(len(data)); i++ {
+		b := data[8+i*32:]
+		history[binary.LittleEndian.Uint64(b[0:8])] = stakeHistoryEntry{
+			effective:    binary.LittleEndian.Uint64(b[8:16]),
+			activating:   binary.LittleEndian.Uint64(b[16:24]),
+			deactivating: binary.LittleEndian.Uint64(b[24:32]),
+		}
+	}
+	return history
+}
+
+// delegationStatus returns the effective, activating and deactivating stake of a delegation in
+// an epoch. Each epoch a delegation gets its share, by weight among the stake changing with it,
+// of the warmup or cooldown the cluster allows, as the Stake program computes it.
+func delegationStatus(d models.StakeDelegation, epoch uint64, history map[uint64]stakeHistoryEntry) (effective, activating, deactivating uint64) {
+	deactivation := uint64(noEpoch)
+	if d.DeactivationEpoch != nil {
+		deactivation = *d.DeactivationEpoch
+	}
+	effective, activating = warmup(d, deactivation, epoch, history)
+	switch {
+	case epoch < deactivation:
+		return effective, activating, 0
+	case epoch == deactivation:
+		return effective, 0, effective
+	}
+	prev, ok := history[deactivation]
+	if !ok {
+		return 0, 0, 0
+	}
+	for current := deactivation + 1; prev.deactivating > 0; current++ {
+		weight := float64(effective) / float64(prev.deactivating)
+		cooled := max(uint64(weight*float64(prev.effective)*warmupCooldownRate), 1)
+		if cooled >= effective {
+			return 0, 0, 0
+		}
+		effective -= cooled
+		if current >= epoch {
+			break
+		}
+		if prev, ok = history[current]; !ok {
+			// out of history: the stake still cooling down stays effective
+			break
+		}
+	}
+	return effective, 0, effective
+}
+
+// warmup returns the effective and activating stake of a delegation, ignoring its deactivation
+func warmup(d models.StakeDelegation, deactivation, epoch uint64, history map[uint64]stakeHistoryEntry) (uint64, uint64) {
+	switch {
+	case d.ActivationEpoch == deactivation || epoch < d.ActivationEpoch:
+		return 0, 0
+	case epoch == d.ActivationEpoch:
+		return 0, d.Stake
+	}
+	prev, ok := history[d.ActivationEpoch]
+	if !ok {
+		// older than the history: fully warmed up
+		return d.Stake, 0
+	}
+	var effective uint64
+	for current := d.ActivationEpoch + 1; prev.activating > 0; current++ {
+		weight := float64(d.Stake-effective) / float64(prev.activating)
+		effective += max(uint64(weight*float64(prev.effective)*warmupCooldownRate), 1)
+		if effective >= d.Stake {
+			return d.Stake, 0
+		}
+		if current >= epoch || current >= deactivation {
+			break
+		}
+		if prev, ok = history[current]; !ok {
+			break
+		}
+	}
+	return effective, d.Stake - effective
+}
+
+// inflationRewardConfig sends an explicit epoch 0, which rpc.GetInflationRewardConfig omits
+type inflationRewardConfig struct {
+	Epoch *uint64 `json:"epoch,omitempty"`
+}
+
+// GetStakeRewards returns the inflation reward each stake account received for an epoch, in the
+// order of the request; an entry is nil when the account earned nothing
+func (c *Client) GetStakeRewards(ctx context.Context, req models.GetStakeRewardsRequest) ([]*models.StakeReward, error) {
+	for _, address := range req.StakeAccounts {
+		if _, err := publicKey(address); err != nil {
+			return nil, err
+		}
+	}
+	body, err := c.c.RpcClient.Call(ctx, "getInflationReward", req.StakeAccounts, inflationRewardConfig{Epoch: req.Epoch})
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	var res rpc.JsonRpcResponse[[]*rpc.GetInflationReward]
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, rpcError(res.Error)
+	}
+	rewards := make([]*models.StakeReward, len(req.StakeAccounts))
+	for i, r := range res.Result {
+		if r == nil || i >= len(rewards) {
+			continue
+		}
+		rewards[i] = &models.StakeReward{
+			Address:       req.StakeAccounts[i],
+			Epoch:         r.Epoch,
+			EffectiveSlot: r.EffectiveSlot,
+			Lamports:      r.Amount,
+			PostBalance:   r.PostBalance,
+			Commission:    r.Commission,
+		}
+	}
+	return rewards, nil
+}
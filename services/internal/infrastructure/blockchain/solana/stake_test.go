@@ -0,0 +1,245 @@
+package sdk_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+const lamportsPerSOL = 1_000_000_000
+
+func TestStake_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 10 * lamportsPerSOL}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	vote := srv.CreateVoteAccount(10)
+	reserve := solanatest.RentExemptMinimum(200)
+
+	stake, _, err := c.CreateStakeAccount(ctx, models.CreateStakeAccountRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, alice), Seed: "stake:0", Lamports: 2 * lamportsPerSOL,
+	})
+	if err != nil {
+		t.Fatalf("create stake account failed: %v", err)
+	}
+	var txErr *sdk.TransactionError
+	_, _, err = c.CreateStakeAccount(ctx, models.CreateStakeAccountRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, alice), Seed: "stake:0", Lamports: lamportsPerSOL,
+	})
+	if !errors.As(err, &txErr) || txErr.Name != "AccountAlreadyInUse" {
+		t.Fatalf("expected the seed to be taken, got %v", err)
+	}
+	sa, err := c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: stake})
+	if err != nil {
+		t.Fatalf("get stake account failed: %v", err)
+	}
+	if sa.State != models.StakeStateInitialized || sa.Staker != alice.PublicKey || sa.Withdrawer != alice.PublicKey ||
+		sa.RentExemptReserve != reserve || sa.Lamports != reserve+2*lamportsPerSOL || sa.Delegation != nil {
+		t.Fatalf("unexpected stake account: %+v", sa)
+	}
+	if act, err := c.GetStakeActivation(ctx, models.GetStakeActivationRequest{Address: stake}); err != nil ||
+		act.State != models.StakeInactive || act.Active != 0 || act.Inactive != 2*lamportsPerSOL {
+		t.Fatalf("unexpected activation %+v: %v", act, err)
+	}
+
+	if _, err := c.DelegateStake(ctx, models.DelegateStakeRequest{SendOptions: confirmed, Staker: mustSigner(t, alice), StakeAccount: stake, VoteAccount: vote}); err != nil {
+		t.Fatalf("delegate failed: %v", err)
+	}
+	act, err := c.GetStakeActivation(ctx, models.GetStakeActivationRequest{Address: stake})
+	if err != nil || act.State != models.StakeActivating || act.Activating != 2*lamportsPerSOL || act.Active != 0 {
+		t.Fatalf("unexpected activation %+v: %v", act, err)
+	}
+	split, _, err := c.SplitStake(ctx, models.SplitStakeRequest{SendOptions: confirmed, Staker: mustSigner(t, alice), StakeAccount: stake, Lamports: lamportsPerSOL / 2})
+	if err != nil {
+		t.Fatalf("split failed: %v", err)
+	}
+	if sa, err := c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: split}); err != nil || sa.Delegation == nil ||
+		sa.Delegation.VoteAccount != vote || sa.Delegation.Stake != lamportsPerSOL/2 || sa.Delegation.ActivationEpoch != act.Epoch {
+		t.Fatalf("unexpected split account %+v: %v", sa, err)
+	}
+
+	// both halves warm up at the epoch boundary
+	srv.AdvanceEpochs(1)
+	for address, want := range map[string]uint64{stake: 3 * lamportsPerSOL / 2, split: lamportsPerSOL / 2} {
+		act, err := c.GetStakeActivation(ctx, models.GetStakeActivationRequest{Address: address})
+		if err != nil || act.State != models.StakeActive || act.Active != want || act.Inactive != 0 {
+			t.Fatalf("unexpected activation of %s %+v: %v", address, act, err)
+		}
+	}
+	_, err = c.WithdrawStake(ctx, models.WithdrawStakeRequest{SendOptions: confirmed, Withdrawer: mustSigner(t, alice), StakeAccount: stake, ToPublicKey: alice.PublicKey, Lamports: 1})
+	if !errors.As(err, &txErr) || txErr.Name != "InsufficientFunds" {
+		t.Fatalf("expected active stake to stay locked, got %v", err)
+	}
+	if _, err := c.MergeStake(ctx, models.MergeStakeRequest{SendOptions: confirmed, Staker: mustSigner(t, alice), Destination: stake, Source: split}); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	if _, err := c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: split}); !errors.Is(err, sdk.ErrAccountNotFound) {
+		t.Fatalf("expected the merged account to be closed, got %v", err)
+	}
+
+	// the merged account keeps the reserve of the split one as unstaked lamports
+	if act, err := c.GetStakeActivation(ctx, models.GetStakeActivationRequest{Address: stake}); err != nil || act.Active != 2*lamportsPerSOL || act.Inactive != reserve {
+		t.Fatalf("unexpected merged activation %+v: %v", act, err)
+	}
+
+	// an epoch of full stake earns its reward, less the validator's commission
+	srv.AdvanceEpochs(1)
+	rewards, err := c.GetStakeRewards(ctx, models.GetStakeRewardsRequest{StakeAccounts: []string{stake, alice.PublicKey}})
+	if err != nil {
+		t.Fatalf("get rewards failed: %v", err)
+	}
+	earned := uint64(2*lamportsPerSOL) * solanatest.StakeRewardBasisPoints / 10_000 * 9 / 10
+	if len(rewards) != 2 || rewards[1] != nil || rewards[0] == nil || rewards[0].Lamports != earned ||
+		rewards[0].Commission == nil || *rewards[0].Commission != 10 || rewards[0].PostBalance != 2*reserve+2*lamportsPerSOL+earned {
+		t.Fatalf("unexpected rewards: %+v", rewards)
+	}
+	epoch := rewards[0].Epoch
+	if again, err := c.GetStakeRewards(ctx, models.GetStakeRewardsRequest{StakeAccounts: []string{stake}, Epoch: &epoch}); err != nil || again[0] == nil || again[0].Lamports != earned {
+		t.Fatalf("unexpected rewards of epoch %d %+v: %v", epoch, again, err)
+	}
+	// the stake was still warming up in epoch 0, which has to be asked for explicitly
+	zero := uint64(0)
+	if first, err := c.GetStakeRewards(ctx, models.GetStakeRewardsRequest{StakeAccounts: []string{stake}, Epoch: &zero}); err != nil || len(first) != 1 || first[0] != nil {
+		t.Fatalf("unexpected rewards of epoch 0 %+v: %v", first, err)
+	}
+
+	if _, err := c.DeactivateStake(ctx, models.DeactivateStakeRequest{SendOptions: confirmed, Staker: mustSigner(t, alice), StakeAccount: stake}); err != nil {
+		t.Fatalf("deactivate failed: %v", err)
+	}
+	_, err = c.DeactivateStake(ctx, models.DeactivateStakeRequest{SendOptions: confirmed, Staker: mustSigner(t, alice), StakeAccount: stake})
+	if !errors.As(err, &txErr) || txErr.Name != "AlreadyDeactivated" {
+		t.Fatalf("expected a second deactivation to fail, got %v", err)
+	}
+	if act, err := c.GetStakeActivation(ctx, models.GetStakeActivationRequest{Address: stake}); err != nil ||
+		act.State != models.StakeDeactivating || act.Deactivating != 2*lamportsPerSOL+earned {
+		t.Fatalf("unexpected activation %+v: %v", act, err)
+	}
+
+	srv.AdvanceEpochs(1)
+	sa, err = c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: stake})
+	if err != nil || sa.Delegation == nil || sa.Delegation.DeactivationEpoch == nil {
+		t.Fatalf("unexpected stake account %+v: %v", sa, err)
+	}
+	if act, err := c.GetStakeActivation(ctx, models.GetStakeActivationRequest{Address: stake}); err != nil || act.State != models.StakeInactive || act.Active != 0 {
+		t.Fatalf("unexpected activation %+v: %v", act, err)
+	}
+	before, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: alice.PublicKey})
+	if err != nil {
+		t.Fatalf("get balance failed: %v", err)
+	}
+	if _, err := c.WithdrawStake(ctx, models.WithdrawStakeRequest{
+		SendOptions: confirmed, Withdrawer: mustSigner(t, alice), StakeAccount: stake, ToPublicKey: alice.PublicKey, Lamports: sa.Lamports,
+	}); err != nil {
+		t.Fatalf("withdraw failed: %v", err)
+	}
+	after, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: alice.PublicKey})
+	if err != nil || after != before+sa.Lamports-solanatest.LamportsPerSignature {
+		t.Fatalf("expected %d back, balance went from %d to %d: %v", sa.Lamports, before, after, err)
+	}
+	if _, err := c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: stake}); !errors.Is(err, sdk.ErrAccountNotFound) {
+		t.Fatalf("expected the emptied account to be closed, got %v", err)
+	}
+}
+
+func TestStake_CooldownPastTheHistory(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 10 * lamportsPerSOL}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	stake, _, err := c.CreateStakeAccount(ctx, models.CreateStakeAccountRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, alice), Seed: "stake:0", Lamports: 2 * lamportsPerSOL,
+	})
+	if err != nil {
+		t.Fatalf("create stake account failed: %v", err)
+	}
+	if _, err := c.DelegateStake(ctx, models.DelegateStakeRequest{
+		SendOptions: confirmed, Staker: mustSigner(t, alice), StakeAccount: stake, VoteAccount: srv.CreateVoteAccount(0),
+	}); err != nil {
+		t.Fatalf("delegate failed: %v", err)
+	}
+	srv.AdvanceEpochs(1)
+	if _, err := c.DeactivateStake(ctx, models.DeactivateStakeRequest{SendOptions: confirmed, Staker: mustSigner(t, alice), StakeAccount: stake}); err != nil {
+		t.Fatalf("deactivate failed: %v", err)
+	}
+	srv.AdvanceEpochs(3)
+	sa, err := c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: stake})
+	if err != nil || sa.Delegation == nil || sa.Delegation.DeactivationEpoch == nil {
+		t.Fatalf("unexpected stake account %+v: %v", sa, err)
+	}
+
+	// the history only keeps the deactivation epoch, in which the stake was half the cluster's
+	deactivation, delegated := *sa.Delegation.DeactivationEpoch, sa.Delegation.Stake
+	sysvar, _ := srv.GetAccount(common.SysVarStakeHistoryPubkey.ToBase58())
+	data := make([]byte, len(sysvar.Data))
+	entry := binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(nil, 1), deactivation)
+	entry = binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(entry, 2*delegated), 0)
+	copy(data, binary.LittleEndian.AppendUint64(entry, delegated))
+	sysvar.Data = data
+	srv.SetAccount(common.SysVarStakeHistoryPubkey.ToBase58(), sysvar)
+
+	// one epoch of cooldown is known; the rest stays effective, as the Stake program keeps it
+	want := delegated - uint64(float64(2*delegated)*0.09)
+	act, err := c.GetStakeActivation(ctx, models.GetStakeActivationRequest{Address: stake})
+	if err != nil || act.State != models.StakeDeactivating || act.Active != want || act.Deactivating != want {
+		t.Fatalf("expected %d still cooling down, got %+v: %v", want, act, err)
+	}
+}
+
+func TestStake_LockupNeedsCustodian(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	alice := c.CreateAccount()
+	custodian := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: alice.PublicKey, Lamports: 10 * lamportsPerSOL}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+	stake, _, err := c.CreateStakeAccount(ctx, models.CreateStakeAccountRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, alice), Lamports: lamportsPerSOL,
+		Lockup: &models.StakeLockup{Epoch: 100, Custodian: custodian.PublicKey},
+	})
+	if err != nil {
+		t.Fatalf("create stake account failed: %v", err)
+	}
+	sa, err := c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: stake})
+	if err != nil || sa.Lockup.Epoch != 100 || sa.Lockup.Custodian != custodian.PublicKey || !sa.Lockup.Until.IsZero() {
+		t.Fatalf("unexpected stake account %+v: %v", sa, err)
+	}
+
+	var txErr *sdk.TransactionError
+	_, err = c.WithdrawStake(ctx, models.WithdrawStakeRequest{SendOptions: confirmed, Withdrawer: mustSigner(t, alice), StakeAccount: stake, ToPublicKey: alice.PublicKey, Lamports: lamportsPerSOL})
+	if !errors.As(err, &txErr) || txErr.Name != "LockupInForce" {
+		t.Fatalf("expected the lockup to hold, got %v", err)
+	}
+	if _, err := c.WithdrawStake(ctx, models.WithdrawStakeRequest{
+		SendOptions: confirmed, Withdrawer: mustSigner(t, alice), StakeAccount: stake, ToPublicKey: alice.PublicKey, Lamports: lamportsPerSOL,
+		Custodian: mustSigner(t, custodian),
+	}); err != nil {
+		t.Fatalf("custodian withdraw failed: %v", err)
+	}
+	if sa, err := c.GetStakeAccount(ctx, models.GetStakeAccountRequest{Address: stake}); err != nil || sa.Lamports != sa.RentExemptReserve {
+		t.Fatalf("expected only the reserve to remain, got %+v: %v", sa, err)
+	}
+}
@@ -0,0 +1,384 @@
+package sdk
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/rpc"
+	"github.com/blocto/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// Every signed write goes through the submission engine. It records each signed version of a
+// request before broadcasting it, rebroadcasts that exact transaction until it reaches the
+// requested commitment, and signs a new version with a fresh blockhash only once the chain is
+// past the old one's last valid block height without having seen its signature. A failed or
+// timed out send therefore never leads to a second transfer.
+
+const (
+	defaultResendInterval = 2 * time.Second
+	defaultMaxRebuilds    = 2
+)
+
+// errAttemptExpired reports that an attempt can no longer land
+var errAttemptExpired = errors.New("attempt expired")
+
+// WithResend sets how often an unconfirmed transaction is rebroadcast and how many times an
+// expired one may be rebuilt with a fresh blockhash
+func WithResend(interval time.Duration, maxRebuilds int) Option {
+	return func(c *Client) {
+		c.resendInterval = interval
+		c.maxRebuilds = maxRebuilds
+	}
+}
+
+// WithSubmissionStore records submissions in store instead of process memory, so a request
+// retried after a restart resumes its earlier submission
+func WithSubmissionStore(store models.SubmissionStore) Option {
+	return func(c *Client) {
+		c.submissions = store
+	}
+}
+
+// MemorySubmissionStore keeps submissions in process memory
+type MemorySubmissionStore struct {
+	mu   sync.Mutex
+	subs map[string]models.Submission
+}
+
+func NewMemorySubmissionStore() *MemorySubmissionStore {
+	return &MemorySubmissionStore{subs: map[string]models.Submission{}}
+}
+
+func (s *MemorySubmissionStore) LoadSubmission(_ context.Context, key string) (*models.Submission, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subs[key]
+	if !ok {
+		return nil, nil
+	}
+	sub.Attempts = append([]models.SubmissionAttempt(nil), sub.Attempts...)
+	return &sub, nil
+}
+
+func (s *MemorySubmissionStore) SaveSubmission(_ context.Context, sub models.Submission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub.Attempts = append([]models.SubmissionAttempt(nil), sub.Attempts...)
+	s.subs[sub.Key] = sub
+	return nil
+}
+
+// GetSubmission returns the recorded attempts of a write request
+func (c *Client) GetSubmission(ctx context.Context, req models.GetSubmissionRequest) (*models.Submission, error) {
+	sub, err := c.submissions.LoadSubmission(ctx, req.Key)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, fmt.Errorf("submission %s not found", req.Key)
+	}
+	return sub, nil
+}
+
+// signFunc returns a signed version of a submission and the last valid block height of its blockhash
+type signFunc func(ctx context.Context) (types.Transaction, uint64, error)
+
+// send submits a transaction signed elsewhere; it is rebroadcast but never rebuilt
+func (c *Client) send(ctx context.Context, tx types.Transaction, lastValidBlockHeight uint64, opts models.SendOptions) (string, error) {
+	if opts.Simulate != nil {
+		return "", c.simulateInto(ctx, opts, tx)
+	}
+	return c.deliver(ctx, opts, func(context.Context) (types.Transaction, uint64, error) {
+		return tx, lastValidBlockHeight, nil
+	}, false)
+}
+
+// deliver runs the submission engine for one request. rebuildable reports whether sign can
+// produce a new version once an attempt expired.
+func (c *Client) deliver(ctx context.Context, opts models.SendOptions, sign signFunc, rebuildable bool) (string, error) {
+	rank := 0
+	if opts.Commitment != "" {
+		r, ok := commitmentRank[opts.Commitment]
+		if !ok {
+			return "", fmt.Errorf("unknown commitment %q", opts.Commitment)
+		}
+		rank = r
+	}
+
+	sub := &models.Submission{}
+	if key := opts.IdempotencyKey; key != "" {
+		// two calls with one key must not both sign a new version
+		unlock := c.lockKey(key)
+		defer unlock()
+		stored, err := c.submissions.LoadSubmission(ctx, key)
+		if err != nil {
+			return "", err
+		}
+		if stored != nil {
+			sub = stored
+		}
+		sub.Key = key
+	}
+	if sub.Status == models.SubmissionLanded {
+		return sub.Signature, landedError(sub)
+	}
+	sub.Status = models.SubmissionPending
+
+	for {
+		att := liveAttempt(sub)
+		if att == nil {
+			if expired := expiredAttempts(sub); expired > 0 && (!rebuildable || expired > c.maxRebuilds) {
+				sub.Status = models.SubmissionFailed
+				sub.Err = ErrBlockhashExpired.Error()
+				c.saveSubmission(ctx, sub)
+				return "", fmt.Errorf("%w: %s after %d attempts", ErrBlockhashExpired, sub.Key, len(sub.Attempts))
+			}
+			tx, lastValidBlockHeight, err := sign(ctx)
+			if err != nil {
+				return "", err
+			}
+			next, err := newAttempt(tx, lastValidBlockHeight)
+			if err != nil {
+				return "", err
+			}
+			sub.Attempts = append(sub.Attempts, next)
+			att = &sub.Attempts[len(sub.Attempts)-1]
+			if sub.Key == "" {
+				sub.Key = att.Signature
+			}
+			// recorded before it is sent, so a retry can always find it
+			if err := c.submissions.SaveSubmission(ctx, *sub); err != nil {
+				return "", fmt.Errorf("record submission %s: %w", sub.Key, err)
+			}
+		}
+
+		tx, err := attemptTransaction(att)
+		if err != nil {
+			return "", err
+		}
+		var sendErr error
+		if att.Broadcasts == 0 {
+			sendErr = c.broadcast(ctx, att, tx, false)
+			if rejected := c.rejection(ctx, att, tx, sendErr); rejected != nil {
+				att.Rejected = rejected.Error()
+				sub.Status = models.SubmissionFailed
+				sub.Err = att.Rejected
+				var txErr *TransactionError
+				if errors.As(rejected, &txErr) {
+					sub.Error = &txErr.TransactionError
+				}
+				c.saveSubmission(ctx, sub)
+				return "", rejected
+			}
+			c.saveSubmission(ctx, sub)
+		}
+		// a send that failed in transit may still have landed, so it is watched like any other
+		if rank == 0 {
+			return att.Signature, rpcError(sendErr)
+		}
+
+		status, err := c.watch(ctx, sub, att, tx, rank)
+		switch {
+		case errors.Is(err, errAttemptExpired):
+			att.Expired = true
+			c.saveSubmission(ctx, sub)
+		case err != nil:
+			c.saveSubmission(ctx, sub)
+			return att.Signature, err
+		default:
+			sub.Status = models.SubmissionLanded
+			sub.Signature = att.Signature
+			sub.Err, sub.Error = "", nil
+			if status.Err == nil {
+				c.saveSubmission(ctx, sub)
+				return sub.Signature, nil
+			}
+			failure := c.landedFailure(ctx, att.Signature, tx, status.Err)
+			sub.Err = failure.TransactionError.Error()
+			sub.Error = &failure.TransactionError
+			c.saveSubmission(ctx, sub)
+			return sub.Signature, failure
+		}
+	}
+}
+
+// watch polls an attempt until it reaches rank or provably expired, rebroadcasting it while
+// the cluster has not seen it. Lookup and send errors are treated as transient.
+func (c *Client) watch(ctx context.Context, sub *models.Submission, att *models.SubmissionAttempt, tx types.Transaction, rank int) (*rpc.SignatureStatus, error) {
+	delay, maxDelay := c.confirmPollInitial, c.confirmPollMax
+	if delay <= 0 {
+		delay = defaultConfirmPollInitial
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultConfirmPollMax
+	}
+
+	for {
+		// height is read before the status so a missing status past expiry is final
+		var (
+			at        chainHeight
+			heightErr error
+		)
+		if att.LastValidBlockHeight > 0 {
+			at, heightErr = c.chainHeight(ctx)
+		}
+		status, statusSlot, err := c.signatureStatus(ctx, att.Signature)
+		if err == nil {
+			if status != nil && status.ConfirmationStatus != nil && commitmentRank[models.Commitment(*status.ConfirmationStatus)] >= rank {
+				return status, nil
+			}
+			if heightErr == nil && c.unseenPast(ctx, att.Signature, att.LastValidBlockHeight, at, status, statusSlot) {
+				return nil, errAttemptExpired
+			}
+			if status == nil && time.Since(att.LastSentAt) >= c.resendInterval {
+				_ = c.broadcast(ctx, att, tx, true)
+				c.saveSubmission(ctx, sub)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// broadcast sends an attempt once. Rebroadcasts skip preflight, which would reject a
+// transaction the cluster already processed.
+func (c *Client) broadcast(ctx context.Context, att *models.SubmissionAttempt, tx types.Transaction, skipPreflight bool) error {
+	now := time.Now()
+	if att.FirstSentAt.IsZero() {
+		att.FirstSentAt = now
+	}
+	att.LastSentAt = now
+	att.Broadcasts++
+	_, err := c.c.SendTransactionWithConfig(ctx, tx, client.SendTransactionConfig{SkipPreflight: skipPreflight})
+	return err
+}
+
+// rejection returns why the cluster refused the attempt, nil when it may have seen its signature.
+// A failed preflight comes back as a *TransactionError.
+func (c *Client) rejection(ctx context.Context, att *models.SubmissionAttempt, tx types.Transaction, sendErr error) error {
+	var rpcErr *rpc.JsonRpcError
+	if !errors.As(sendErr, &rpcErr) {
+		return nil
+	}
+	// a duplicate fails preflight too, so the refusal only counts when the signature is unknown
+	status, err := c.c.GetSignatureStatusWithConfig(ctx, att.Signature, client.GetSignatureStatusesConfig{SearchTransactionHistory: true})
+	if err != nil || status != nil {
+		return nil
+	}
+	if txErr, logs, ok := preflightFailure(sendErr); ok {
+		return c.transactionError(ctx, "", tx, txErr, logs)
+	}
+	return rpcError(sendErr)
+}
+
+// landedFailure decodes the error of a landed transaction, with its logs when the node serves them
+func (c *Client) landedFailure(ctx context.Context, signature string, tx types.Transaction, txErr any) *TransactionError {
+	var logs []string
+	landed, err := c.c.GetTransactionWithConfig(ctx, signature, client.GetTransactionConfig{Commitment: rpc.CommitmentConfirmed})
+	if err == nil && landed != nil && landed.Meta != nil {
+		logs = landed.Meta.LogMessages
+	}
+	return c.transactionError(ctx, signature, tx, txErr, logs)
+}
+
+// saveSubmission records progress on a best-effort basis: a stale record only makes a retry
+// re-check an attempt, never sign a new one
+func (c *Client) saveSubmission(ctx context.Context, sub *models.Submission) {
+	_ = c.submissions.SaveSubmission(ctx, *sub)
+}
+
+// keyLock serializes the deliveries of an idempotency key; it is dropped once no delivery holds
+// or waits for it
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func (c *Client) lockKey(key string) func() {
+	c.keyLocksMu.Lock()
+	l := c.keyLocks[key]
+	if l == nil {
+		l = &keyLock{}
+		c.keyLocks[key] = l
+	}
+	l.refs++
+	c.keyLocksMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		c.keyLocksMu.Lock()
+		defer c.keyLocksMu.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(c.keyLocks, key)
+		}
+	}
+}
+
+func newAttempt(tx types.Transaction, lastValidBlockHeight uint64) (models.SubmissionAttempt, error) {
+	raw, err := tx.Serialize()
+	if err != nil {
+		return models.SubmissionAttempt{}, err
+	}
+	return models.SubmissionAttempt{
+		Signature:            base58.Encode(tx.Signatures[0]),
+		Transaction:          base64.StdEncoding.EncodeToString(raw),
+		Blockhash:            tx.Message.RecentBlockHash,
+		LastValidBlockHeight: lastValidBlockHeight,
+	}, nil
+}
+
+func attemptTransaction(att *models.SubmissionAttempt) (types.Transaction, error) {
+	raw, err := base64.StdEncoding.DecodeString(att.Transaction)
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("invalid recorded transaction: %w", err)
+	}
+	return types.TransactionDeserialize(raw)
+}
+
+// liveAttempt returns the last attempt when it may still land
+func liveAttempt(sub *models.Submission) *models.SubmissionAttempt {
+	if len(sub.Attempts) == 0 {
+		return nil
+	}
+	att := &sub.Attempts[len(sub.Attempts)-1]
+	if att.Expired || att.Rejected != "" {
+		return nil
+	}
+	return att
+}
+
+func expiredAttempts(sub *models.Submission) int {
+	n := 0
+	for _, att := range sub.Attempts {
+		if att.Expired {
+			n++
+		}
+	}
+	return n
+}
+
+func landedError(sub *models.Submission) error {
+	if sub.Err == "" {
+		return nil
+	}
+	if sub.Error != nil {
+		return &TransactionError{Signature: sub.Signature, TransactionError: *sub.Error}
+	}
+	return fmt.Errorf("%w: %s: %s", ErrTransactionFailed, sub.Signature, sub.Err)
+}
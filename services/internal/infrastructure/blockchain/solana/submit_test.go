@@ -0,0 +1,134 @@
+package sdk_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/solanatest"
+)
+
+func newResendCluster(t *testing.T, interval time.Duration, maxRebuilds int) (*sdk.Client, *solanatest.Server) {
+	t.Helper()
+	srv := solanatest.NewServer()
+	t.Cleanup(srv.Close)
+	c, err := sdk.NewClient(srv.URL, sdk.WithConfirmationPolling(time.Millisecond, 5*time.Millisecond), sdk.WithResend(interval, maxRebuilds))
+	if err != nil {
+		t.Fatalf("new client failed: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c, srv
+}
+
+func TestSubmission_RebroadcastsDroppedTransaction(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newResendCluster(t, 2*time.Millisecond, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	from := c.CreateAccount()
+	to := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	srv.DropTransactions(3)
+	sig, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, from), ToPublicKey: to.PublicKey, Lamports: 1_000_000})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	assertBalance(t, ctx, c, to.PublicKey, 1_000_000)
+
+	sub, err := c.GetSubmission(ctx, models.GetSubmissionRequest{Key: sig})
+	if err != nil {
+		t.Fatalf("get submission failed: %v", err)
+	}
+	if sub.Status != models.SubmissionLanded || len(sub.Attempts) != 1 {
+		t.Fatalf("unexpected submission: status %s with %d attempts", sub.Status, len(sub.Attempts))
+	}
+	if got := sub.Attempts[0].Broadcasts; got < 4 {
+		t.Fatalf("expected the dropped transaction to be rebroadcast, got %d broadcasts", got)
+	}
+}
+
+func TestSubmission_RebuildsOnlyAfterExpiry(t *testing.T) {
+	t.Parallel()
+
+	// without rebroadcasts the dropped first version can only expire
+	c, srv := newResendCluster(t, time.Hour, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	from := c.CreateAccount()
+	to := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	req := models.TransferSOLRequest{
+		SendOptions: models.SendOptions{Commitment: models.CommitmentConfirmed, IdempotencyKey: "payout-1"},
+		From:        mustSigner(t, from),
+		ToPublicKey: to.PublicKey,
+		Lamports:    1_000_000,
+	}
+	srv.DropTransactions(1)
+	sig, err := c.TransferSOL(ctx, req)
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	assertBalance(t, ctx, c, to.PublicKey, 1_000_000)
+
+	sub, err := c.GetSubmission(ctx, models.GetSubmissionRequest{Key: "payout-1"})
+	if err != nil {
+		t.Fatalf("get submission failed: %v", err)
+	}
+	if len(sub.Attempts) != 2 || !sub.Attempts[0].Expired || sub.Attempts[1].Signature != sig {
+		t.Fatalf("expected an expired attempt followed by the landed one, got %+v", sub.Attempts)
+	}
+	if sub.Attempts[0].Blockhash == sub.Attempts[1].Blockhash {
+		t.Fatal("expected the rebuilt attempt to use a fresh blockhash")
+	}
+
+	// retrying the request returns the landed transfer instead of paying again
+	again, err := c.TransferSOL(ctx, req)
+	if err != nil {
+		t.Fatalf("retried transfer failed: %v", err)
+	}
+	if again != sig {
+		t.Fatalf("retry returned %s, want %s", again, sig)
+	}
+	assertBalance(t, ctx, c, to.PublicKey, 1_000_000)
+}
+
+func TestSubmission_LostResponseDoesNotResign(t *testing.T) {
+	t.Parallel()
+
+	c, srv := newResendCluster(t, 2*time.Millisecond, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	from := c.CreateAccount()
+	to := c.CreateAccount()
+	if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: from.PublicKey, Lamports: 100_000_000}); err != nil {
+		t.Fatalf("airdrop failed: %v", err)
+	}
+
+	// the transfer lands but the caller only sees a gateway timeout
+	srv.LoseResponses(1)
+	sig, err := c.TransferSOL(ctx, models.TransferSOLRequest{SendOptions: confirmed, From: mustSigner(t, from), ToPublicKey: to.PublicKey, Lamports: 1_000_000})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	assertBalance(t, ctx, c, to.PublicKey, 1_000_000)
+
+	sub, err := c.GetSubmission(ctx, models.GetSubmissionRequest{Key: sig})
+	if err != nil {
+		t.Fatalf("get submission failed: %v", err)
+	}
+	if len(sub.Attempts) != 1 || sub.Status != models.SubmissionLanded {
+		t.Fatalf("unexpected submission: status %s with %d attempts", sub.Status, len(sub.Attempts))
+	}
+}
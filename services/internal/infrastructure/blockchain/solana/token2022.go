@@ -0,0 +1,249 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/client"
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/mr-tron/base58"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// Token-2022 accounts keep the base layout padded to the token account size,
+// followed by an account type byte and type-length-value extensions
+const (
+	mintSize          = 82
+	tokenAccountSize  = 165
+	accountTypeOffset = tokenAccountSize
+	extensionsOffset  = accountTypeOffset + 1
+	tlvHeaderSize     = 4
+
+	extensionTransferFeeConfig = 1
+	extensionTransferFeeAmount = 2
+
+	transferFeeConfigSize = 108
+
+	accountTypeMint = 1
+
+	tokenAccountStateUninitialized = 0
+	tokenAccountStateFrozen        = 2
+)
+
+func isTokenProgram(program common.PublicKey) bool {
+	return program == common.TokenProgramID || program == common.Token2022ProgramID
+}
+
+// tokenProgramFromString parses an optional token program address; empty selects Token
+func tokenProgramFromString(program string) (common.PublicKey, error) {
+	if program == "" {
+		return common.TokenProgramID, nil
+	}
+	p, err := publicKey(program)
+	if err != nil {
+		return common.PublicKey{}, err
+	}
+	if !isTokenProgram(p) {
+		return common.PublicKey{}, fmt.Errorf("%s is not a token program", program)
+	}
+	return p, nil
+}
+
+// tokenAccountInfo fetches an account owned by one of the token programs
+func (c *Client) tokenAccountInfo(ctx context.Context, address string) (client.AccountInfo, error) {
+	if _, err := publicKey(address); err != nil {
+		return client.AccountInfo{}, err
+	}
+	acc, err := c.c.GetAccountInfo(ctx, address)
+	if err != nil {
+		return client.AccountInfo{}, rpcError(err)
+	}
+	if len(acc.Data) == 0 {
+		return client.AccountInfo{}, accountError(address, ErrAccountNotFound)
+	}
+	if !isTokenProgram(acc.Owner) {
+		return client.AccountInfo{}, accountError(address, fmt.Errorf("%w: not owned by a token program", ErrInvalidAccountData))
+	}
+	return acc, nil
+}
+
+// tokenProgramOf returns the token program that owns mint
+func (c *Client) tokenProgramOf(ctx context.Context, mint string) (common.PublicKey, error) {
+	acc, err := c.tokenAccountInfo(ctx, mint)
+	if err != nil {
+		return common.PublicKey{}, err
+	}
+	return acc.Owner, nil
+}
+
+func deriveATA(owner, mint, tokenProgram common.PublicKey) (common.PublicKey, error) {
+	seeds := [][]byte{
+		owner.Bytes(),
+		tokenProgram.Bytes(),
+		mint.Bytes(),
+	}
+	pda, _, err := common.FindProgramAddress(seeds, common.SPLAssociatedTokenAccountProgramID)
+	if err != nil {
+		return common.PublicKey{}, err
+	}
+	return pda, nil
+}
+
+// findExtension returns the value of a Token-2022 extension, or nil when absent
+func findExtension(data []byte, extType uint16) []byte {
+	if len(data) <= extensionsOffset {
+		return nil
+	}
+	for off := extensionsOffset; off+tlvHeaderSize <= len(data); {
+		t := binary.LittleEndian.Uint16(data[off:])
+		l := int(binary.LittleEndian.Uint16(data[off+2:]))
+		if t == 0 {
+			return nil
+		}
+		off += tlvHeaderSize
+		if off+l > len(data) {
+			return nil
+		}
+		if t == extType {
+			return data[off : off+l]
+		}
+		off += l
+	}
+	return nil
+}
+
+// optionalKey decodes a COption<Pubkey> as an address, empty when None
+func optionalKey(b []byte) string {
+	if binary.LittleEndian.Uint32(b[:4]) == 0 {
+		return ""
+	}
+	return base58.Encode(b[4:36])
+}
+
+// nonZeroKey decodes an OptionalNonZeroPubkey, empty when all zero
+func nonZeroKey(b []byte) string {
+	for _, v := range b[:32] {
+		if v != 0 {
+			return base58.Encode(b[:32])
+		}
+	}
+	return ""
+}
+
+func parseTransferFee(b []byte) models.TransferFee {
+	return models.TransferFee{
+		Epoch:       binary.LittleEndian.Uint64(b[0:8]),
+		MaximumFee:  binary.LittleEndian.Uint64(b[8:16]),
+		BasisPoints: binary.LittleEndian.Uint16(b[16:18]),
+	}
+}
+
+// parseMint decodes the base mint layout: mint authority COption, supply u64, decimals u8,
+// is_initialized bool, freeze authority COption. A Token-2022 mint with extensions is padded to
+// the token account size and typed as a mint, which tells it apart from a token account.
+func parseMint(address string, program common.PublicKey, data []byte) (*models.MintInfo, error) {
+	isMint := len(data) == mintSize ||
+		(program == common.Token2022ProgramID && len(data) > tokenAccountSize && data[accountTypeOffset] == accountTypeMint)
+	if !isMint {
+		return nil, accountError(address, fmt.Errorf("%w: not a mint", ErrInvalidAccountData))
+	}
+	if data[45] != 1 {
+		return nil, accountError(address, fmt.Errorf("%w: mint is not initialized", ErrInvalidAccountData))
+	}
+	info := &models.MintInfo{
+		Address:         address,
+		TokenProgram:    program.ToBase58(),
+		MintAuthority:   optionalKey(data[0:36]),
+		Supply:          binary.LittleEndian.Uint64(data[36:44]),
+		Decimals:        data[44],
+		FreezeAuthority: optionalKey(data[46:82]),
+	}
+	if ext := findExtension(data, extensionTransferFeeConfig); len(ext) >= transferFeeConfigSize {
+		info.TransferFee = &models.TransferFeeConfig{
+			ConfigAuthority:           nonZeroKey(ext[0:32]),
+			WithdrawWithheldAuthority: nonZeroKey(ext[32:64]),
+			WithheldAmount:            binary.LittleEndian.Uint64(ext[64:72]),
+			Older:                     parseTransferFee(ext[72:90]),
+			Newer:                     parseTransferFee(ext[90:108]),
+		}
+	}
+	return info, nil
+}
+
+// parseTokenAccount decodes the base token account layout: mint, owner, amount u64,
+// delegate COption, state u8, is_native COption<u64>, delegated amount u64, close authority COption
+func parseTokenAccount(address string, program common.PublicKey, data []byte) (*models.TokenAccount, error) {
+	if len(data) < tokenAccountSize {
+		return nil, accountError(address, fmt.Errorf("%w: not a token account", ErrInvalidAccountData))
+	}
+	if data[108] == tokenAccountStateUninitialized {
+		return nil, accountError(address, fmt.Errorf("%w: token account is not initialized", ErrInvalidAccountData))
+	}
+	ta := &models.TokenAccount{
+		Mint:            base58.Encode(data[0:32]),
+		Owner:           base58.Encode(data[32:64]),
+		Amount:          binary.LittleEndian.Uint64(data[64:72]),
+		Delegate:        optionalKey(data[72:108]),
+		Frozen:          data[108] == tokenAccountStateFrozen,
+		DelegatedAmount: binary.LittleEndian.Uint64(data[121:129]),
+		CloseAuthority:  optionalKey(data[129:165]),
+		TokenProgram:    program.ToBase58(),
+	}
+	if ext := findExtension(data, extensionTransferFeeAmount); len(ext) >= 8 {
+		ta.WithheldAmount = binary.LittleEndian.Uint64(ext)
+	}
+	return ta, nil
+}
+
+// GetMintInfo returns the parsed mint, including Token-2022 transfer fee configuration
+func (c *Client) GetMintInfo(ctx context.Context, req models.GetMintInfoRequest) (*models.MintInfo, error) {
+	acc, err := c.tokenAccountInfo(ctx, req.Mint)
+	if err != nil {
+		return nil, err
+	}
+	return parseMint(req.Mint, acc.Owner, acc.Data)
+}
+
+// GetTransferFee quotes the transfer fee of a mint for the current epoch
+func (c *Client) GetTransferFee(ctx context.Context, req models.GetTransferFeeRequest) (*models.TransferFeeQuote, error) {
+	mint, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: req.Mint})
+	if err != nil {
+		return nil, err
+	}
+	epoch, err := c.c.GetEpochInfo(ctx)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	quote := &models.TransferFeeQuote{Amount: req.Amount, Epoch: epoch.Epoch}
+	if mint.TransferFee != nil {
+		quote.Fee = mint.TransferFee.Fee(epoch.Epoch, req.Amount)
+	}
+	quote.NetAmount = req.Amount - quote.Fee
+	return quote, nil
+}
+
+// epochAt estimates the epoch containing slot, assuming a fixed epoch length
+func epochAt(info client.GetEpochInfo, slot uint64) uint64 {
+	start := info.AbsoluteSlot - info.SlotIndex
+	if slot >= start || info.SlotsInEpoch == 0 {
+		return info.Epoch
+	}
+	back := (start - slot + info.SlotsInEpoch - 1) / info.SlotsInEpoch
+	if back > info.Epoch {
+		return 0
+	}
+	return info.Epoch - back
+}
+
+// appendPubkeyOption encodes an instruction Option<Pubkey>: a tag byte, then the key when set
+func appendPubkeyOption(data []byte, key string) ([]byte, error) {
+	if key == "" {
+		return append(data, 0), nil
+	}
+	pub, err := publicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(data, 1), pub.Bytes()...), nil
+}
@@ -0,0 +1,130 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blocto/solana-go-sdk/common"
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+func TestToken2022_TransferFee(t *testing.T) {
+	t.Parallel()
+
+	c := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	token2022 := common.Token2022ProgramID.ToBase58()
+	payer := c.CreateAccount()
+	owner1 := c.CreateAccount()
+	owner2 := c.CreateAccount()
+	for _, acc := range []string{payer.PublicKey, owner1.PublicKey} {
+		if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: acc, Lamports: 100_000_000}); err != nil {
+			t.Fatalf("airdrop failed: %v", err)
+		}
+	}
+
+	// 1% fee capped at 5_000 base units
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{
+		SendOptions:   confirmed,
+		Payer:         mustSigner(t, payer),
+		MintAuthority: payer.PublicKey,
+		Decimals:      6,
+		TokenProgram:  token2022,
+		TransferFee:   &models.TransferFeeParams{BasisPoints: 100, MaximumFee: 5_000, WithdrawWithheldAuthority: payer.PublicKey},
+	})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	info, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: mint})
+	if err != nil {
+		t.Fatalf("get mint info failed: %v", err)
+	}
+	if info.TokenProgram != token2022 || info.Decimals != 6 || info.TransferFee == nil {
+		t.Fatalf("unexpected mint info: %+v", info)
+	}
+	if info.TransferFee.Newer.BasisPoints != 100 || info.TransferFee.Newer.MaximumFee != 5_000 || info.TransferFee.WithdrawWithheldAuthority != payer.PublicKey {
+		t.Fatalf("unexpected transfer fee config: %+v", info.TransferFee)
+	}
+
+	ata1, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: owner1.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata1 failed: %v", err)
+	}
+	ata2, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, payer), Owner: owner2.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata2 failed: %v", err)
+	}
+	derived, err := c.DeriveAssociatedTokenAddress(models.DeriveATARequest{Owner: owner1.PublicKey, Mint: mint, TokenProgram: token2022})
+	if err != nil {
+		t.Fatalf("derive ata failed: %v", err)
+	}
+	if derived != ata1 {
+		t.Fatalf("derived ata mismatch: %s != %s", derived, ata1)
+	}
+	classic, _ := c.DeriveAssociatedTokenAddress(models.DeriveATARequest{Owner: owner1.PublicKey, Mint: mint})
+	if classic == ata1 {
+		t.Fatalf("Token and Token-2022 derivations must differ")
+	}
+
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, payer), Mint: mint, DestinationATA: ata1, Amount: 10_000_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	quote, err := c.GetTransferFee(ctx, models.GetTransferFeeRequest{Mint: mint, Amount: 100_000})
+	if err != nil {
+		t.Fatalf("get transfer fee failed: %v", err)
+	}
+	if quote.Fee != 1_000 || quote.NetAmount != 99_000 {
+		t.Fatalf("unexpected quote: %+v", quote)
+	}
+	capped, err := c.GetTransferFee(ctx, models.GetTransferFeeRequest{Mint: mint, Amount: 9_000_000})
+	if err != nil {
+		t.Fatalf("get transfer fee failed: %v", err)
+	}
+	if capped.Fee != 5_000 {
+		t.Fatalf("fee not capped: %+v", capped)
+	}
+
+	sig, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+		SendOptions:    confirmed,
+		Authority:      mustSigner(t, owner1),
+		SourceATA:      ata1,
+		DestinationATA: ata2,
+		Mint:           mint,
+		Amount:         100_000,
+		Decimals:       6,
+	})
+	if err != nil {
+		t.Fatalf("token transfer failed: %v", err)
+	}
+
+	ta2, err := c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: ata2})
+	if err != nil {
+		t.Fatalf("get token account failed: %v", err)
+	}
+	if ta2.Amount != 99_000 || ta2.WithheldAmount != 1_000 || ta2.TokenProgram != token2022 {
+		t.Fatalf("unexpected destination account: %+v", ta2)
+	}
+
+	transfers, err := c.GetTransactionTransfersSPL(ctx, models.GetTransactionTransfersRequest{Signature: sig})
+	if err != nil {
+		t.Fatalf("get transfers failed: %v", err)
+	}
+	if len(transfers) != 1 {
+		t.Fatalf("expected one transfer, got %d", len(transfers))
+	}
+	tr := transfers[0]
+	if tr.Mint != mint || tr.Amount.Raw != 100_000 || tr.Fee.Raw != 1_000 || tr.NetAmount.Value.String() != "0.099" || tr.Program != token2022 {
+		t.Fatalf("unexpected transfer: %+v", tr)
+	}
+
+	// a Token-2022 account with extensions is as long as a mint with extensions, but typed as an account
+	if _, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: ata2}); !errors.Is(err, sdk.ErrInvalidAccountData) {
+		t.Fatalf("expected a token account to be refused as a mint, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// SPL Token instructions that act on behalf of an authority
+const (
+	tokenInstructionRevoke         = 5
+	tokenInstructionSetAuthority   = 6
+	tokenInstructionBurn           = 8
+	tokenInstructionCloseAccount   = 9
+	tokenInstructionFreezeAccount  = 10
+	tokenInstructionThawAccount    = 11
+	tokenInstructionApproveChecked = 13
+	tokenInstructionBurnChecked    = 15
+)
+
+// Burn destroys tokens of a token account and lowers the supply of its mint
+func (c *Client) Burn(ctx context.Context, req models.BurnRequest) (string, error) {
+	op, err := c.burn(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) burn(ctx context.Context, req models.BurnRequest) (operation, error) {
+	authority, signers, err := authorityAccounts(req.Authority, req.Multisig)
+	if err != nil {
+		return operation{}, err
+	}
+	program, err := c.tokenProgramOf(ctx, req.Mint)
+	if err != nil {
+		return operation{}, err
+	}
+	account, err := publicKey(req.Account)
+	if err != nil {
+		return operation{}, err
+	}
+
+	// Burn: 8, amount u64; BurnChecked: 15, amount u64, decimals u8
+	data := []byte{tokenInstructionBurn}
+	if req.Decimals != nil {
+		data[0] = tokenInstructionBurnChecked
+	}
+	data = binary.LittleEndian.AppendUint64(data, req.Amount)
+	if req.Decimals != nil {
+		data = append(data, *req.Decimals)
+	}
+	inst := types.Instruction{
+		ProgramID: program,
+		Accounts: []types.AccountMeta{
+			{PubKey: account, IsSigner: false, IsWritable: true},
+			{PubKey: common.PublicKeyFromString(req.Mint), IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+	inst.Accounts = append(inst.Accounts, authority...)
+	return operation{opts: req.SendOptions, feePayer: req.Authority, instructions: []types.Instruction{inst}, signers: signers}, nil
+}
+
+// ApproveChecked lets a delegate spend up to an amount from a token account; the delegate then
+// signs transfers and burns as their authority
+func (c *Client) ApproveChecked(ctx context.Context, req models.ApproveRequest) (string, error) {
+	op, err := c.approveChecked(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) approveChecked(ctx context.Context, req models.ApproveRequest) (operation, error) {
+	owner, signers, err := authorityAccounts(req.Owner, req.Multisig)
+	if err != nil {
+		return operation{}, err
+	}
+	program, err := c.tokenProgramOf(ctx, req.Mint)
+	if err != nil {
+		return operation{}, err
+	}
+	account, err := publicKey(req.Account)
+	if err != nil {
+		return operation{}, err
+	}
+	delegate, err := publicKey(req.Delegate)
+	if err != nil {
+		return operation{}, err
+	}
+
+	// ApproveChecked: 13, amount u64, decimals u8
+	data := binary.LittleEndian.AppendUint64([]byte{tokenInstructionApproveChecked}, req.Amount)
+	data = append(data, req.Decimals)
+	inst := types.Instruction{
+		ProgramID: program,
+		Accounts: []types.AccountMeta{
+			{PubKey: account, IsSigner: false, IsWritable: true},
+			{PubKey: common.PublicKeyFromString(req.Mint), IsSigner: false, IsWritable: false},
+			{PubKey: delegate, IsSigner: false, IsWritable: false},
+		},
+		Data: data,
+	}
+	inst.Accounts = append(inst.Accounts, owner...)
+	return operation{opts: req.SendOptions, feePayer: req.Owner, instructions: []types.Instruction{inst}, signers: signers}, nil
+}
+
+// Revoke withdraws the allowance of a token account's delegate
+func (c *Client) Revoke(ctx context.Context, req models.RevokeRequest) (string, error) {
+	op, err := c.revoke(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) revoke(ctx context.Context, req models.RevokeRequest) (operation, error) {
+	owner, signers, err := authorityAccounts(req.Owner, req.Multisig)
+	if err != nil {
+		return operation{}, err
+	}
+	acc, err := c.tokenAccountInfo(ctx, req.Account)
+	if err != nil {
+		return operation{}, err
+	}
+	inst := types.Instruction{
+		ProgramID: acc.Owner,
+		Accounts: []types.AccountMeta{
+			{PubKey: common.PublicKeyFromString(req.Account), IsSigner: false, IsWritable: true},
+		},
+		Data: []byte{tokenInstructionRevoke},
+	}
+	inst.Accounts = append(inst.Accounts, owner...)
+	return operation{opts: req.SendOptions, feePayer: req.Owner, instructions: []types.Instruction{inst}, signers: signers}, nil
+}
+
+// FreezeAccount stops a token account from sending or receiving tokens
+func (c *Client) FreezeAccount(ctx context.Context, req models.FreezeAccountRequest) (string, error) {
+	op, err := c.setFrozen(ctx, req.SendOptions, req.FreezeAuthority, req.Multisig, req.Account, req.Mint, tokenInstructionFreezeAccount)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+// ThawAccount lifts the freeze of a token account
+func (c *Client) ThawAccount(ctx context.Context, req models.ThawAccountRequest) (string, error) {
+	op, err := c.setFrozen(ctx, req.SendOptions, req.FreezeAuthority, req.Multisig, req.Account, req.Mint, tokenInstructionThawAccount)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+// setFrozen builds FreezeAccount or ThawAccount, which share their accounts and carry no data
+func (c *Client) setFrozen(ctx context.Context, opts models.SendOptions, freezeAuthority models.Signer, multisig *models.MultisigAuthority, account, mint string, instruction byte) (operation, error) {
+	authority, signers, err := authorityAccounts(freezeAuthority, multisig)
+	if err != nil {
+		return operation{}, err
+	}
+	info, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: mint})
+	if err != nil {
+		return operation{}, err
+	}
+	if info.FreezeAuthority == "" {
+		return operation{}, accountError(mint, fmt.Errorf("%w: mint has no freeze authority", ErrInvalidAccountData))
+	}
+	target, err := publicKey(account)
+	if err != nil {
+		return operation{}, err
+	}
+	inst := types.Instruction{
+		ProgramID: common.PublicKeyFromString(info.TokenProgram),
+		Accounts: []types.AccountMeta{
+			{PubKey: target, IsSigner: false, IsWritable: true},
+			{PubKey: common.PublicKeyFromString(mint), IsSigner: false, IsWritable: false},
+		},
+		Data: []byte{instruction},
+	}
+	inst.Accounts = append(inst.Accounts, authority...)
+	return operation{opts: opts, feePayer: freezeAuthority, instructions: []types.Instruction{inst}, signers: signers}, nil
+}
+
+// CloseAccount closes an empty token account and sends its rent to the destination
+func (c *Client) CloseAccount(ctx context.Context, req models.CloseAccountRequest) (string, error) {
+	op, err := c.closeAccount(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) closeAccount(ctx context.Context, req models.CloseAccountRequest) (operation, error) {
+	authority, signers, err := authorityAccounts(req.Authority, req.Multisig)
+	if err != nil {
+		return operation{}, err
+	}
+	acc, err := c.tokenAccountInfo(ctx, req.Account)
+	if err != nil {
+		return operation{}, err
+	}
+	destination := common.PublicKeyFromString(req.Authority.PublicKey())
+	if req.Destination != "" {
+		if destination, err = publicKey(req.Destination); err != nil {
+			return operation{}, err
+		}
+	}
+	inst := types.Instruction{
+		ProgramID: acc.Owner,
+		Accounts: []types.AccountMeta{
+			{PubKey: common.PublicKeyFromString(req.Account), IsSigner: false, IsWritable: true},
+			{PubKey: destination, IsSigner: false, IsWritable: true},
+		},
+		Data: []byte{tokenInstructionCloseAccount},
+	}
+	inst.Accounts = append(inst.Accounts, authority...)
+	return operation{opts: req.SendOptions, feePayer: req.Authority, instructions: []types.Instruction{inst}, signers: signers}, nil
+}
+
+// SetAuthority hands an authority of a mint or token account to a new key, or removes it
+func (c *Client) SetAuthority(ctx context.Context, req models.SetAuthorityRequest) (string, error) {
+	op, err := c.setAuthority(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return c.run(ctx, op)
+}
+
+func (c *Client) setAuthority(ctx context.Context, req models.SetAuthorityRequest) (operation, error) {
+	current, signers, err := authorityAccounts(req.CurrentAuthority, req.Multisig)
+	if err != nil {
+		return operation{}, err
+	}
+	if req.AuthorityType == models.AuthorityAccountOwner && req.NewAuthority == "" {
+		return operation{}, fmt.Errorf("the owner of a token account cannot be removed")
+	}
+	acc, err := c.tokenAccountInfo(ctx, req.Account)
+	if err != nil {
+		return operation{}, err
+	}
+
+	// SetAuthority: 6, authority type u8, new authority option u8 (0 = none) + pubkey
+	data := []byte{tokenInstructionSetAuthority, byte(req.AuthorityType)}
+	if data, err = appendPubkeyOption(data, req.NewAuthority); err != nil {
+		return operation{}, err
+	}
+	inst := types.Instruction{
+		ProgramID: acc.Owner,
+		Accounts: []types.AccountMeta{
+			{PubKey: common.PublicKeyFromString(req.Account), IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+	inst.Accounts = append(inst.Accounts, current...)
+	return operation{opts: req.SendOptions, feePayer: req.CurrentAuthority, instructions: []types.Instruction{inst}, signers: signers}, nil
+}
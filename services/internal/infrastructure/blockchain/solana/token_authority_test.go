@@ -0,0 +1,212 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana"
+	"github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+func TestTokenAuthority_BurnFreezeCloseAndSetAuthority(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	issuer := c.CreateAccount()
+	compliance := c.CreateAccount()
+	holder := c.CreateAccount()
+	for _, acc := range []string{issuer.PublicKey, compliance.PublicKey, holder.PublicKey} {
+		if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: acc, Lamports: 100_000_000}); err != nil {
+			t.Fatalf("airdrop failed: %v", err)
+		}
+	}
+
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{
+		SendOptions: confirmed, Payer: mustSigner(t, issuer), MintAuthority: issuer.PublicKey, FreezeAuthority: compliance.PublicKey, Decimals: 2,
+	})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	info, err := c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: mint})
+	if err != nil || info.FreezeAuthority != compliance.PublicKey {
+		t.Fatalf("unexpected mint %+v: %v", info, err)
+	}
+	ata, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, issuer), Owner: holder.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, issuer), Mint: mint, DestinationATA: ata, Amount: 1_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	// a frozen account can neither burn nor be closed until it is thawed
+	if _, err := c.FreezeAccount(ctx, models.FreezeAccountRequest{SendOptions: confirmed, FreezeAuthority: mustSigner(t, compliance), Account: ata, Mint: mint}); err != nil {
+		t.Fatalf("freeze failed: %v", err)
+	}
+	var txErr *sdk.TransactionError
+	_, err = c.Burn(ctx, models.BurnRequest{SendOptions: confirmed, Authority: mustSigner(t, holder), Account: ata, Mint: mint, Amount: 100})
+	if !errors.As(err, &txErr) || txErr.Name != "AccountFrozen" {
+		t.Fatalf("expected burning from a frozen account to fail, got %v", err)
+	}
+	if _, err := c.ThawAccount(ctx, models.ThawAccountRequest{SendOptions: confirmed, FreezeAuthority: mustSigner(t, compliance), Account: ata, Mint: mint}); err != nil {
+		t.Fatalf("thaw failed: %v", err)
+	}
+
+	wrong := uint8(6)
+	_, err = c.Burn(ctx, models.BurnRequest{SendOptions: confirmed, Authority: mustSigner(t, holder), Account: ata, Mint: mint, Amount: 100, Decimals: &wrong})
+	if !errors.As(err, &txErr) || txErr.Name != "MintDecimalsMismatch" {
+		t.Fatalf("expected a decimals mismatch, got %v", err)
+	}
+	decimals := uint8(2)
+	if _, err := c.Burn(ctx, models.BurnRequest{SendOptions: confirmed, Authority: mustSigner(t, holder), Account: ata, Mint: mint, Amount: 400, Decimals: &decimals}); err != nil {
+		t.Fatalf("burn checked failed: %v", err)
+	}
+	if _, err := c.Burn(ctx, models.BurnRequest{SendOptions: confirmed, Authority: mustSigner(t, holder), Account: ata, Mint: mint, Amount: 500}); err != nil {
+		t.Fatalf("burn failed: %v", err)
+	}
+	if info, err = c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: mint}); err != nil || info.Supply != 100 {
+		t.Fatalf("expected a supply of 100, got %+v: %v", info, err)
+	}
+
+	_, err = c.CloseAccount(ctx, models.CloseAccountRequest{SendOptions: confirmed, Authority: mustSigner(t, holder), Account: ata})
+	if !errors.As(err, &txErr) || txErr.Name != "NonNativeHasBalance" {
+		t.Fatalf("expected closing a funded account to fail, got %v", err)
+	}
+	if _, err := c.Burn(ctx, models.BurnRequest{SendOptions: confirmed, Authority: mustSigner(t, holder), Account: ata, Mint: mint, Amount: 100}); err != nil {
+		t.Fatalf("burn failed: %v", err)
+	}
+
+	// the issuer reclaims the rent it paid for the holder's account
+	if _, err := c.SetAuthority(ctx, models.SetAuthorityRequest{
+		SendOptions: confirmed, CurrentAuthority: mustSigner(t, holder), Account: ata, AuthorityType: models.AuthorityCloseAccount, NewAuthority: issuer.PublicKey,
+	}); err != nil {
+		t.Fatalf("set close authority failed: %v", err)
+	}
+	before, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: issuer.PublicKey})
+	if err != nil {
+		t.Fatalf("get balance failed: %v", err)
+	}
+	if _, err := c.CloseAccount(ctx, models.CloseAccountRequest{SendOptions: confirmed, Authority: mustSigner(t, issuer), Account: ata}); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	after, err := c.GetBalance(ctx, models.BalanceRequest{PublicKey: issuer.PublicKey})
+	if err != nil {
+		t.Fatalf("get balance failed: %v", err)
+	}
+	if after <= before {
+		t.Fatalf("expected the rent to return to the issuer: %d -> %d", before, after)
+	}
+	if _, err := c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: ata}); !errors.Is(err, sdk.ErrAccountNotFound) {
+		t.Fatalf("expected the account to be gone, got %v", err)
+	}
+
+	// handing the mint authority over, then removing the freeze authority for good
+	if _, err := c.SetAuthority(ctx, models.SetAuthorityRequest{
+		SendOptions: confirmed, CurrentAuthority: mustSigner(t, issuer), Account: mint, AuthorityType: models.AuthorityMintTokens, NewAuthority: compliance.PublicKey,
+	}); err != nil {
+		t.Fatalf("set mint authority failed: %v", err)
+	}
+	if _, err := c.SetAuthority(ctx, models.SetAuthorityRequest{
+		SendOptions: confirmed, CurrentAuthority: mustSigner(t, compliance), Account: mint, AuthorityType: models.AuthorityFreezeAccount,
+	}); err != nil {
+		t.Fatalf("remove freeze authority failed: %v", err)
+	}
+	if info, err = c.GetMintInfo(ctx, models.GetMintInfoRequest{Mint: mint}); err != nil || info.MintAuthority != compliance.PublicKey || info.FreezeAuthority != "" {
+		t.Fatalf("unexpected authorities %+v: %v", info, err)
+	}
+	_, err = c.SetAuthority(ctx, models.SetAuthorityRequest{
+		SendOptions: confirmed, CurrentAuthority: mustSigner(t, compliance), Account: mint, AuthorityType: models.AuthorityAccountOwner, NewAuthority: issuer.PublicKey,
+	})
+	if !errors.As(err, &txErr) || txErr.Name != "AuthorityTypeNotSupported" {
+		t.Fatalf("expected a mint to refuse an owner change, got %v", err)
+	}
+	if _, err := c.FreezeAccount(ctx, models.FreezeAccountRequest{SendOptions: confirmed, FreezeAuthority: mustSigner(t, compliance), Account: ata, Mint: mint}); err == nil {
+		t.Fatal("expected freezing without a freeze authority to fail")
+	}
+}
+
+func TestTokenAuthority_DelegatedRentAllowance(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCluster(t)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	platform := c.CreateAccount()
+	tenant := c.CreateAccount()
+	landlord := c.CreateAccount()
+	for _, acc := range []string{platform.PublicKey, tenant.PublicKey} {
+		if _, err := c.RequestAirdrop(ctx, models.AirdropRequest{SendOptions: confirmed, PublicKey: acc, Lamports: 100_000_000}); err != nil {
+			t.Fatalf("airdrop failed: %v", err)
+		}
+	}
+	mint, _, err := c.CreateMint(ctx, models.CreateMintRequest{SendOptions: confirmed, Payer: mustSigner(t, platform), MintAuthority: platform.PublicKey, Decimals: 2})
+	if err != nil {
+		t.Fatalf("create mint failed: %v", err)
+	}
+	tenantATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, platform), Owner: tenant.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	landlordATA, _, err := c.CreateAssociatedTokenAccountIfNotExists(ctx, models.CreateATARequest{SendOptions: confirmed, Payer: mustSigner(t, platform), Owner: landlord.PublicKey, Mint: mint})
+	if err != nil {
+		t.Fatalf("create ata failed: %v", err)
+	}
+	if _, err := c.MintTo(ctx, models.MintToRequest{SendOptions: confirmed, MintAuthority: mustSigner(t, platform), Mint: mint, DestinationATA: tenantATA, Amount: 10_000}); err != nil {
+		t.Fatalf("mint to failed: %v", err)
+	}
+
+	// the tenant allows three months of rent
+	if _, err := c.ApproveChecked(ctx, models.ApproveRequest{
+		SendOptions: confirmed, Owner: mustSigner(t, tenant), Account: tenantATA, Mint: mint, Delegate: platform.PublicKey, Amount: 3_000, Decimals: 2,
+	}); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+	pull := func(amount uint64) error {
+		_, err := c.TransferTokenChecked(ctx, models.TransferTokenCheckedRequest{
+			SendOptions: confirmed, Authority: mustSigner(t, platform), SourceATA: tenantATA, DestinationATA: landlordATA, Mint: mint, Amount: amount, Decimals: 2,
+		})
+		return err
+	}
+	if err := pull(1_000); err != nil {
+		t.Fatalf("delegate transfer failed: %v", err)
+	}
+	ta, err := c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: tenantATA})
+	if err != nil {
+		t.Fatalf("get token account failed: %v", err)
+	}
+	if ta.Owner != tenant.PublicKey || ta.Amount != 9_000 || ta.Delegate != platform.PublicKey || ta.DelegatedAmount != 2_000 || ta.Frozen {
+		t.Fatalf("unexpected allowance: %+v", ta)
+	}
+
+	var txErr *sdk.TransactionError
+	if err := pull(2_500); !errors.As(err, &txErr) || txErr.Name != "InsufficientFunds" {
+		t.Fatalf("expected pulling past the allowance to fail, got %v", err)
+	}
+	if err := pull(2_000); err != nil {
+		t.Fatalf("delegate transfer failed: %v", err)
+	}
+	if ta, err = c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: tenantATA}); err != nil || ta.Delegate != "" || ta.DelegatedAmount != 0 {
+		t.Fatalf("expected a spent allowance to clear the delegate, got %+v: %v", ta, err)
+	}
+
+	if _, err := c.ApproveChecked(ctx, models.ApproveRequest{
+		SendOptions: confirmed, Owner: mustSigner(t, tenant), Account: tenantATA, Mint: mint, Delegate: platform.PublicKey, Amount: 1_000, Decimals: 2,
+	}); err != nil {
+		t.Fatalf("approve failed: %v", err)
+	}
+	if _, err := c.Revoke(ctx, models.RevokeRequest{SendOptions: confirmed, Owner: mustSigner(t, tenant), Account: tenantATA}); err != nil {
+		t.Fatalf("revoke failed: %v", err)
+	}
+	if err := pull(100); !errors.As(err, &txErr) || txErr.Name != "OwnerMismatch" {
+		t.Fatalf("expected a revoked delegate to be refused, got %v", err)
+	}
+	if ta, err = c.GetTokenAccount(ctx, models.GetTokenAccountRequest{ATA: landlordATA}); err != nil || ta.Amount != 3_000 {
+		t.Fatalf("unexpected landlord account %+v: %v", ta, err)
+	}
+}
@@ -0,0 +1,175 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// maxTransactionSize is the largest serialized transaction, signatures included, a node accepts
+const maxTransactionSize = 1232
+
+func signerPublicKey(s models.Signer) (common.PublicKey, error) {
+	if s == nil {
+		return common.PublicKey{}, fmt.Errorf("missing signer")
+	}
+	return publicKey(s.PublicKey())
+}
+
+// operation is a write request compiled to instructions, before a blockhash is attached
+type operation struct {
+	opts         models.SendOptions
+	feePayer     models.Signer
+	instructions []types.Instruction
+	// signers are the request's signers other than the fee payer
+	signers []models.Signer
+	// generated are keypairs created for the operation, such as a new mint account;
+	// builders sign with them since nobody else holds them
+	generated []models.Signer
+}
+
+// newUnsignedTransaction compiles instructions into a message paid by feePayer with zeroed signatures;
+// with lookup tables the message is v0 and loads the accounts it finds in them
+func newUnsignedTransaction(feePayer common.PublicKey, recentBlockhash string, instructions []types.Instruction, tables ...types.AddressLookupTableAccount) types.Transaction {
+	message := types.NewMessage(types.NewMessageParam{
+		FeePayer:                   feePayer,
+		RecentBlockhash:            recentBlockhash,
+		Instructions:               instructions,
+		AddressLookupTableAccounts: tables,
+	})
+	tx := types.Transaction{
+		Message:    message,
+		Signatures: make([]types.Signature, message.Header.NumRequireSignatures),
+	}
+	for i := range tx.Signatures {
+		tx.Signatures[i] = make([]byte, 64)
+	}
+	return tx
+}
+
+// signWith adds a signature from every signer, skipping repeated public keys.
+// Signers implementing models.ContextSigner are bounded by ctx.
+func signWith(ctx context.Context, tx *types.Transaction, signers ...models.Signer) error {
+	data, err := tx.Message.Serialize()
+	if err != nil {
+		return err
+	}
+	signed := map[string]bool{}
+	for _, s := range signers {
+		if s == nil {
+			return fmt.Errorf("missing signer")
+		}
+		if signed[s.PublicKey()] {
+			continue
+		}
+		sig, err := sign(ctx, s, data)
+		if err != nil {
+			return fmt.Errorf("sign with %s: %w", s.PublicKey(), err)
+		}
+		// AddSignature verifies the signature and places it in the signer's slot
+		if err := tx.AddSignature(sig); err != nil {
+			return fmt.Errorf("sign with %s: %w", s.PublicKey(), err)
+		}
+		signed[s.PublicKey()] = true
+	}
+	return nil
+}
+
+func sign(ctx context.Context, s models.Signer, message []byte) ([]byte, error) {
+	if cs, ok := s.(models.ContextSigner); ok {
+		return cs.SignContext(ctx, message)
+	}
+	return s.Sign(message)
+}
+
+// prepare applies the compute budget and attaches the latest blockhash (or the durable nonce of opts)
+// to instructions, compiling a v0 message when opts names lookup tables. It returns the unsigned transaction and the last valid block height of its blockhash.
+func (c *Client) prepare(ctx context.Context, opts models.SendOptions, feePayer common.PublicKey, instructions []types.Instruction) (types.Transaction, uint64, error) {
+	instructions, err := c.applyComputeBudget(ctx, opts, feePayer, instructions)
+	if err != nil {
+		return types.Transaction{}, 0, err
+	}
+
+	// a durable transaction never expires by block height, so expiry is not tracked
+	var blockhash string
+	var lastValidBlockHeight uint64
+	if opts.Nonce != nil {
+		blockhash, instructions, err = c.useNonce(ctx, opts.Nonce, instructions)
+		if err != nil {
+			return types.Transaction{}, 0, err
+		}
+	} else {
+		recent, err := c.c.GetLatestBlockhash(ctx)
+		if err != nil {
+			return types.Transaction{}, 0, rpcError(err)
+		}
+		blockhash, lastValidBlockHeight = recent.Blockhash, recent.LatestValidBlockHeight
+	}
+	tables, err := c.lookupTableAccounts(ctx, opts.LookupTables)
+	if err != nil {
+		return types.Transaction{}, 0, err
+	}
+	tx := newUnsignedTransaction(feePayer, blockhash, instructions, tables...)
+	raw, err := tx.Serialize()
+	if err != nil {
+		return types.Transaction{}, 0, err
+	}
+	if len(raw) > maxTransactionSize {
+		return types.Transaction{}, 0, fmt.Errorf("%w: %d bytes, at most %d fit", ErrTransactionTooLarge, len(raw), maxTransactionSize)
+	}
+	return tx, lastValidBlockHeight, nil
+}
+
+// sendInstructions prepares instructions, signs them with the fee payer, the nonce authority
+// and every other signer and submits them, re-signing with a fresh blockhash if they expire
+func (c *Client) sendInstructions(ctx context.Context, opts models.SendOptions, feePayer models.Signer, instructions []types.Instruction, signers ...models.Signer) (string, error) {
+	payer, err := signerPublicKey(feePayer)
+	if err != nil {
+		return "", err
+	}
+	signers = append([]models.Signer{feePayer}, signers...)
+	if opts.Nonce != nil {
+		signers = append(signers, opts.Nonce.Authority)
+	}
+	if opts.Simulate != nil {
+		tx, _, err := c.prepare(ctx, opts, payer, instructions)
+		if err != nil {
+			return "", err
+		}
+		return "", c.simulateInto(ctx, opts, tx)
+	}
+	return c.deliver(ctx, opts, func(ctx context.Context) (types.Transaction, uint64, error) {
+		tx, lastValidBlockHeight, err := c.prepare(ctx, opts, payer, instructions)
+		if err != nil {
+			return types.Transaction{}, 0, err
+		}
+		if err := signWith(ctx, &tx, signers...); err != nil {
+			return types.Transaction{}, 0, err
+		}
+		return tx, lastValidBlockHeight, nil
+	}, true)
+}
+
+// run signs and submits an operation
+func (c *Client) run(ctx context.Context, op operation) (string, error) {
+	return c.sendInstructions(ctx, op.opts, op.feePayer, op.instructions, append(op.signers, op.generated...)...)
+}
+
+// build prepares an operation without the request's signatures
+func (c *Client) build(ctx context.Context, op operation) (*models.PreparedTransaction, error) {
+	payer, err := signerPublicKey(op.feePayer)
+	if err != nil {
+		return nil, err
+	}
+	tx, lastValidBlockHeight, err := c.prepare(ctx, op.opts, payer, op.instructions)
+	if err != nil {
+		return nil, err
+	}
+	if err := signWith(ctx, &tx, op.generated...); err != nil {
+		return nil, err
+	}
+	return preparedTransaction(tx, lastValidBlockHeight)
+}
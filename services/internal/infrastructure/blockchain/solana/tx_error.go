@@ -0,0 +1,256 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blocto/solana-go-sdk/common"
+	"github.com/blocto/solana-go-sdk/types"
+	models "github.com/whiteelite/superapp/internal/infrastructure/blockchain/solana/models"
+)
+
+// programError names a custom error code of a program
+type programError struct {
+	name    string
+	message string
+}
+
+// builtinErrors describes the runtime's transaction and instruction errors
+var builtinErrors = map[string]string{
+	"AccountNotFound":                "fee payer account not found",
+	"InsufficientFundsForFee":        "insufficient funds for fee",
+	"InsufficientFundsForRent":       "account would not be rent exempt",
+	"BlockhashNotFound":              "blockhash not found or expired",
+	"AlreadyProcessed":               "transaction already processed",
+	"AccountInUse":                   "account in use by a concurrent transaction",
+	"InvalidAccountData":             "invalid account data",
+	"UninitializedAccount":           "account is not initialized",
+	"AccountAlreadyInitialized":      "account already initialized",
+	"InsufficientFunds":              "insufficient funds",
+	"MissingRequiredSignature":       "missing required signature",
+	"IncorrectProgramId":             "incorrect program id",
+	"IllegalOwner":                   "account owned by the wrong program",
+	"InvalidAccountOwner":            "account is not owned by the program",
+	"InvalidArgument":                "invalid argument",
+	"InvalidInstructionData":         "invalid instruction data",
+	"InvalidSeeds":                   "address does not match its seeds",
+	"NotEnoughAccountKeys":           "not enough account keys",
+	"ReadonlyDataModified":           "read-only account modified",
+	"ComputationalBudgetExceeded":    "compute budget exceeded",
+	"UnsupportedProgramId":           "unsupported program",
+	"AddressLookupTableNotFound":     "address lookup table not found",
+	"InvalidAddressLookupTableIndex": "address lookup table index out of range",
+}
+
+var systemErrors = map[uint32]programError{
+	0: {"AccountAlreadyInUse", "account already in use"},
+	1: {"ResultWithNegativeLamports", "insufficient lamports"},
+	2: {"InvalidProgramId", "cannot assign account to this program id"},
+	3: {"InvalidAccountDataLength", "cannot allocate account data of this length"},
+	4: {"MaxSeedLengthExceeded", "seed too long"},
+	5: {"AddressWithSeedMismatch", "address does not match its seed"},
+	6: {"NonceNoRecentBlockhashes", "no recent blockhashes for the nonce"},
+	7: {"NonceBlockhashNotExpired", "nonce blockhash has not expired"},
+	8: {"NonceUnexpectedBlockhashValue", "unexpected nonce value"},
+}
+
+// tokenErrors are shared by the Token and Token-2022 programs
+var tokenErrors = map[uint32]programError{
+	0:  {"NotRentExempt", "lamport balance below rent-exempt threshold"},
+	1:  {"InsufficientFunds", "insufficient token funds"},
+	2:  {"InvalidMint", "invalid mint"},
+	3:  {"MintMismatch", "account not associated with this mint"},
+	4:  {"OwnerMismatch", "owner does not match"},
+	5:  {"FixedSupply", "fixed supply"},
+	6:  {"AlreadyInUse", "account already in use"},
+	7:  {"InvalidNumberOfProvidedSigners", "invalid number of provided signers"},
+	8:  {"InvalidNumberOfRequiredSigners", "invalid number of required signers"},
+	9:  {"UninitializedState", "token account is not initialized"},
+	10: {"NativeNotSupported", "instruction does not support native tokens"},
+	11: {"NonNativeHasBalance", "non-native account can only be closed if its balance is zero"},
+	12: {"InvalidInstruction", "invalid instruction"},
+	13: {"InvalidState", "state is invalid for requested operation"},
+	14: {"Overflow", "operation overflowed"},
+	15: {"AuthorityTypeNotSupported", "account does not support specified authority type"},
+	16: {"MintCannotFreeze", "this token mint cannot freeze accounts"},
+	17: {"AccountFrozen", "account is frozen"},
+	18: {"MintDecimalsMismatch", "the provided decimals value different from the mint decimals"},
+	19: {"NonNativeNotSupported", "instruction does not support non-native tokens"},
+}
+
+// associatedTokenErrors are raised by the System program the ATA program invokes to create the
+// account; its own InvalidOwner (0) is only raised by RecoverNested
+var associatedTokenErrors = map[uint32]programError{
+	0: systemErrors[0],
+	1: systemErrors[1],
+}
+
+var stakeErrors = map[uint32]programError{
+	0:  {"NoCreditsToRedeem", "not enough credits to redeem"},
+	1:  {"LockupInForce", "lockup has not yet expired"},
+	2:  {"AlreadyDeactivated", "stake already deactivated"},
+	3:  {"TooSoonToRedelegate", "one re-delegation permitted per epoch"},
+	4:  {"InsufficientStake", "split amount is more than is staked"},
+	5:  {"MergeTransientStake", "stake account with transient stake cannot be merged"},
+	6:  {"MergeMismatch", "stake account merge failed due to different authority, lockups or state"},
+	7:  {"CustodianMissing", "custodian address not present"},
+	8:  {"CustodianSignatureMissing", "custodian signature not present"},
+	9:  {"InsufficientReferenceVotes", "insufficient voting activity in the reference vote account"},
+	10: {"VoteAddressMismatch", "stake account is not delegated to the provided vote account"},
+	11: {"MinimumDelinquentEpochsForDeactivationNotMet", "vote account has not been delinquent long enough"},
+	12: {"InsufficientDelegation", "delegation amount is less than the minimum"},
+	13: {"RedelegateTransientOrInactiveStake", "stake account with transient or inactive stake cannot be redelegated"},
+	14: {"RedelegateToSameVoteAccount", "stake redelegation to the same vote account is not permitted"},
+	15: {"RedelegatedStakeMustFullyActivateBeforeDeactivationIsPermitted", "redelegated stake must be fully activated before deactivation"},
+	16: {"EpochRewardsActive", "stake action is not permitted while the epoch rewards period is active"},
+}
+
+var tokenMetadataErrors = map[uint32]programError{
+	0:  {"InstructionUnpackError", "failed to unpack instruction data"},
+	1:  {"InstructionPackError", "failed to pack instruction data"},
+	2:  {"NotRentExempt", "lamport balance below rent-exempt threshold"},
+	3:  {"AlreadyInitialized", "already initialized"},
+	4:  {"Uninitialized", "uninitialized"},
+	5:  {"InvalidMetadataKey", "metadata address does not match its seeds"},
+	6:  {"InvalidEditionKey", "edition address does not match its seeds"},
+	7:  {"UpdateAuthorityIncorrect", "update authority given does not match"},
+	8:  {"UpdateAuthorityIsNotSigner", "update authority needs to sign to update metadata"},
+	9:  {"NotMintAuthority", "the mint authority must sign this transaction"},
+	10: {"InvalidMintAuthority", "mint authority does not match the authority on the mint"},
+	11: {"NameTooLong", "name too long"},
+	12: {"SymbolTooLong", "symbol too long"},
+	13: {"UriTooLong", "URI too long"},
+	14: {"UpdateAuthorityMustBeEqualToMetadataAuthorityAndSigner", "update authority must equal the metadata authority and sign"},
+	15: {"MintMismatch", "mint does not match the mint of the metadata"},
+	16: {"EditionsMustHaveExactlyOneToken", "editions must have exactly one token"},
+	17: {"MaxEditionsMintedAlready", "maximum editions printed already"},
+	18: {"TokenMintToFailed", "token mint to failed"},
+	19: {"MasterRecordMismatch", "the master edition record does not match"},
+	20: {"DestinationMintMismatch", "the destination account does not have the right mint"},
+	21: {"EditionAlreadyMinted", "an edition can only mint one of its kind"},
+	22: {"PrintingMintDecimalsShouldBeZero", "printing mint decimals should be zero"},
+	23: {"OneTimePrintingAuthorizationMintDecimalsShouldBeZero", "one time printing authorization mint decimals should be zero"},
+	24: {"EditionMintDecimalsShouldBeZero", "edition mint decimals should be zero"},
+}
+
+// programErrors maps a program to the names of its custom error codes
+var programErrors = map[common.PublicKey]map[uint32]programError{
+	common.SystemProgramID:                    systemErrors,
+	common.TokenProgramID:                     tokenErrors,
+	common.Token2022ProgramID:                 tokenErrors,
+	common.SPLAssociatedTokenAccountProgramID: associatedTokenErrors,
+	tokenMetadataProgramID:                    tokenMetadataErrors,
+	common.StakeProgramID:                     stakeErrors,
+}
+
+// decodeTransactionError decodes a transaction error as reported by the cluster: a name such as
+// "BlockhashNotFound", or {"InstructionError": [index, error]} where error is a name or
+// {"Custom": code}. keys are the accounts of the message, static and loaded, and may be empty
+// when the transaction is unknown. A custom code is looked up in the program the logs report
+// failing first, which is the invoked one when the error came from a cross-program invocation.
+func decodeTransactionError(txErr any, instructions []types.CompiledInstruction, keys []common.PublicKey, logs []string) *models.TransactionError {
+	if txErr == nil {
+		return nil
+	}
+	// the fee payer is the account transaction level errors are about
+	var feePayer string
+	if len(keys) > 0 {
+		feePayer = keys[0].ToBase58()
+	}
+	switch v := txErr.(type) {
+	case string:
+		return &models.TransactionError{Instruction: -1, Account: feePayer, Name: v, Message: builtinMessage(v)}
+	case map[string]any:
+		ie, ok := v["InstructionError"].([]any)
+		if !ok || len(ie) != 2 {
+			for name := range v {
+				return &models.TransactionError{Instruction: -1, Account: feePayer, Name: name, Message: builtinMessage(name)}
+			}
+			break
+		}
+		index, ok := jsonInt(ie[0])
+		if !ok {
+			break
+		}
+		e := &models.TransactionError{Instruction: index}
+		var program common.PublicKey
+		if index >= 0 && index < len(instructions) {
+			inst := instructions[index]
+			if inst.ProgramIDIndex < len(keys) {
+				program = keys[inst.ProgramIDIndex]
+				e.Program = program.ToBase58()
+			}
+			// most program errors are about the first account: the source of a transfer, the funding account
+			if len(inst.Accounts) > 0 && inst.Accounts[0] < len(keys) {
+				e.Account = keys[inst.Accounts[0]].ToBase58()
+			}
+		}
+		switch cause := ie[1].(type) {
+		case string:
+			e.Name, e.Message = cause, builtinMessage(cause)
+		case map[string]any:
+			if code, ok := jsonInt(cause["Custom"]); ok {
+				c := uint32(code)
+				e.Code = &c
+				if failed, ok := failedProgram(logs); ok {
+					program = failed
+					e.Program = program.ToBase58()
+				}
+				if pe, ok := programErrors[program][c]; ok {
+					e.Name, e.Message = pe.name, pe.message
+				} else {
+					e.Name, e.Message = "Custom", fmt.Sprintf("custom program error: 0x%x", c)
+				}
+			} else {
+				for name := range cause {
+					e.Name, e.Message = name, builtinMessage(name)
+				}
+			}
+		}
+		return e
+	}
+	return &models.TransactionError{Instruction: -1, Account: feePayer, Message: fmt.Sprint(txErr)}
+}
+
+// failedProgram returns the program of the first "Program <id> failed: custom program error" log
+func failedProgram(logs []string) (common.PublicKey, bool) {
+	for _, line := range logs {
+		rest, ok := strings.CutPrefix(line, "Program ")
+		if !ok {
+			continue
+		}
+		id, reason, ok := strings.Cut(rest, " failed: ")
+		if !ok || !strings.HasPrefix(reason, "custom program error") {
+			continue
+		}
+		program, err := publicKey(id)
+		if err != nil {
+			return common.PublicKey{}, false
+		}
+		return program, true
+	}
+	return common.PublicKey{}, false
+}
+
+func builtinMessage(name string) string {
+	if msg, ok := builtinErrors[name]; ok {
+		return msg
+	}
+	return name
+}
+
+// jsonInt reads a number decoded from JSON into an interface
+func jsonInt(v any) (int, bool) {
+	switch n := v.(type) {
+	case float64:
+		return int(n), true
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}